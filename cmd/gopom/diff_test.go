@@ -0,0 +1,39 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_UnifiedDiff(t *testing.T) {
+	assert.Empty(t, unifiedDiff("pom.xml", "a\n", "a\n"))
+
+	a := "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n15\n16\n"
+	b := "1\n2\nthree\n4\n5\n6\n7\n8\n9\n10\n11\n12\n13\n14\n16\n17"
+	assert.Equal(t, `--- a/pom.xml
++++ b/pom.xml
+@@ -1,6 +1,6 @@
+ 1
+ 2
+-3
++three
+ 4
+ 5
+ 6
+@@ -12,5 +12,5 @@
+ 12
+ 13
+ 14
+-15
+ 16
++17
+\ No newline at end of file
+`, unifiedDiff("pom.xml", a, b))
+
+	assert.Equal(t, `--- a/pom.xml
++++ b/pom.xml
+@@ -0,0 +1 @@
++a
+`, unifiedDiff("pom.xml", "", "a\n"))
+}
@@ -0,0 +1,455 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vifraa/gopom"
+)
+
+// pomFile is a pom of the reactor being edited.
+type pomFile struct {
+	path    string
+	root    bool
+	src     string
+	doc     *gopom.Document
+	project *gopom.Project
+}
+
+// editOptions are the flags shared by the edit commands.
+type editOptions struct {
+	recursive bool
+	dryRun    bool
+}
+
+func (env *environment) editFlags(name string, opts *editOptions) *flag.FlagSet {
+	fs := flag.NewFlagSet("gopom "+name, flag.ContinueOnError)
+	fs.SetOutput(env.stderr)
+	fs.StringVar(&env.file, "f", "pom.xml", "pom file to edit, - to edit standard input and write the result to standard output")
+	fs.BoolVar(&opts.recursive, "r", false, "also edit the modules of the reactor, recursively")
+	fs.BoolVar(&opts.dryRun, "dry-run", false, "print the changes as a unified diff instead of writing them")
+	return fs
+}
+
+// poms reads the pom selected by -f and, with -r, its modules recursively.
+func (env *environment) poms(recursive bool) ([]*pomFile, error) {
+	if env.file == "-" {
+		if recursive {
+			return nil, fmt.Errorf("-r cannot be used with standard input")
+		}
+		src, err := ioutil.ReadAll(env.stdin)
+		if err != nil {
+			return nil, err
+		}
+		f, err := newPomFile("-", src)
+		if err != nil {
+			return nil, err
+		}
+		f.root = true
+		return []*pomFile{f}, nil
+	}
+
+	var files []*pomFile
+	seen := map[string]bool{}
+	var read func(path string) error
+	read = func(path string) error {
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			path = filepath.Join(path, "pom.xml")
+		}
+		path = filepath.Clean(path)
+		if seen[path] {
+			return nil
+		}
+		seen[path] = true
+		src, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		f, err := newPomFile(path, src)
+		if err != nil {
+			return err
+		}
+		f.root = len(files) == 0
+		files = append(files, f)
+		if !recursive {
+			return nil
+		}
+		for _, modules := range []*[]string{f.project.Modules, f.project.Subprojects} {
+			if modules == nil {
+				continue
+			}
+			for _, m := range *modules {
+				if err := read(filepath.Join(filepath.Dir(path), filepath.FromSlash(m))); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := read(env.file); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+func newPomFile(path string, src []byte) (*pomFile, error) {
+	doc, err := gopom.ParseDocument(src)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	p, err := doc.Project()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return &pomFile{path: path, src: string(src), doc: doc, project: p}, nil
+}
+
+// apply runs edit on every pom and writes the changed ones, or prints their
+// diff with -dry-run. As an edit may change another pom of the reactor,
+// nothing is written before every pom is edited. It returns errNotFound when
+// no pom changed.
+func (env *environment) apply(files []*pomFile, opts editOptions, edit func(f *pomFile) error) error {
+	for _, f := range files {
+		if err := edit(f); err != nil {
+			return fmt.Errorf("%s: %v", f.path, err)
+		}
+	}
+	changed := false
+	for _, f := range files {
+		result := string(f.doc.Bytes())
+		if result == f.src {
+			continue
+		}
+		changed = true
+		switch {
+		case opts.dryRun:
+			name := f.path
+			if name == "-" {
+				name = "pom.xml"
+			}
+			fmt.Fprint(env.stdout, unifiedDiff(filepath.ToSlash(name), f.src, result))
+		case f.path == "-":
+			fmt.Fprint(env.stdout, result)
+		default:
+			info, err := os.Stat(f.path)
+			if err != nil {
+				return err
+			}
+			if err := ioutil.WriteFile(f.path, []byte(result), info.Mode()); err != nil {
+				return err
+			}
+		}
+	}
+	if !changed {
+		if len(files) == 1 && files[0].path == "-" && !opts.dryRun {
+			fmt.Fprint(env.stdout, files[0].src)
+		}
+		return errNotFound
+	}
+	return nil
+}
+
+// setValue sets the text at path in f, or the property it refers to when it
+// is a single ${property} expression. Like set-property, the property is set
+// where it is declared, in f or else in the first of files declaring it.
+func setValue(files []*pomFile, f *pomFile, value string, path ...string) error {
+	current, err := f.doc.Text(path...)
+	if err == nil {
+		current = strings.TrimSpace(current)
+		if strings.HasPrefix(current, "${") && strings.HasSuffix(current, "}") {
+			property := current[2 : len(current)-1]
+			for _, declaring := range append([]*pomFile{f}, files...) {
+				if declaring.doc.Has("properties", property) {
+					return declaring.doc.SetText(value, "properties", property)
+				}
+			}
+		}
+	}
+	return f.doc.SetText(value, path...)
+}
+
+// dependencyPaths returns the paths of the dependencies and managed
+// dependencies of the pom matching key, last first so that they can be removed
+// in order.
+func dependencyPaths(f *pomFile, matches func(c gopom.Coordinates) bool) [][]string {
+	var paths [][]string
+	add := func(dependencies *[]gopom.Dependency, prefix ...string) {
+		if dependencies == nil {
+			return
+		}
+		for i := len(*dependencies) - 1; i >= 0; i-- {
+			c := (*dependencies)[i].Coordinates()
+			c.GroupID = f.project.Interpolate(c.GroupID)
+			c.ArtifactID = f.project.Interpolate(c.ArtifactID)
+			if matches(c) {
+				paths = append(paths, append(append([]string(nil), prefix...), fmt.Sprintf("dependency[%d]", i)))
+			}
+		}
+	}
+	add(f.project.Dependencies, "dependencies")
+	if f.project.DependencyManagement != nil {
+		add(f.project.DependencyManagement.Dependencies, "dependencyManagement", "dependencies")
+	}
+	return paths
+}
+
+func runSetVersion(env *environment, args []string) error {
+	var opts editOptions
+	fs := env.editFlags("set-version", &opts)
+	if err := parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+	version := fs.Arg(0)
+	files, err := env.poms(opts.recursive)
+	if err != nil {
+		return err
+	}
+
+	// With -r, references to the projects of the reactor move along.
+	reactor := map[gopom.ArtifactKey]bool{}
+	old := files[0].project.Coordinates().Version
+	if opts.recursive {
+		for _, f := range files {
+			reactor[f.project.Coordinates().Key()] = true
+		}
+	}
+	return env.apply(files, opts, func(f *pomFile) error {
+		if f.root || f.doc.Has("version") {
+			if err := setValue(files, f, version, "version"); err != nil {
+				return err
+			}
+		}
+		if f.project.Parent != nil && reactor[f.project.Parent.Coordinates().Key()] {
+			if err := setValue(files, f, version, "parent", "version"); err != nil {
+				return err
+			}
+		}
+		for _, path := range dependencyPaths(f, func(c gopom.Coordinates) bool { return reactor[c.Key()] }) {
+			if current, err := f.doc.Text(append(path, "version")...); err == nil && strings.TrimSpace(current) == old {
+				if err := setValue(files, f, version, append(path, "version")...); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+func runSetProperty(env *environment, args []string) error {
+	var opts editOptions
+	fs := env.editFlags("set-property", &opts)
+	if err := parseFlags(fs, args, 2); err != nil {
+		return err
+	}
+	name, value := fs.Arg(0), fs.Arg(1)
+	files, err := env.poms(opts.recursive)
+	if err != nil {
+		return err
+	}
+
+	// With -r, the property is set where it is declared, or in the root pom
+	// when no pom declares it.
+	declared := false
+	for _, f := range files {
+		declared = declared || f.doc.Has("properties", name)
+	}
+	return env.apply(files, opts, func(f *pomFile) error {
+		if f.doc.Has("properties", name) || f.root && !declared {
+			return f.doc.SetText(value, "properties", name)
+		}
+		return nil
+	})
+}
+
+func runAddDependency(env *environment, args []string) error {
+	var opts editOptions
+	fs := env.editFlags("add-dependency", &opts)
+	if err := parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+	parts := strings.Split(fs.Arg(0), ":")
+	if len(parts) < 3 || len(parts) > 4 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return fmt.Errorf("invalid dependency %q, expected groupId:artifactId:version[:scope]", fs.Arg(0))
+	}
+	dependency := gopom.Dependency{GroupID: &parts[0], ArtifactID: &parts[1], Version: &parts[2]}
+	if len(parts) == 4 {
+		dependency.Scope = &parts[3]
+	}
+	key := dependency.Coordinates().Key()
+	files, err := env.poms(opts.recursive)
+	if err != nil {
+		return err
+	}
+
+	// With -r, the dependency is added to every module that builds an artifact.
+	return env.apply(files, opts, func(f *pomFile) error {
+		if opts.recursive && f.project.Packaging != nil && *f.project.Packaging == "pom" {
+			return nil
+		}
+		var existing [][]string
+		for _, path := range dependencyPaths(f, func(c gopom.Coordinates) bool { return c.Key() == key }) {
+			if path[0] == "dependencies" {
+				existing = append(existing, path)
+			}
+		}
+		if len(existing) == 0 {
+			return f.doc.Append(dependency, "dependency", "dependencies")
+		}
+		for _, path := range existing {
+			// A version left to dependencyManagement is updated where it is managed.
+			if err := setDependencyVersion(files, f, key, *dependency.Version, path); err != nil {
+				return err
+			}
+			if dependency.Scope != nil {
+				if err := f.doc.SetText(*dependency.Scope, append(path, "scope")...); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// setDependencyVersion sets the version of the dependency at path, or the one
+// of its managed entry in the reactor when it has none. Dependencies managed
+// outside of the reactor are left alone.
+func setDependencyVersion(files []*pomFile, f *pomFile, key gopom.ArtifactKey, version string, path []string) error {
+	if f.doc.Has(append(path, "version")...) {
+		return setValue(files, f, version, append(path, "version")...)
+	}
+	for _, managing := range append([]*pomFile{f}, files...) {
+		for _, managed := range dependencyPaths(managing, func(c gopom.Coordinates) bool { return c.Key() == key }) {
+			if managed[0] == "dependencyManagement" && managing.doc.Has(append(managed, "version")...) {
+				return setValue(files, managing, version, append(managed, "version")...)
+			}
+		}
+	}
+	return nil
+}
+
+func runRemoveDependency(env *environment, args []string) error {
+	var opts editOptions
+	fs := env.editFlags("remove-dependency", &opts)
+	if err := parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+	parts := strings.Split(fs.Arg(0), ":")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("invalid dependency %q, expected groupId:artifactId", fs.Arg(0))
+	}
+	key := gopom.ArtifactKey{GroupID: parts[0], ArtifactID: parts[1]}
+	files, err := env.poms(opts.recursive)
+	if err != nil {
+		return err
+	}
+	return env.apply(files, opts, func(f *pomFile) error {
+		for _, path := range dependencyPaths(f, func(c gopom.Coordinates) bool { return c.Key() == key }) {
+			if err := f.doc.Remove(path...); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func runBumpPlugin(env *environment, args []string) error {
+	var opts editOptions
+	fs := env.editFlags("bump-plugin", &opts)
+	if err := parseFlags(fs, args, 2); err != nil {
+		return err
+	}
+	plugin, version := fs.Arg(0), fs.Arg(1)
+	if !strings.Contains(plugin, ":") {
+		plugin = "*:" + plugin
+	}
+	files, err := env.poms(opts.recursive)
+	if err != nil {
+		return err
+	}
+
+	// Versions are only raised. When no pom gives the plugin a version, the
+	// first declaration in each pom gets one.
+	versioned := false
+	for _, f := range files {
+		for _, path := range pluginPaths(f, plugin) {
+			versioned = versioned || f.doc.Has(append(path, "version")...)
+		}
+	}
+	return env.apply(files, opts, func(f *pomFile) error {
+		declarations := pluginPaths(f, plugin)
+		if !versioned && len(declarations) > 0 {
+			return f.doc.SetText(version, append(declarations[0], "version")...)
+		}
+		for _, path := range declarations {
+			current, err := f.doc.Text(append(path, "version")...)
+			if err != nil {
+				continue
+			}
+			if gopom.CompareVersions(f.project.Interpolate(strings.TrimSpace(current)), version) < 0 {
+				if err := setValue(files, f, version, append(path, "version")...); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// pluginPaths returns the paths of the build plugins and managed plugins of
+// the pom matching pattern.
+func pluginPaths(f *pomFile, pattern string) [][]string {
+	build := f.project.Build
+	if build == nil {
+		return nil
+	}
+	var paths [][]string
+	add := func(plugins *[]gopom.Plugin, prefix ...string) {
+		if plugins == nil {
+			return
+		}
+		for i, p := range *plugins {
+			if p.Coordinates().Matches(pattern) {
+				paths = append(paths, append(append([]string(nil), prefix...), fmt.Sprintf("plugin[%d]", i)))
+			}
+		}
+	}
+	add(build.Plugins, "build", "plugins")
+	if build.PluginManagement != nil {
+		add(build.PluginManagement.Plugins, "build", "pluginManagement", "plugins")
+	}
+	return paths
+}
+
+func runAddModule(env *environment, args []string) error {
+	var opts editOptions
+	fs := env.editFlags("add-module", &opts)
+	if err := parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+	if opts.recursive {
+		return fmt.Errorf("-r cannot be used with add-module")
+	}
+	module := fs.Arg(0)
+	files, err := env.poms(false)
+	if err != nil {
+		return err
+	}
+	return env.apply(files, opts, func(f *pomFile) error {
+		element, list := "modules", f.project.Modules
+		if f.doc.Has("subprojects") {
+			element, list = "subprojects", f.project.Subprojects
+		}
+		if list != nil {
+			for _, m := range *list {
+				if m == module {
+					return nil
+				}
+			}
+		}
+		return f.doc.Append(module, strings.TrimSuffix(element, "s"), element)
+	})
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const editParentPom = `<project>
+  <groupId>org.acme</groupId>
+  <artifactId>parent</artifactId>
+  <version>1.0</version>
+  <packaging>pom</packaging>
+  <properties>
+    <junit.version>4.13.1</junit.version>
+  </properties>
+  <modules>
+    <module>lib</module>
+  </modules>
+  <dependencyManagement>
+    <dependencies>
+      <dependency>
+        <groupId>org.acme</groupId>
+        <artifactId>lib</artifactId>
+        <version>1.0</version>
+      </dependency>
+      <dependency>
+        <groupId>junit</groupId>
+        <artifactId>junit</artifactId>
+        <version>${junit.version}</version>
+      </dependency>
+    </dependencies>
+  </dependencyManagement>
+  <build>
+    <pluginManagement>
+      <plugins>
+        <plugin>
+          <artifactId>maven-compiler-plugin</artifactId>
+          <version>3.8.1</version>
+        </plugin>
+      </plugins>
+    </pluginManagement>
+  </build>
+</project>
+`
+
+const editLibPom = `<project>
+  <parent>
+    <groupId>org.acme</groupId>
+    <artifactId>parent</artifactId>
+    <version>1.0</version>
+  </parent>
+  <artifactId>lib</artifactId>
+  <dependencies>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+    </dependency>
+  </dependencies>
+  <build>
+    <plugins>
+      <plugin>
+        <groupId>org.apache.maven.plugins</groupId>
+        <artifactId>maven-compiler-plugin</artifactId>
+      </plugin>
+    </plugins>
+  </build>
+</project>
+`
+
+// writeEditReactor writes a parent pom with a lib module and returns the
+// paths of both poms.
+func writeEditReactor(t *testing.T) (string, string) {
+	dir := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "lib"), 0755))
+	parent, lib := filepath.Join(dir, "pom.xml"), filepath.Join(dir, "lib", "pom.xml")
+	assert.Nil(t, ioutil.WriteFile(parent, []byte(editParentPom), 0644))
+	assert.Nil(t, ioutil.WriteFile(lib, []byte(editLibPom), 0644))
+	return parent, lib
+}
+
+func readFile(t *testing.T, path string) string {
+	b, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	return string(b)
+}
+
+func Test_SetVersion(t *testing.T) {
+	parent, lib := writeEditReactor(t)
+	code, _, _ := runCommand("", "set-version", "-f", parent, "-r", "1.1-SNAPSHOT")
+	assert.Equal(t, exitOK, code)
+
+	parentText := readFile(t, parent)
+	assert.Contains(t, parentText, "  <version>1.1-SNAPSHOT</version>\n  <packaging>pom</packaging>")
+	assert.Contains(t, parentText, "<artifactId>lib</artifactId>\n        <version>1.1-SNAPSHOT</version>")
+	assert.Equal(t, editLibPom[:len("<project>\n  <parent>\n    <groupId>org.acme</groupId>\n    <artifactId>parent</artifactId>\n")]+
+		"    <version>1.1-SNAPSHOT</version>\n"+
+		editLibPom[len("<project>\n  <parent>\n    <groupId>org.acme</groupId>\n    <artifactId>parent</artifactId>\n    <version>1.0</version>\n"):],
+		readFile(t, lib))
+
+	code, _, _ = runCommand("", "set-version", "-f", parent, "-r", "1.1-SNAPSHOT")
+	assert.Equal(t, exitNotFound, code)
+}
+
+func Test_SetVersionRevision(t *testing.T) {
+	parent, lib := writeEditReactor(t)
+	parentPom := strings.Replace(editParentPom, "<version>1.0</version>", "<version>${revision}</version>", -1)
+	parentPom = strings.Replace(parentPom, "<properties>\n", "<properties>\n    <revision>1.0</revision>\n", 1)
+	libPom := strings.Replace(editLibPom, "<version>1.0</version>", "<version>${revision}</version>", 1)
+	assert.Nil(t, ioutil.WriteFile(parent, []byte(parentPom), 0644))
+	assert.Nil(t, ioutil.WriteFile(lib, []byte(libPom), 0644))
+
+	code, _, _ := runCommand("", "set-version", "-f", parent, "-r", "1.1")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, strings.Replace(parentPom, "<revision>1.0</revision>", "<revision>1.1</revision>", 1), readFile(t, parent))
+	assert.Equal(t, libPom, readFile(t, lib))
+}
+
+func Test_SetVersionDryRun(t *testing.T) {
+	parent, lib := writeEditReactor(t)
+	code, out, _ := runCommand("", "set-version", "-f", lib, "-dry-run", "2.0")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, `--- a/`+filepath.ToSlash(lib)+`
++++ b/`+filepath.ToSlash(lib)+`
+@@ -19,4 +19,5 @@
+       </plugin>
+     </plugins>
+   </build>
++  <version>2.0</version>
+ </project>
+`, out)
+	assert.Equal(t, editLibPom, readFile(t, lib))
+	assert.Equal(t, editParentPom, readFile(t, parent))
+}
+
+func Test_SetProperty(t *testing.T) {
+	parent, lib := writeEditReactor(t)
+	code, _, _ := runCommand("", "set-property", "-f", parent, "-r", "junit.version", "4.13.2")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, readFile(t, parent), "<junit.version>4.13.2</junit.version>")
+	assert.Equal(t, editLibPom, readFile(t, lib))
+
+	code, out, _ := runCommand(editLibPom, "set-property", "-f", "-", "java.version", "11")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, out, "</build>\n  <properties>\n    <java.version>11</java.version>\n  </properties>\n</project>\n")
+}
+
+func Test_AddDependency(t *testing.T) {
+	parent, lib := writeEditReactor(t)
+	code, _, _ := runCommand("", "add-dependency", "-f", parent, "-r", "org.slf4j:slf4j-api:2.0.9")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, editParentPom, readFile(t, parent))
+	assert.Contains(t, readFile(t, lib), `      <artifactId>junit</artifactId>
+    </dependency>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+      <version>2.0.9</version>
+    </dependency>
+  </dependencies>`)
+
+	// The version of junit is managed by the parent, which is not edited.
+	code, _, _ = runCommand("", "add-dependency", "-f", lib, "junit:junit:4.13.2:test")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, readFile(t, lib), `      <artifactId>junit</artifactId>
+      <scope>test</scope>
+    </dependency>`)
+	assert.NotContains(t, readFile(t, lib), "4.13.2")
+
+	code, _, _ = runCommand("", "add-dependency", "-f", parent, "-r", "junit:junit:4.13.2")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, strings.Replace(editParentPom, "<junit.version>4.13.1<", "<junit.version>4.13.2<", 1), readFile(t, parent))
+	assert.NotContains(t, readFile(t, lib), "4.13.2")
+
+	code, _, errOut := runCommand("", "add-dependency", "-f", lib, "junit:junit")
+	assert.Equal(t, exitError, code)
+	assert.Equal(t, "gopom add-dependency: invalid dependency \"junit:junit\", expected groupId:artifactId:version[:scope]\n", errOut)
+}
+
+func Test_AddDependencyUpdatesProperty(t *testing.T) {
+	pom := `<project>
+  <properties>
+    <guava.version>31.0-jre</guava.version>
+  </properties>
+  <dependencies>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+      <version>${guava.version}</version>
+    </dependency>
+  </dependencies>
+</project>
+`
+	code, out, _ := runCommand(pom, "add-dependency", "-f", "-", "com.google.guava:guava:32.1.3-jre")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, out, "<guava.version>32.1.3-jre</guava.version>")
+	assert.Contains(t, out, "<version>${guava.version}</version>")
+}
+
+func Test_AddDependencyUpdatesReactorProperty(t *testing.T) {
+	parent, lib := writeEditReactor(t)
+	parentPom := strings.Replace(editParentPom, "<junit.version>4.13.1</junit.version>",
+		"<junit.version>4.13.1</junit.version>\n    <guava.version>31.0-jre</guava.version>", 1)
+	libPom := strings.Replace(editLibPom, "<artifactId>junit</artifactId>",
+		"<artifactId>junit</artifactId>\n    </dependency>\n    <dependency>\n      <groupId>com.google.guava</groupId>\n      <artifactId>guava</artifactId>\n      <version>${guava.version}</version>", 1)
+	assert.Nil(t, ioutil.WriteFile(parent, []byte(parentPom), 0644))
+	assert.Nil(t, ioutil.WriteFile(lib, []byte(libPom), 0644))
+
+	code, _, _ := runCommand("", "add-dependency", "-f", parent, "-r", "com.google.guava:guava:33.0-jre")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, strings.Replace(parentPom, "31.0-jre", "33.0-jre", 1), readFile(t, parent))
+	assert.Equal(t, libPom, readFile(t, lib))
+}
+
+func Test_RemoveDependency(t *testing.T) {
+	parent, lib := writeEditReactor(t)
+	code, _, _ := runCommand("", "remove-dependency", "-f", parent, "-r", "junit:junit")
+	assert.Equal(t, exitOK, code)
+	assert.NotContains(t, readFile(t, parent), "junit</artifactId>")
+	assert.Contains(t, readFile(t, lib), "  <dependencies>\n  </dependencies>\n")
+
+	code, _, _ = runCommand("", "remove-dependency", "-f", parent, "-r", "junit:junit")
+	assert.Equal(t, exitNotFound, code)
+}
+
+func Test_BumpPlugin(t *testing.T) {
+	parent, lib := writeEditReactor(t)
+	code, _, _ := runCommand("", "bump-plugin", "-f", parent, "-r", "maven-compiler-plugin", "3.11.0")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, readFile(t, parent), "<version>3.11.0</version>")
+	assert.Equal(t, editLibPom, readFile(t, lib))
+
+	code, _, _ = runCommand("", "bump-plugin", "-f", parent, "maven-compiler-plugin", "3.9.0")
+	assert.Equal(t, exitNotFound, code)
+	assert.Contains(t, readFile(t, parent), "<version>3.11.0</version>")
+
+	code, _, _ = runCommand("", "bump-plugin", "-f", lib, "org.apache.maven.plugins:maven-compiler-plugin", "3.11.0")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, readFile(t, lib), "<artifactId>maven-compiler-plugin</artifactId>\n        <version>3.11.0</version>\n      </plugin>")
+}
+
+func Test_AddModule(t *testing.T) {
+	parent, _ := writeEditReactor(t)
+	code, _, _ := runCommand("", "add-module", "-f", parent, "app")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, readFile(t, parent), "<modules>\n    <module>lib</module>\n    <module>app</module>\n  </modules>")
+
+	code, _, _ = runCommand("", "add-module", "-f", parent, "app")
+	assert.Equal(t, exitNotFound, code)
+
+	code, out, _ := runCommand("<project>\n  <subprojects/>\n</project>\n", "add-module", "-f", "-", "core")
+	assert.Equal(t, exitOK, code)
+	assert.Contains(t, out, "<subproject>core</subproject>")
+
+	code, _, errOut := runCommand("<project/>", "add-module", "-f", "-", "-r", "core")
+	assert.Equal(t, exitError, code)
+	assert.Equal(t, "gopom add-module: -r cannot be used with add-module\n", errOut)
+}
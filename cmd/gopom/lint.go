@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/vifraa/gopom"
+)
+
+func runLint(env *environment, args []string) error {
+	fs := env.flags("lint")
+	recursive := fs.Bool("r", false, "also lint the modules of the reactor, recursively")
+	configFile := fs.String("config", "", "JSON file setting the severity of rules")
+	disable := fs.String("disable", "", "comma-separated rules to disable")
+	format := fs.String("format", "text", "output format, text or sarif")
+	if err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	if *format != "text" && *format != "sarif" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	config := &gopom.LintConfig{}
+	if *configFile != "" {
+		f, err := os.Open(*configFile)
+		if err != nil {
+			return err
+		}
+		config, err = gopom.LoadLintConfig(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %v", *configFile, err)
+		}
+	}
+	if *disable != "" {
+		known := map[string]bool{}
+		for _, rule := range gopom.LintRules() {
+			known[rule.ID] = true
+		}
+		if config.Rules == nil {
+			config.Rules = map[string]gopom.LintSeverity{}
+		}
+		for _, id := range strings.Split(*disable, ",") {
+			id = strings.TrimSpace(id)
+			if !known[id] {
+				return fmt.Errorf("unknown lint rule %q", id)
+			}
+			config.Rules[id] = gopom.LintOff
+		}
+	}
+
+	files, err := env.poms(*recursive)
+	if err != nil {
+		return err
+	}
+	var findings gopom.LintFindings
+	for _, f := range files {
+		source, dir := f.path, filepath.Dir(f.path)
+		if f.path == "-" {
+			source, dir = "<stdin>", "."
+		}
+		p, err := gopom.ParseFromReader(strings.NewReader(f.src), gopom.WithSource(filepath.ToSlash(source)), gopom.WithLocations())
+		if err != nil {
+			return err
+		}
+		var loader gopom.PomLoader
+		if env.effective {
+			loader = newReactorLoader(p, dir, gopom.LocalRepository(env.repository))
+		}
+		found, err := config.Lint(p, loader)
+		if err != nil {
+			return fmt.Errorf("%s: %v", source, err)
+		}
+		findings = append(findings, found...)
+	}
+
+	if *format == "sarif" {
+		err = findings.WriteSARIF(env.stdout)
+	} else {
+		err = findings.WriteText(env.stdout)
+	}
+	if err != nil {
+		return err
+	}
+	if findings.Failed() {
+		return errFailed
+	}
+	return nil
+}
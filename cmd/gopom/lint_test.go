@@ -0,0 +1,46 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const insecurePom = `<project>
+  <groupId>org.acme</groupId>
+  <artifactId>app</artifactId>
+  <version>1.0</version>
+  <repositories>
+    <repository><id>acme</id><url>http://repo.acme.org</url></repository>
+  </repositories>
+</project>
+`
+
+func Test_Lint(t *testing.T) {
+	parent, _ := writeEditReactor(t)
+	code, out, _ := runCommand("", "lint", "-f", parent, "-r", "-effective", "-disable", "central-metadata")
+	assert.Equal(t, exitOK, code)
+	assert.Empty(t, out)
+
+	code, out, _ = runCommand(insecurePom, "lint", "-f", "-", "-disable", "central-metadata")
+	assert.Equal(t, exitNotFound, code)
+	assert.Equal(t, "<stdin>:6:30: error: repository http://repo.acme.org is accessed over http, use https (insecure-repository)\n", out)
+
+	code, out, _ = runCommand(insecurePom, "lint", "-f", "-", "-format", "sarif")
+	assert.Equal(t, exitNotFound, code)
+	assert.Equal(t, 6, strings.Count(out, `"ruleId": "central-metadata"`))
+	assert.Contains(t, out, `"uri": "<stdin>"`)
+
+	config := filepath.Join(t.TempDir(), "lint.json")
+	assert.Nil(t, ioutil.WriteFile(config, []byte(`{"rules": {"insecure-repository": "warning", "central-metadata": "off"}}`), 0644))
+	code, out, _ = runCommand(insecurePom, "lint", "-f", "-", "-config", config)
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "<stdin>:6:30: warning: repository http://repo.acme.org is accessed over http, use https (insecure-repository)\n", out)
+
+	code, _, errOut := runCommand(insecurePom, "lint", "-f", "-", "-disable", "no-such-rule")
+	assert.Equal(t, exitError, code)
+	assert.Equal(t, "gopom lint: unknown lint rule \"no-such-rule\"\n", errOut)
+}
@@ -0,0 +1,380 @@
+// Command gopom queries and edits Maven pom files from the command line.
+//
+// Usage:
+//
+//	gopom <command> [flags] [arguments]
+//
+// The commands are:
+//
+//	coordinates    print groupId:artifactId:version
+//	eval EXPR      print the values an expression selects, one per line
+//	modules        list the modules
+//	dependencies   list the dependencies as a table or JSON
+//	effective      print the effective pom
+//	lint           check the pom against the lint rules, as text or SARIF
+//
+// The edit commands change poms in place, keeping their formatting:
+//
+//	set-version VERSION               set the version, and with -r that of the modules
+//	set-property NAME VALUE           set a property
+//	add-dependency G:A:V[:SCOPE]      add a dependency or update its version and scope
+//	remove-dependency G:A             remove a dependency and its management
+//	bump-plugin [G:]A VERSION         raise the version of a plugin
+//	add-module NAME                   add a module
+//
+// With -r they also edit the modules of the reactor, recursively, and with
+// -dry-run they print a unified diff instead of writing the files.
+//
+// Every command reads pom.xml from the current directory, another file given
+// with -f, or standard input with -f -. The edit commands write the result of
+// editing standard input to standard output.
+//
+// The exit status is 0 on success, 1 when eval selects nothing, an edit
+// changes nothing or lint reports errors, and 2 when the command fails, for
+// example because the pom cannot be read.
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/vifraa/gopom"
+)
+
+const (
+	exitOK       = 0
+	exitNotFound = 1
+	exitError    = 2
+)
+
+// errNotFound makes a command exit with exitNotFound.
+var errNotFound = errors.New("not found")
+
+// errFailed makes a command exit with exitNotFound after reporting failures itself.
+var errFailed = errors.New("failed")
+
+type command struct {
+	usage string
+	run   func(env *environment, args []string) error
+}
+
+var commands = map[string]command{
+	"coordinates":  {"coordinates [-f file] [-effective]", runCoordinates},
+	"eval":         {"eval [-f file] [-effective] EXPR", runEval},
+	"modules":      {"modules [-f file]", runModules},
+	"dependencies": {"dependencies [-f file] [-effective] [-managed] [-format table|json]", runDependencies},
+	"effective":    {"effective [-f file] [-repository dir] [-format xml|json|yaml]", runEffective},
+	"lint":         {"lint [-f file] [-r] [-effective] [-repository dir] [-config file] [-disable rules] [-format text|sarif]", runLint},
+
+	"set-version":       {"set-version [-f file] [-r] [-dry-run] VERSION", runSetVersion},
+	"set-property":      {"set-property [-f file] [-r] [-dry-run] NAME VALUE", runSetProperty},
+	"add-dependency":    {"add-dependency [-f file] [-r] [-dry-run] groupId:artifactId:version[:scope]", runAddDependency},
+	"remove-dependency": {"remove-dependency [-f file] [-r] [-dry-run] groupId:artifactId", runRemoveDependency},
+	"bump-plugin":       {"bump-plugin [-f file] [-r] [-dry-run] [groupId:]artifactId VERSION", runBumpPlugin},
+	"add-module":        {"add-module [-f file] [-dry-run] NAME", runAddModule},
+}
+
+// environment holds the streams and the options shared by all commands.
+type environment struct {
+	stdin          io.Reader
+	stdout, stderr io.Writer
+	file           string
+	effective      bool
+	repository     string
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "help" {
+		usage(stderr)
+		return exitError
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "gopom: unknown command %q\n", args[0])
+		usage(stderr)
+		return exitError
+	}
+
+	env := &environment{stdin: stdin, stdout: stdout, stderr: stderr}
+	err := cmd.run(env, args[1:])
+	switch {
+	case err == nil:
+		return exitOK
+	case err == errNotFound, err == errFailed:
+		return exitNotFound
+	case err == flag.ErrHelp:
+		return exitError
+	}
+	fmt.Fprintf(stderr, "gopom %s: %v\n", args[0], err)
+	return exitError
+}
+
+func usage(w io.Writer) {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintln(w, "usage:")
+	for _, name := range names {
+		fmt.Fprintf(w, "  gopom %s\n", commands[name].usage)
+	}
+}
+
+// flags returns a flag set with the flags every command accepts.
+func (env *environment) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("gopom "+name, flag.ContinueOnError)
+	fs.SetOutput(env.stderr)
+	fs.StringVar(&env.file, "f", "pom.xml", "pom file to read, - for standard input")
+	fs.BoolVar(&env.effective, "effective", false, "use the effective pom, with parents and imported boms applied")
+	fs.StringVar(&env.repository, "repository", defaultRepository(), "local repository to load parents and boms from")
+	return fs
+}
+
+func defaultRepository() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".m2", "repository")
+}
+
+// project reads the pom selected by -f, made effective when -effective is set.
+func (env *environment) project() (*gopom.Project, error) {
+	var p *gopom.Project
+	var err error
+	dir := filepath.Dir(env.file)
+	if env.file == "-" {
+		p, err = gopom.ParseFromReader(env.stdin, gopom.WithSource("<stdin>"))
+		dir = "."
+	} else {
+		p, err = gopom.Parse(env.file)
+	}
+	if err != nil || !env.effective {
+		return p, err
+	}
+	return gopom.EffectiveProject(p, newReactorLoader(p, dir, gopom.LocalRepository(env.repository)))
+}
+
+// reactorLoader loads the parents found by their relative path before
+// falling back to the local repository, as Maven does in a reactor build.
+type reactorLoader struct {
+	poms       map[string]*gopom.Project
+	repository gopom.LocalRepository
+}
+
+func newReactorLoader(p *gopom.Project, dir string, repository gopom.LocalRepository) reactorLoader {
+	l := reactorLoader{poms: map[string]*gopom.Project{}, repository: repository}
+	for p.Parent != nil {
+		relative := "../pom.xml"
+		if p.Parent.RelativePath != nil {
+			relative = strings.TrimSpace(*p.Parent.RelativePath)
+		}
+		if relative == "" {
+			break
+		}
+		path := filepath.Join(dir, filepath.FromSlash(relative))
+		if info, err := os.Stat(path); err == nil && info.IsDir() {
+			path = filepath.Join(path, "pom.xml")
+		}
+		parent, err := gopom.Parse(path)
+		if err != nil || parent.Coordinates().GAV() != p.Parent.Coordinates().GAV() {
+			break
+		}
+		l.poms[parent.Coordinates().GAV()] = parent
+		p, dir = parent, filepath.Dir(path)
+	}
+	return l
+}
+
+func (l reactorLoader) Load(c gopom.Coordinates) (*gopom.Project, error) {
+	if p, ok := l.poms[c.GAV()]; ok {
+		return p, nil
+	}
+	return l.repository.Load(c)
+}
+
+func runCoordinates(env *environment, args []string) error {
+	fs := env.flags("coordinates")
+	if err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	p, err := env.project()
+	if err != nil {
+		return err
+	}
+	c := p.Coordinates()
+	interpolated := gopom.Coordinates{
+		GroupID:    p.Interpolate(c.GroupID),
+		ArtifactID: p.Interpolate(c.ArtifactID),
+		Version:    p.Interpolate(c.Version),
+	}
+	fmt.Fprintln(env.stdout, interpolated.GAV())
+	return nil
+}
+
+func runEval(env *environment, args []string) error {
+	fs := env.flags("eval")
+	if err := parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+	p, err := env.project()
+	if err != nil {
+		return err
+	}
+	values, err := p.Evaluate(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if len(values) == 0 {
+		return errNotFound
+	}
+	for _, v := range values {
+		fmt.Fprintln(env.stdout, v)
+	}
+	return nil
+}
+
+func runModules(env *environment, args []string) error {
+	fs := env.flags("modules")
+	if err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	p, err := env.project()
+	if err != nil {
+		return err
+	}
+	for _, modules := range []*[]string{p.Modules, p.Subprojects} {
+		if modules == nil {
+			continue
+		}
+		for _, m := range *modules {
+			fmt.Fprintln(env.stdout, m)
+		}
+	}
+	return nil
+}
+
+// dependencyRow is a dependency as listed by the dependencies command.
+type dependencyRow struct {
+	GroupID    string `json:"groupId"`
+	ArtifactID string `json:"artifactId"`
+	Version    string `json:"version,omitempty"`
+	Type       string `json:"type,omitempty"`
+	Classifier string `json:"classifier,omitempty"`
+	Scope      string `json:"scope,omitempty"`
+	Optional   bool   `json:"optional,omitempty"`
+}
+
+func runDependencies(env *environment, args []string) error {
+	fs := env.flags("dependencies")
+	managed := fs.Bool("managed", false, "list dependencyManagement instead of dependencies")
+	format := fs.String("format", "table", "output format, table or json")
+	if err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	if *format != "table" && *format != "json" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	p, err := env.project()
+	if err != nil {
+		return err
+	}
+
+	dependencies := p.Dependencies
+	if *managed {
+		dependencies = nil
+		if p.DependencyManagement != nil {
+			dependencies = p.DependencyManagement.Dependencies
+		}
+	}
+	rows := []dependencyRow{}
+	if dependencies != nil {
+		for _, d := range *dependencies {
+			c := d.Coordinates()
+			rows = append(rows, dependencyRow{
+				GroupID:    p.Interpolate(c.GroupID),
+				ArtifactID: p.Interpolate(c.ArtifactID),
+				Version:    p.Interpolate(c.Version),
+				Type:       c.Type,
+				Classifier: c.Classifier,
+				Scope:      p.Interpolate(deref(d.Scope)),
+				Optional:   p.Interpolate(deref(d.Optional)) == "true",
+			})
+		}
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(env.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rows)
+	}
+	w := tabwriter.NewWriter(env.stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "GROUP ID\tARTIFACT ID\tVERSION\tTYPE\tCLASSIFIER\tSCOPE\tOPTIONAL")
+	for _, r := range rows {
+		optional := ""
+		if r.Optional {
+			optional = "true"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.GroupID, r.ArtifactID, r.Version, r.Type, r.Classifier, r.Scope, optional)
+	}
+	return w.Flush()
+}
+
+func runEffective(env *environment, args []string) error {
+	fs := env.flags("effective")
+	format := fs.String("format", "xml", "output format, xml, json or yaml")
+	if err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+	env.effective = true
+	p, err := env.project()
+	if err != nil {
+		return err
+	}
+	switch *format {
+	case "xml":
+		b, err := xml.MarshalIndent(p, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(env.stdout, "%s%s\n", xml.Header, b)
+		return err
+	case "json":
+		return p.WriteJSON(env.stdout)
+	case "yaml":
+		return p.WriteYAML(env.stdout)
+	}
+	return fmt.Errorf("unknown format %q", *format)
+}
+
+// parseFlags parses the flags and checks that exactly n arguments follow them.
+func parseFlags(fs *flag.FlagSet, args []string, n int) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != n {
+		return fmt.Errorf("expected %d argument(s), got %d", n, fs.NArg())
+	}
+	return nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const parentPom = `<project>
+  <groupId>org.acme</groupId><artifactId>parent</artifactId><version>1.0</version><packaging>pom</packaging>
+  <properties><junit.version>4.13.2</junit.version></properties>
+  <modules><module>app</module><module>lib</module></modules>
+  <dependencyManagement><dependencies>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><version>${junit.version}</version><scope>test</scope></dependency>
+  </dependencies></dependencyManagement>
+</project>`
+
+const appPom = `<project>
+  <parent><groupId>org.acme</groupId><artifactId>parent</artifactId><version>1.0</version></parent>
+  <artifactId>app</artifactId>
+  <dependencies>
+    <dependency><groupId>org.acme</groupId><artifactId>lib</artifactId><version>${project.version}</version></dependency>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId></dependency>
+  </dependencies>
+</project>`
+
+// writeReactor writes a parent pom with an app module and returns its directory.
+func writeReactor(t *testing.T) string {
+	dir := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "app"), 0755))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "pom.xml"), []byte(parentPom), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "app", "pom.xml"), []byte(appPom), 0644))
+	return dir
+}
+
+func runCommand(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func Test_Coordinates(t *testing.T) {
+	dir := writeReactor(t)
+	code, out, _ := runCommand("", "coordinates", "-f", filepath.Join(dir, "app", "pom.xml"))
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "org.acme:app:1.0\n", out)
+
+	code, out, _ = runCommand(parentPom, "coordinates", "-f", "-")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "org.acme:parent:1.0\n", out)
+
+	code, out, _ = runCommand(`<project>
+  <groupId>org.acme</groupId><artifactId>parent</artifactId><version>${revision}</version>
+  <properties><revision>1.2</revision></properties>
+</project>`, "coordinates", "-f", "-")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "org.acme:parent:1.2\n", out)
+}
+
+func Test_Eval(t *testing.T) {
+	dir := writeReactor(t)
+	app := filepath.Join(dir, "app", "pom.xml")
+
+	code, out, _ := runCommand("", "eval", "-f", app, "project.dependencies[artifactId=lib].version")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "1.0\n", out)
+
+	code, out, _ = runCommand("", "eval", "-f", app, "-effective", "project.dependencies[artifactId=junit].version")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "4.13.2\n", out)
+
+	code, out, _ = runCommand(parentPom, "eval", "-f", "-", "junit.version")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "4.13.2\n", out)
+
+	code, out, _ = runCommand("", "eval", "-f", app, "project.dependencies[artifactId=junit].version")
+	assert.Equal(t, exitNotFound, code)
+	assert.Empty(t, out)
+
+	code, _, errOut := runCommand("", "eval", "-f", app, "project.dependencies[0]")
+	assert.Equal(t, exitError, code)
+	assert.Equal(t, "gopom eval: invalid expression \"project.dependencies[0]\": invalid selector [0]\n", errOut)
+}
+
+func Test_Modules(t *testing.T) {
+	dir := writeReactor(t)
+	code, out, _ := runCommand("", "modules", "-f", filepath.Join(dir, "pom.xml"))
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "app\nlib\n", out)
+}
+
+func Test_Dependencies(t *testing.T) {
+	dir := writeReactor(t)
+	app := filepath.Join(dir, "app", "pom.xml")
+
+	code, out, _ := runCommand("", "dependencies", "-f", app, "-effective")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, `GROUP ID  ARTIFACT ID  VERSION  TYPE  CLASSIFIER  SCOPE  OPTIONAL
+org.acme  lib          1.0                               
+junit     junit        4.13.2                     test   
+`, out)
+
+	code, out, _ = runCommand(parentPom, "dependencies", "-f", "-", "-managed", "-format", "json")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, `[
+  {
+    "groupId": "junit",
+    "artifactId": "junit",
+    "version": "4.13.2",
+    "scope": "test"
+  }
+]
+`, out)
+
+	code, out, _ = runCommand(appPom, "dependencies", "-f", "-", "-managed", "-format", "json")
+	assert.Equal(t, exitOK, code)
+	assert.Equal(t, "[]\n", out)
+
+	code, _, errOut := runCommand("", "dependencies", "-f", app, "-format", "csv")
+	assert.Equal(t, exitError, code)
+	assert.Equal(t, "gopom dependencies: unknown format \"csv\"\n", errOut)
+}
+
+func Test_Effective(t *testing.T) {
+	dir := writeReactor(t)
+	code, out, _ := runCommand("", "effective", "-f", filepath.Join(dir, "app", "pom.xml"))
+	assert.Equal(t, exitOK, code)
+	assert.True(t, strings.HasPrefix(out, `<?xml version="1.0" encoding="UTF-8"?>
+<project>`), out)
+	assert.Contains(t, out, `
+      <dependency>
+        <groupId>junit</groupId>
+        <artifactId>junit</artifactId>
+        <version>4.13.2</version>
+        <scope>test</scope>
+      </dependency>`)
+
+	code, _, errOut := runCommand(appPom, "effective", "-f", "-", "-repository", dir)
+	assert.Equal(t, exitError, code)
+	assert.Contains(t, errOut, "gopom effective: ")
+}
+
+func Test_UsageErrors(t *testing.T) {
+	code, _, errOut := runCommand("")
+	assert.Equal(t, exitError, code)
+	assert.True(t, strings.HasPrefix(errOut, "usage:\n"))
+
+	code, _, errOut = runCommand("", "bogus")
+	assert.Equal(t, exitError, code)
+	assert.True(t, strings.HasPrefix(errOut, "gopom: unknown command \"bogus\"\n"))
+
+	code, _, errOut = runCommand("", "coordinates", "-f", "missing.xml")
+	assert.Equal(t, exitError, code)
+	assert.Equal(t, "gopom coordinates: open missing.xml: no such file or directory\n", errOut)
+
+	code, _, errOut = runCommand("", "eval")
+	assert.Equal(t, exitError, code)
+	assert.Equal(t, "gopom eval: expected 1 argument(s), got 0\n", errOut)
+}
@@ -0,0 +1,229 @@
+package gopom
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ArtifactKey is the groupId:artifactId pair identifying an artifact independent of its version.
+type ArtifactKey struct {
+	GroupID    string `json:"groupId"`
+	ArtifactID string `json:"artifactId"`
+}
+
+func (k ArtifactKey) String() string {
+	return k.GroupID + ":" + k.ArtifactID
+}
+
+// Coordinates identify a single artifact. Type holds the dependency type, or
+// the extension when parsed from notation, and is jar when empty.
+type Coordinates struct {
+	GroupID    string `json:"groupId"`
+	ArtifactID string `json:"artifactId"`
+	Type       string `json:"type,omitempty"`
+	Classifier string `json:"classifier,omitempty"`
+	Version    string `json:"version,omitempty"`
+}
+
+// ParseCoordinates parses coordinates in Maven's
+// groupId:artifactId[:extension[:classifier]]:version notation.
+func ParseCoordinates(s string) (Coordinates, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	var c Coordinates
+	switch len(parts) {
+	case 3:
+		c = Coordinates{GroupID: parts[0], ArtifactID: parts[1], Version: parts[2]}
+	case 4:
+		c = Coordinates{GroupID: parts[0], ArtifactID: parts[1], Type: parts[2], Version: parts[3]}
+	case 5:
+		c = Coordinates{GroupID: parts[0], ArtifactID: parts[1], Type: parts[2], Classifier: parts[3], Version: parts[4]}
+	default:
+		return Coordinates{}, fmt.Errorf("invalid coordinates %q, expected groupId:artifactId[:extension[:classifier]]:version", s)
+	}
+	if c.GroupID == "" || c.ArtifactID == "" || c.Version == "" {
+		return Coordinates{}, fmt.Errorf("invalid coordinates %q, groupId, artifactId and version must not be empty", s)
+	}
+	return c, nil
+}
+
+// String formats the coordinates in groupId:artifactId[:extension[:classifier]]:version
+// notation, leaving out the type when it is jar and there is no classifier.
+func (c Coordinates) String() string {
+	parts := []string{c.GroupID, c.ArtifactID}
+	if c.Classifier != "" {
+		parts = append(parts, c.typeOrDefault(), c.Classifier)
+	} else if c.Type != "" && c.Type != "jar" {
+		parts = append(parts, c.Type)
+	}
+	return strings.Join(append(parts, c.Version), ":")
+}
+
+// Key returns the groupId:artifactId of the coordinates.
+func (c Coordinates) Key() ArtifactKey {
+	return ArtifactKey{GroupID: c.GroupID, ArtifactID: c.ArtifactID}
+}
+
+// GAV returns the coordinates as groupId:artifactId:version.
+func (c Coordinates) GAV() string {
+	return c.GroupID + ":" + c.ArtifactID + ":" + c.Version
+}
+
+// ManagementKey returns the groupId:artifactId:type[:classifier] key that
+// Maven uses to match dependencies against dependencyManagement.
+func (c Coordinates) ManagementKey() string {
+	key := c.GroupID + ":" + c.ArtifactID + ":" + c.typeOrDefault()
+	if c.Classifier != "" {
+		key += ":" + c.Classifier
+	}
+	return key
+}
+
+// SameArtifact reports whether c and o have the same management key and so
+// refer to the same artifact, possibly in different versions.
+func (c Coordinates) SameArtifact(o Coordinates) bool {
+	return c.ManagementKey() == o.ManagementKey()
+}
+
+// Matches reports whether the coordinates match pattern. The pattern uses the
+// same notation as ParseCoordinates but may stop after the groupId or
+// artifactId, and every part may contain * wildcards, e.g. "org.apache.*:*"
+// or "*:junit:4.*".
+func (c Coordinates) Matches(pattern string) bool {
+	parts := strings.Split(strings.TrimSpace(pattern), ":")
+	var fields []string
+	switch len(parts) {
+	case 1, 2:
+		fields = []string{c.GroupID, c.ArtifactID}
+	case 3:
+		fields = []string{c.GroupID, c.ArtifactID, c.Version}
+	case 4:
+		fields = []string{c.GroupID, c.ArtifactID, c.typeOrDefault(), c.Version}
+	case 5:
+		fields = []string{c.GroupID, c.ArtifactID, c.typeOrDefault(), c.Classifier, c.Version}
+	default:
+		return false
+	}
+	for i, part := range parts {
+		if !wildcardMatch(part, fields[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c Coordinates) typeOrDefault() string {
+	if c.Type == "" {
+		return "jar"
+	}
+	return c.Type
+}
+
+// wildcardMatch reports whether s matches pattern, where * matches any
+// sequence of characters.
+func wildcardMatch(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == s
+	}
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return strings.HasSuffix(s, parts[len(parts)-1])
+}
+
+// packagingTypes are the packagings whose artifact is not a jar. Others, such
+// as maven-plugin or bundle, produce a jar.
+var packagingTypes = map[string]bool{"pom": true, "war": true, "ear": true, "rar": true}
+
+// Coordinates returns the coordinates of the project, taking the groupId and
+// version from the parent when the project does not declare them. The type
+// is the packaging when it produces something else than a jar.
+func (p *Project) Coordinates() Coordinates {
+	c := Coordinates{GroupID: deref(p.GroupID), ArtifactID: deref(p.ArtifactID), Version: deref(p.Version)}
+	if p.Parent != nil {
+		if c.GroupID == "" {
+			c.GroupID = deref(p.Parent.GroupID)
+		}
+		if c.Version == "" {
+			c.Version = deref(p.Parent.Version)
+		}
+	}
+	if packaging := deref(p.Packaging); packagingTypes[packaging] {
+		c.Type = packaging
+	}
+	return c
+}
+
+// Coordinates returns the coordinates of the dependency.
+func (d Dependency) Coordinates() Coordinates {
+	return Coordinates{
+		GroupID:    deref(d.GroupID),
+		ArtifactID: deref(d.ArtifactID),
+		Type:       deref(d.Type),
+		Classifier: deref(d.Classifier),
+		Version:    deref(d.Version),
+	}
+}
+
+// Coordinates returns the coordinates of the plugin, which belongs to
+// org.apache.maven.plugins when no groupId is given.
+func (p Plugin) Coordinates() Coordinates {
+	return Coordinates{
+		GroupID:    derefOr(p.GroupID, "org.apache.maven.plugins"),
+		ArtifactID: deref(p.ArtifactID),
+		Version:    deref(p.Version),
+	}
+}
+
+// Coordinates returns the coordinates of the report plugin, which belongs to
+// org.apache.maven.plugins when no groupId is given.
+func (p ReportingPlugin) Coordinates() Coordinates {
+	return Coordinates{
+		GroupID:    derefOr(p.GroupID, "org.apache.maven.plugins"),
+		ArtifactID: deref(p.ArtifactID),
+		Version:    deref(p.Version),
+	}
+}
+
+// Coordinates returns the coordinates of the build extension.
+func (e Extension) Coordinates() Coordinates {
+	return Coordinates{
+		GroupID:    deref(e.GroupID),
+		ArtifactID: deref(e.ArtifactID),
+		Version:    deref(e.Version),
+	}
+}
+
+// Coordinates returns the coordinates of the parent pom.
+func (p Parent) Coordinates() Coordinates {
+	return Coordinates{
+		GroupID:    deref(p.GroupID),
+		ArtifactID: deref(p.ArtifactID),
+		Type:       "pom",
+		Version:    deref(p.Version),
+	}
+}
+
+// Coordinates returns the coordinates the artifact was relocated to. Parts the
+// relocation leaves out are taken from the relocated artifact from.
+func (r Relocation) Coordinates(from Coordinates) Coordinates {
+	c := from
+	if !isEmpty(r.GroupID) {
+		c.GroupID = *r.GroupID
+	}
+	if !isEmpty(r.ArtifactID) {
+		c.ArtifactID = *r.ArtifactID
+	}
+	if !isEmpty(r.Version) {
+		c.Version = *r.Version
+	}
+	return c
+}
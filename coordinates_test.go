@@ -0,0 +1,84 @@
+package gopom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ParseCoordinates(t *testing.T) {
+	c, err := ParseCoordinates("org.apache.commons:commons-lang3:3.12.0")
+	assert.Nil(t, err)
+	assert.Equal(t, Coordinates{GroupID: "org.apache.commons", ArtifactID: "commons-lang3", Version: "3.12.0"}, c)
+
+	c, err = ParseCoordinates("com.test:app:war:1.0.0")
+	assert.Nil(t, err)
+	assert.Equal(t, Coordinates{GroupID: "com.test", ArtifactID: "app", Type: "war", Version: "1.0.0"}, c)
+
+	c, err = ParseCoordinates("com.test:app:jar:tests:1.0.0")
+	assert.Nil(t, err)
+	assert.Equal(t, Coordinates{GroupID: "com.test", ArtifactID: "app", Type: "jar", Classifier: "tests", Version: "1.0.0"}, c)
+
+	_, err = ParseCoordinates("com.test:app")
+	assert.EqualError(t, err, `invalid coordinates "com.test:app", expected groupId:artifactId[:extension[:classifier]]:version`)
+	_, err = ParseCoordinates("com.test::1.0.0")
+	assert.NotNil(t, err)
+}
+
+func Test_CoordinatesString(t *testing.T) {
+	for _, s := range []string{
+		"g:a:1",
+		"g:a:war:1",
+		"g:a:jar:tests:1",
+	} {
+		c, err := ParseCoordinates(s)
+		assert.Nil(t, err)
+		assert.Equal(t, s, c.String())
+	}
+
+	assert.Equal(t, "g:a:1", Coordinates{GroupID: "g", ArtifactID: "a", Type: "jar", Version: "1"}.String())
+	assert.Equal(t, "g:a:jar:sources:1", Coordinates{GroupID: "g", ArtifactID: "a", Classifier: "sources", Version: "1"}.String())
+}
+
+func Test_CoordinatesKeys(t *testing.T) {
+	c := Coordinates{GroupID: "g", ArtifactID: "a", Classifier: "tests", Version: "1"}
+	assert.Equal(t, ArtifactKey{GroupID: "g", ArtifactID: "a"}, c.Key())
+	assert.Equal(t, "g:a", c.Key().String())
+	assert.Equal(t, "g:a:1", c.GAV())
+	assert.Equal(t, "g:a:jar:tests", c.ManagementKey())
+
+	assert.True(t, c.SameArtifact(Coordinates{GroupID: "g", ArtifactID: "a", Type: "jar", Classifier: "tests", Version: "2"}))
+	assert.False(t, c.SameArtifact(Coordinates{GroupID: "g", ArtifactID: "a", Version: "1"}))
+}
+
+func Test_CoordinatesMatches(t *testing.T) {
+	c := Coordinates{GroupID: "org.apache.commons", ArtifactID: "commons-lang3", Version: "3.12.0"}
+
+	assert.True(t, c.Matches("org.apache.*:*"))
+	assert.True(t, c.Matches("org.apache.*"))
+	assert.True(t, c.Matches("*:commons-*"))
+	assert.True(t, c.Matches("org.apache.commons:commons-lang3:3.*"))
+	assert.True(t, c.Matches("*:*:jar:*"))
+	assert.True(t, c.Matches("*:*:jar::*"))
+	assert.False(t, c.Matches("org.apache.maven*:*"))
+	assert.False(t, c.Matches("*:*:2.*"))
+	assert.False(t, c.Matches("*:*:pom:*"))
+	assert.False(t, c.Matches("*:*:*:*:*:*"))
+}
+
+func Test_CoordinatesFromModel(t *testing.T) {
+	dependency := (*p.Dependencies)[0]
+	assert.Equal(t, Coordinates{GroupID: "groupId", ArtifactID: "artifactId", Type: "type", Classifier: "classifier", Version: "version"}, dependency.Coordinates())
+
+	assert.Equal(t, Coordinates{GroupID: "com.test", ArtifactID: "test-application", Type: "pom", Version: "1.0.0"}, p.Parent.Coordinates())
+	assert.Equal(t, Coordinates{GroupID: "com.test", ArtifactID: "test-application", Type: "war", Version: "1.0.0"}, p.Coordinates())
+
+	child := Project{ArtifactID: str("child"), Parent: p.Parent}
+	assert.Equal(t, "com.test:child:1.0.0", child.Coordinates().String())
+
+	assert.Equal(t, "org.apache.maven.plugins:maven-jar-plugin:3.0", Plugin{ArtifactID: str("maven-jar-plugin"), Version: str("3.0")}.Coordinates().String())
+	assert.Equal(t, "g:ext:1", Extension{GroupID: str("g"), ArtifactID: str("ext"), Version: str("1")}.Coordinates().String())
+
+	from := Coordinates{GroupID: "old", ArtifactID: "a", Version: "1"}
+	assert.Equal(t, "new:a:1", Relocation{GroupID: str("new")}.Coordinates(from).String())
+}
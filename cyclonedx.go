@@ -0,0 +1,223 @@
+package gopom
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+// CycloneDXNamespace is the XML namespace of CycloneDX 1.5 documents.
+const CycloneDXNamespace = "http://cyclonedx.org/schema/bom/1.5"
+
+// CycloneDXBOM is a CycloneDX 1.5 bill of materials.
+type CycloneDXBOM struct {
+	XMLName      xml.Name              `json:"-" xml:"bom"`
+	Xmlns        string                `json:"-" xml:"xmlns,attr"`
+	BOMFormat    string                `json:"bomFormat" xml:"-"`
+	SpecVersion  string                `json:"specVersion" xml:"-"`
+	SerialNumber string                `json:"serialNumber,omitempty" xml:"serialNumber,attr,omitempty"`
+	Version      int                   `json:"version" xml:"version,attr"`
+	Metadata     CycloneDXMetadata     `json:"metadata" xml:"metadata"`
+	Components   []CycloneDXComponent  `json:"components,omitempty" xml:"components>component,omitempty"`
+	Dependencies []CycloneDXDependency `json:"dependencies,omitempty" xml:"dependencies>dependency,omitempty"`
+}
+
+type CycloneDXMetadata struct {
+	Timestamp string              `json:"timestamp,omitempty" xml:"timestamp,omitempty"`
+	Component *CycloneDXComponent `json:"component,omitempty" xml:"component,omitempty"`
+}
+
+type CycloneDXComponent struct {
+	Type        string                 `json:"type" xml:"type,attr"`
+	BOMRef      string                 `json:"bom-ref" xml:"bom-ref,attr"`
+	Supplier    *CycloneDXOrganization `json:"supplier,omitempty" xml:"supplier,omitempty"`
+	Group       string                 `json:"group,omitempty" xml:"group,omitempty"`
+	Name        string                 `json:"name" xml:"name"`
+	Version     string                 `json:"version,omitempty" xml:"version,omitempty"`
+	Description string                 `json:"description,omitempty" xml:"description,omitempty"`
+	Scope       string                 `json:"scope,omitempty" xml:"scope,omitempty"`
+	Hashes      CycloneDXHashes        `json:"hashes,omitempty" xml:"hashes,omitempty"`
+	Licenses    CycloneDXLicenses      `json:"licenses,omitempty" xml:"licenses,omitempty"`
+	Purl        string                 `json:"purl,omitempty" xml:"purl,omitempty"`
+}
+
+type CycloneDXOrganization struct {
+	Name string   `json:"name,omitempty" xml:"name,omitempty"`
+	URL  []string `json:"url,omitempty" xml:"url,omitempty"`
+}
+
+// CycloneDXHashes is a list of hashes, which is left out of XML when empty.
+type CycloneDXHashes []CycloneDXHash
+
+func (h CycloneDXHashes) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(struct {
+		Hash []CycloneDXHash `xml:"hash"`
+	}{h}, start)
+}
+
+type CycloneDXHash struct {
+	Algorithm string `json:"alg" xml:"alg,attr"`
+	Content   string `json:"content" xml:",chardata"`
+}
+
+// CycloneDXLicenses is a list of licenses, which is left out of XML when empty.
+type CycloneDXLicenses []CycloneDXLicenseChoice
+
+func (l CycloneDXLicenses) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(struct {
+		License []CycloneDXLicenseChoice `xml:"license"`
+	}{l}, start)
+}
+
+// CycloneDXLicenseChoice wraps a license the way the JSON format expects it.
+type CycloneDXLicenseChoice struct {
+	License CycloneDXLicense `json:"license"`
+}
+
+// MarshalXML writes the license directly, as the XML format has no wrapper.
+func (c CycloneDXLicenseChoice) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return e.EncodeElement(c.License, start)
+}
+
+type CycloneDXLicense struct {
+	ID   string `json:"id,omitempty" xml:"id,omitempty"`
+	Name string `json:"name,omitempty" xml:"name,omitempty"`
+	URL  string `json:"url,omitempty" xml:"url,omitempty"`
+}
+
+type CycloneDXDependency struct {
+	Ref       string   `json:"ref" xml:"ref,attr"`
+	DependsOn []string `json:"dependsOn,omitempty" xml:"-"`
+}
+
+// MarshalXML writes the dependencies as nested dependency elements.
+func (d CycloneDXDependency) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	start.Attr = []xml.Attr{{Name: xml.Name{Local: "ref"}, Value: d.Ref}}
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, ref := range d.DependsOn {
+		child := xml.StartElement{Name: start.Name, Attr: []xml.Attr{{Name: xml.Name{Local: "ref"}, Value: ref}}}
+		if err := e.EncodeToken(child); err != nil {
+			return err
+		}
+		if err := e.EncodeToken(child.End()); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// cycloneDXScopes maps Maven scopes to CycloneDX component scopes.
+var cycloneDXScopes = map[Scope]string{
+	ScopeCompile:  "required",
+	ScopeRuntime:  "required",
+	ScopeProvided: "optional",
+	ScopeSystem:   "optional",
+	ScopeTest:     "excluded",
+}
+
+// NewCycloneDX builds a CycloneDX bill of materials for the project and its
+// resolved dependency graph. Components are identified by their package URL
+// and sorted, so the same input always produces the same document.
+func NewCycloneDX(p *Project, graph *DependencyNode, opts SBOMOptions) (*CycloneDXBOM, error) {
+	root, components, err := sbomComponents(p, graph, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	rootComponent := cycloneDXComponent(root)
+	rootComponent.Scope = ""
+	switch derefOr(p.Packaging, string(PackagingJar)) {
+	case string(PackagingWar), string(PackagingEar):
+		rootComponent.Type = "application"
+	}
+	bom := &CycloneDXBOM{
+		Xmlns:       CycloneDXNamespace,
+		BOMFormat:   "CycloneDX",
+		SpecVersion: "1.5",
+		Version:     1,
+		Metadata:    CycloneDXMetadata{Component: &rootComponent},
+	}
+	if !opts.Timestamp.IsZero() {
+		bom.Metadata.Timestamp = opts.Timestamp.UTC().Format(time.RFC3339)
+	}
+
+	all := append([]sbomComponent{root}, components...)
+	for i, c := range components {
+		bom.Components = append(bom.Components, cycloneDXComponent(c))
+		if c.optional {
+			bom.Components[i].Scope = "optional"
+		}
+	}
+	for _, c := range all {
+		dependency := CycloneDXDependency{Ref: c.coordinates.PackageURL().String()}
+		for _, d := range c.dependsOn {
+			dependency.DependsOn = append(dependency.DependsOn, d.PackageURL().String())
+		}
+		sort.Strings(dependency.DependsOn)
+		bom.Dependencies = append(bom.Dependencies, dependency)
+	}
+	sort.Slice(bom.Components, func(i, j int) bool { return bom.Components[i].BOMRef < bom.Components[j].BOMRef })
+	sort.Slice(bom.Dependencies, func(i, j int) bool { return bom.Dependencies[i].Ref < bom.Dependencies[j].Ref })
+	return bom, nil
+}
+
+func cycloneDXComponent(c sbomComponent) CycloneDXComponent {
+	purl := c.coordinates.PackageURL().String()
+	component := CycloneDXComponent{
+		Type:        "library",
+		BOMRef:      purl,
+		Group:       c.coordinates.GroupID,
+		Name:        c.coordinates.ArtifactID,
+		Version:     c.coordinates.Version,
+		Description: c.description,
+		Scope:       cycloneDXScopes[c.scope],
+		Purl:        purl,
+	}
+	if c.organization != nil && (c.organization.Name != nil || c.organization.URL != nil) {
+		component.Supplier = &CycloneDXOrganization{Name: deref(c.organization.Name)}
+		if c.organization.URL != nil {
+			component.Supplier.URL = []string{*c.organization.URL}
+		}
+	}
+	for _, alg := range sbomAlgorithms {
+		if digest, ok := c.hashes[alg.name]; ok {
+			component.Hashes = append(component.Hashes, CycloneDXHash{Algorithm: alg.name, Content: digest})
+		}
+	}
+	for _, m := range NormalizeLicenses(c.licenses).Matches {
+		license := CycloneDXLicense{URL: deref(m.License.URL)}
+		if m.Known() && !strings.Contains(m.ID, " ") {
+			license.ID = m.ID
+		} else {
+			license.Name = deref(m.License.Name)
+		}
+		component.Licenses = append(component.Licenses, CycloneDXLicenseChoice{License: license})
+	}
+	return component
+}
+
+// WriteJSON writes the bill of materials as indented JSON.
+func (b *CycloneDXBOM) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(b)
+}
+
+// WriteXML writes the bill of materials as indented XML.
+func (b *CycloneDXBOM) WriteXML(w io.Writer) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(b); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
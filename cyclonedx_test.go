@@ -0,0 +1,125 @@
+package gopom
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var sbomPoms = map[string]string{
+	"org/acme/base/1/base-1.pom": `<project>
+  <groupId>org.acme</groupId><artifactId>base</artifactId><version>1</version><packaging>pom</packaging>
+  <organization><name>Acme</name><url>https://acme.org</url></organization>
+  <licenses><license><name>Apache License, Version 2.0</name><url>https://www.apache.org/licenses/LICENSE-2.0.txt</url></license></licenses>
+  <developers><developer><id>jd</id><name>Jane Doe</name><email>jane@acme.org</email></developer></developers>
+</project>`,
+	"org/acme/core/1.0/core-1.0.pom": `<project>
+  <parent><groupId>org.acme</groupId><artifactId>base</artifactId><version>1</version></parent>
+  <artifactId>core</artifactId><version>1.0</version>
+  <description>Acme core</description>
+  <dependencies>
+    <dependency><groupId>org.acme</groupId><artifactId>util</artifactId><version>2</version></dependency>
+  </dependencies>
+</project>`,
+	"org/acme/core/1.0/core-1.0.jar": "core classes",
+}
+
+const sbomApp = `<project>
+  <groupId>org.acme</groupId><artifactId>shop</artifactId><version>3</version><packaging>war</packaging>
+  <description>Acme shop</description>
+  <licenses><license><name>MIT</name></license></licenses>
+  <dependencies>
+    <dependency><groupId>org.acme</groupId><artifactId>core</artifactId><version>1.0</version></dependency>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><version>4.13</version><scope>test</scope></dependency>
+  </dependencies>
+</project>`
+
+func sbomFixture(t *testing.T) (*Project, *DependencyNode, SBOMOptions) {
+	repo := LocalRepository(writeRepository(t, sbomPoms))
+	p, err := ParseFromReader(strings.NewReader(sbomApp))
+	assert.Nil(t, err)
+	graph, err := RepositoryResolver{Loader: repo}.Resolve(p)
+	assert.Nil(t, err)
+	return p, graph, SBOMOptions{Loader: repo, Repository: repo, Timestamp: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)}
+}
+
+func Test_NewCycloneDX(t *testing.T) {
+	p, graph, opts := sbomFixture(t)
+	bom, err := NewCycloneDX(p, graph, opts)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "2024-01-02T03:04:05Z", bom.Metadata.Timestamp)
+	assert.Equal(t, "application", bom.Metadata.Component.Type)
+	assert.Equal(t, "pkg:maven/org.acme/shop@3?type=war", bom.Metadata.Component.BOMRef)
+	assert.Equal(t, CycloneDXLicense{ID: "MIT"}, bom.Metadata.Component.Licenses[0].License)
+
+	var refs []string
+	for _, c := range bom.Components {
+		refs = append(refs, c.BOMRef+" "+c.Scope)
+	}
+	assert.Equal(t, []string{
+		"pkg:maven/junit/junit@4.13 excluded",
+		"pkg:maven/org.acme/core@1.0 required",
+		"pkg:maven/org.acme/util@2 required",
+	}, refs)
+
+	core := bom.Components[1]
+	assert.Equal(t, "Acme core", core.Description)
+	assert.Equal(t, &CycloneDXOrganization{Name: "Acme", URL: []string{"https://acme.org"}}, core.Supplier)
+	assert.Equal(t, CycloneDXLicense{ID: "Apache-2.0", URL: "https://www.apache.org/licenses/LICENSE-2.0.txt"}, core.Licenses[0].License)
+	sum := sha256.Sum256([]byte("core classes"))
+	assert.Len(t, core.Hashes, 3)
+	assert.Equal(t, CycloneDXHash{Algorithm: "SHA-256", Content: hex.EncodeToString(sum[:])}, core.Hashes[1])
+	assert.Empty(t, bom.Components[2].Hashes)
+
+	assert.Equal(t, []CycloneDXDependency{
+		{Ref: "pkg:maven/junit/junit@4.13"},
+		{Ref: "pkg:maven/org.acme/core@1.0", DependsOn: []string{"pkg:maven/org.acme/util@2"}},
+		{Ref: "pkg:maven/org.acme/shop@3?type=war", DependsOn: []string{"pkg:maven/junit/junit@4.13", "pkg:maven/org.acme/core@1.0"}},
+		{Ref: "pkg:maven/org.acme/util@2"},
+	}, bom.Dependencies)
+}
+
+func Test_NewCycloneDXWithoutGraph(t *testing.T) {
+	_, err := NewCycloneDX(&Project{}, nil, SBOMOptions{})
+	assert.EqualError(t, err, "no dependency graph")
+}
+
+func Test_CycloneDXOutput(t *testing.T) {
+	p, graph, opts := sbomFixture(t)
+	opts.Repository = ""
+	bom, err := NewCycloneDX(p, graph, opts)
+	assert.Nil(t, err)
+
+	var first, second bytes.Buffer
+	assert.Nil(t, bom.WriteJSON(&first))
+	assert.Nil(t, bom.WriteJSON(&second))
+	assert.Equal(t, first.String(), second.String())
+	assert.Contains(t, first.String(), `"bomFormat": "CycloneDX",
+  "specVersion": "1.5",`)
+	assert.Contains(t, first.String(), `"licenses": [
+        {
+          "license": {
+            "id": "Apache-2.0",`)
+
+	var xmlOut bytes.Buffer
+	assert.Nil(t, bom.WriteXML(&xmlOut))
+	out := xmlOut.String()
+	assert.True(t, strings.HasPrefix(out, `<?xml version="1.0" encoding="UTF-8"?>
+<bom xmlns="http://cyclonedx.org/schema/bom/1.5" version="1">`), out)
+	assert.Contains(t, out, `
+      <licenses>
+        <license>
+          <id>Apache-2.0</id>`)
+	assert.Contains(t, out, `
+    <dependency ref="pkg:maven/org.acme/core@1.0">
+      <dependency ref="pkg:maven/org.acme/util@2"></dependency>
+    </dependency>`)
+	assert.NotContains(t, out, "bomFormat")
+	assert.NotContains(t, out, "<hashes>")
+}
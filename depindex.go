@@ -0,0 +1,204 @@
+package gopom
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+)
+
+// EdgeKind is how a project refers to another artifact.
+type EdgeKind string
+
+const (
+	EdgeDependency       EdgeKind = "dependency"
+	EdgeManaged          EdgeKind = "dependencyManagement"
+	EdgePlugin           EdgeKind = "plugin"
+	EdgePluginDependency EdgeKind = "pluginDependency"
+	EdgeParent           EdgeKind = "parent"
+)
+
+// DependencyEdge records that the project From refers to the artifact To. The
+// version of To is the version or version range as declared, after
+// interpolating the properties of From, and is empty when it is managed elsewhere.
+type DependencyEdge struct {
+	From  Coordinates `json:"from"`
+	To    Coordinates `json:"to"`
+	Kind  EdgeKind    `json:"kind"`
+	Scope string      `json:"scope,omitempty"`
+}
+
+// DependencyIndex answers which projects depend on an artifact, across many
+// loaded projects.
+type DependencyIndex struct {
+	edges    []DependencyEdge
+	byTarget map[ArtifactKey][]int
+}
+
+// NewDependencyIndex returns an empty index.
+func NewDependencyIndex() *DependencyIndex {
+	return &DependencyIndex{byTarget: map[ArtifactKey][]int{}}
+}
+
+// Add indexes the parent, dependencies, dependencyManagement and build plugins of the project.
+func (i *DependencyIndex) Add(p *Project) {
+	from := p.Coordinates()
+	add := func(kind EdgeKind, to Coordinates, scope string) {
+		to.GroupID = p.Interpolate(to.GroupID)
+		to.ArtifactID = p.Interpolate(to.ArtifactID)
+		to.Version = p.Interpolate(to.Version)
+		i.addEdge(DependencyEdge{From: from, To: to, Kind: kind, Scope: scope})
+	}
+
+	if p.Parent != nil {
+		add(EdgeParent, p.Parent.Coordinates(), "")
+	}
+	if p.Dependencies != nil {
+		for _, d := range *p.Dependencies {
+			add(EdgeDependency, d.Coordinates(), deref(d.Scope))
+		}
+	}
+	if p.DependencyManagement != nil && p.DependencyManagement.Dependencies != nil {
+		for _, d := range *p.DependencyManagement.Dependencies {
+			add(EdgeManaged, d.Coordinates(), deref(d.Scope))
+		}
+	}
+	if p.Build != nil {
+		var plugins []Plugin
+		if p.Build.Plugins != nil {
+			plugins = append(plugins, *p.Build.Plugins...)
+		}
+		if p.Build.PluginManagement != nil && p.Build.PluginManagement.Plugins != nil {
+			plugins = append(plugins, *p.Build.PluginManagement.Plugins...)
+		}
+		for _, plugin := range plugins {
+			add(EdgePlugin, plugin.Coordinates(), "")
+			if plugin.Dependencies != nil {
+				for _, d := range *plugin.Dependencies {
+					add(EdgePluginDependency, d.Coordinates(), deref(d.Scope))
+				}
+			}
+		}
+	}
+}
+
+func (i *DependencyIndex) addEdge(e DependencyEdge) {
+	i.byTarget[e.To.Key()] = append(i.byTarget[e.To.Key()], len(i.edges))
+	i.edges = append(i.edges, e)
+}
+
+// Edges returns every edge in the index.
+func (i *DependencyIndex) Edges() []DependencyEdge {
+	return append([]DependencyEdge(nil), i.edges...)
+}
+
+// DependentsQuery selects the projects returned by DependencyIndex.Dependents.
+type DependentsQuery struct {
+	Artifact ArtifactKey
+	// Versions is a version range such as [1.0,2.0); empty matches every version.
+	Versions string
+	// Transitive also returns projects that reach the artifact through other
+	// indexed projects, e.g. by depending on a library that depends on it.
+	Transitive bool
+	// Kinds restricts the edges that are followed; empty follows all of them.
+	Kinds []EdgeKind
+}
+
+// Dependent is a project that depends on the queried artifact.
+type Dependent struct {
+	Coordinates Coordinates
+	// Path is the shortest chain of edges from the dependent to the artifact.
+	Path []DependencyEdge
+}
+
+// Dependents returns the projects that depend on the artifact selected by q,
+// sorted by their coordinates.
+func (i *DependencyIndex) Dependents(q DependentsQuery) ([]Dependent, error) {
+	versions := AnyVersion
+	if q.Versions != "" {
+		var err error
+		versions, err = ParseVersionRange(q.Versions)
+		if err != nil {
+			return nil, err
+		}
+	}
+	kinds := map[EdgeKind]bool{}
+	for _, k := range q.Kinds {
+		kinds[k] = true
+	}
+
+	type target struct {
+		key      ArtifactKey
+		versions VersionRange
+		path     []DependencyEdge
+	}
+	queue := []target{{key: q.Artifact, versions: versions}}
+	found := map[string]Dependent{}
+
+	for len(queue) > 0 {
+		t := queue[0]
+		queue = queue[1:]
+		for _, idx := range i.byTarget[t.key] {
+			e := i.edges[idx]
+			if len(kinds) > 0 && !kinds[e.Kind] {
+				continue
+			}
+			if !edgeVersions(e).Intersects(t.versions) {
+				continue
+			}
+			gav := e.From.GAV()
+			if _, ok := found[gav]; ok {
+				continue
+			}
+			path := append([]DependencyEdge{e}, t.path...)
+			found[gav] = Dependent{Coordinates: e.From, Path: path}
+			if q.Transitive {
+				exact := VersionRange{restrictions: []versionRestriction{{lower: e.From.Version, upper: e.From.Version, lowerInclusive: true, upperInclusive: true}}}
+				queue = append(queue, target{key: e.From.Key(), versions: exact, path: path})
+			}
+		}
+	}
+
+	dependents := make([]Dependent, 0, len(found))
+	for _, d := range found {
+		dependents = append(dependents, d)
+	}
+	sort.Slice(dependents, func(a, b int) bool {
+		return dependents[a].Coordinates.GAV() < dependents[b].Coordinates.GAV()
+	})
+	return dependents, nil
+}
+
+// edgeVersions returns the versions of the target an edge may resolve to.
+// Versions that are missing or cannot be parsed may be anything.
+func edgeVersions(e DependencyEdge) VersionRange {
+	if e.To.Version == "" {
+		return AnyVersion
+	}
+	r, err := ParseVersionRange(e.To.Version)
+	if err != nil {
+		return AnyVersion
+	}
+	return r
+}
+
+type dependencyIndexFile struct {
+	Edges []DependencyEdge `json:"edges"`
+}
+
+// Save writes the index to w so that it can be reused with LoadDependencyIndex.
+func (i *DependencyIndex) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(dependencyIndexFile{Edges: i.edges})
+}
+
+// LoadDependencyIndex reads an index written by DependencyIndex.Save.
+func LoadDependencyIndex(r io.Reader) (*DependencyIndex, error) {
+	var f dependencyIndexFile
+	if err := json.NewDecoder(r).Decode(&f); err != nil {
+		return nil, err
+	}
+	i := NewDependencyIndex()
+	for _, e := range f.Edges {
+		i.addEdge(e)
+	}
+	return i, nil
+}
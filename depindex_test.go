@@ -0,0 +1,115 @@
+package gopom
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var indexedPoms = []string{
+	`<project><groupId>com.test</groupId><artifactId>lib</artifactId><version>1.0</version>
+  <dependencies><dependency><groupId>junit</groupId><artifactId>junit</artifactId><version>4.12</version><scope>test</scope></dependency></dependencies>
+</project>`,
+	`<project><groupId>com.test</groupId><artifactId>lib</artifactId><version>2.0</version>
+  <properties><junit.version>4.13</junit.version></properties>
+  <dependencies><dependency><groupId>junit</groupId><artifactId>junit</artifactId><version>${junit.version}</version><scope>test</scope></dependency></dependencies>
+</project>`,
+	`<project><groupId>com.test</groupId><artifactId>app</artifactId><version>1</version>
+  <dependencies><dependency><groupId>com.test</groupId><artifactId>lib</artifactId><version>[1.0,2.0)</version></dependency></dependencies>
+</project>`,
+	`<project><groupId>com.test</groupId><artifactId>bom</artifactId><version>1</version><packaging>pom</packaging>
+  <dependencyManagement><dependencies><dependency><groupId>com.test</groupId><artifactId>lib</artifactId><version>2.0</version></dependency></dependencies></dependencyManagement>
+</project>`,
+	`<project><parent><groupId>com.test</groupId><artifactId>bom</artifactId><version>1</version></parent><artifactId>svc</artifactId></project>`,
+	`<project><groupId>com.test</groupId><artifactId>tool</artifactId><version>1</version>
+  <build><plugins><plugin><artifactId>maven-surefire-plugin</artifactId><version>3.0</version>
+    <dependencies><dependency><groupId>junit</groupId><artifactId>junit</artifactId><version>4.13.2</version></dependency></dependencies>
+  </plugin></plugins></build>
+</project>`,
+}
+
+func newTestDependencyIndex(t *testing.T) *DependencyIndex {
+	index := NewDependencyIndex()
+	for _, pom := range indexedPoms {
+		project, err := ParseFromReader(strings.NewReader(pom))
+		if err != nil {
+			t.Fatal(err)
+		}
+		index.Add(project)
+	}
+	return index
+}
+
+func dependentGAVs(t *testing.T, index *DependencyIndex, q DependentsQuery) []string {
+	dependents, err := index.Dependents(q)
+	assert.Nil(t, err)
+	var gavs []string
+	for _, d := range dependents {
+		gavs = append(gavs, d.Coordinates.GAV())
+	}
+	return gavs
+}
+
+var junitKey = ArtifactKey{GroupID: "junit", ArtifactID: "junit"}
+
+func Test_DependencyIndexDirectDependents(t *testing.T) {
+	index := newTestDependencyIndex(t)
+
+	assert.Equal(t, []string{"com.test:lib:1.0", "com.test:lib:2.0", "com.test:tool:1"},
+		dependentGAVs(t, index, DependentsQuery{Artifact: junitKey}))
+	assert.Equal(t, []string{"com.test:lib:2.0", "com.test:tool:1"},
+		dependentGAVs(t, index, DependentsQuery{Artifact: junitKey, Versions: "[4.13,)"}))
+	assert.Equal(t, []string{"com.test:tool:1"},
+		dependentGAVs(t, index, DependentsQuery{Artifact: junitKey, Kinds: []EdgeKind{EdgePluginDependency}}))
+	assert.Equal(t, []string{"com.test:tool:1"},
+		dependentGAVs(t, index, DependentsQuery{Artifact: ArtifactKey{GroupID: "org.apache.maven.plugins", ArtifactID: "maven-surefire-plugin"}}))
+
+	_, err := index.Dependents(DependentsQuery{Artifact: junitKey, Versions: "[4.13"})
+	assert.NotNil(t, err)
+}
+
+func Test_DependencyIndexTransitiveDependents(t *testing.T) {
+	index := newTestDependencyIndex(t)
+
+	assert.Equal(t, []string{"com.test:app:1", "com.test:lib:1.0"},
+		dependentGAVs(t, index, DependentsQuery{Artifact: junitKey, Versions: "(,4.12]", Transitive: true}))
+	assert.Equal(t, []string{"com.test:bom:1", "com.test:lib:2.0", "com.test:svc:1", "com.test:tool:1"},
+		dependentGAVs(t, index, DependentsQuery{Artifact: junitKey, Versions: "[4.13,)", Transitive: true}))
+	assert.Equal(t, []string{"com.test:app:1", "com.test:lib:1.0", "com.test:lib:2.0"},
+		dependentGAVs(t, index, DependentsQuery{Artifact: junitKey, Transitive: true, Kinds: []EdgeKind{EdgeDependency}}))
+
+	dependents, err := index.Dependents(DependentsQuery{Artifact: junitKey, Versions: "[4.13]", Transitive: true})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(dependents))
+	for _, d := range dependents {
+		if d.Coordinates.ArtifactID != "svc" {
+			continue
+		}
+		var kinds []EdgeKind
+		for _, e := range d.Path {
+			kinds = append(kinds, e.Kind)
+		}
+		assert.Equal(t, []EdgeKind{EdgeParent, EdgeManaged, EdgeDependency}, kinds)
+		assert.Equal(t, "4.13", d.Path[2].To.Version)
+	}
+}
+
+func Test_DependencyIndexSaveAndLoad(t *testing.T) {
+	index := newTestDependencyIndex(t)
+
+	var buf bytes.Buffer
+	assert.Nil(t, index.Save(&buf))
+	assert.Contains(t, buf.String(), `"from":{"groupId":"com.test","artifactId":"lib","version":"1.0"}`)
+
+	loaded, err := LoadDependencyIndex(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, index.Edges(), loaded.Edges())
+	assert.Equal(t,
+		dependentGAVs(t, index, DependentsQuery{Artifact: junitKey, Transitive: true}),
+		dependentGAVs(t, loaded, DependentsQuery{Artifact: junitKey, Transitive: true}))
+
+	_, err = LoadDependencyIndex(strings.NewReader("{"))
+	assert.NotNil(t, err)
+}
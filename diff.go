@@ -0,0 +1,430 @@
+package gopom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ChangeType is whether something was added, removed or changed.
+type ChangeType string
+
+const (
+	ChangeAdded   ChangeType = "added"
+	ChangeRemoved ChangeType = "removed"
+	ChangeChanged ChangeType = "changed"
+)
+
+// Change is a single semantic difference between two poms.
+type Change struct {
+	// Section is the part of the pom, e.g. "dependencies", "build.plugins" or
+	// "profiles[release].properties".
+	Section string `json:"section"`
+	// Key identifies the entry within the section, e.g. the management key of
+	// a dependency, the name of a property or the id of a profile.
+	Key  string     `json:"key,omitempty"`
+	Type ChangeType `json:"type"`
+	// Field is the changed part of the entry, e.g. "version" or "configuration.release".
+	Field string `json:"field,omitempty"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+}
+
+func (c Change) String() string {
+	subject := c.Section
+	if c.Key != "" {
+		subject += " " + c.Key
+	}
+	switch c.Type {
+	case ChangeAdded:
+		return strings.TrimSpace(fmt.Sprintf("+ %s %s", subject, c.New))
+	case ChangeRemoved:
+		return strings.TrimSpace(fmt.Sprintf("- %s %s", subject, c.Old))
+	}
+	if c.Field != "" {
+		subject += " " + c.Field
+	}
+	return fmt.Sprintf("~ %s: %s -> %s", subject, quoteEmpty(c.Old), quoteEmpty(c.New))
+}
+
+func quoteEmpty(s string) string {
+	if s == "" {
+		return `""`
+	}
+	return s
+}
+
+// ProjectDiff is the list of semantic changes between two poms.
+type ProjectDiff struct {
+	Changes []Change `json:"changes"`
+}
+
+// Empty reports whether the poms are semantically equal.
+func (d *ProjectDiff) Empty() bool {
+	return len(d.Changes) == 0
+}
+
+// WriteText writes one line per change, e.g.
+// "~ dependencies junit:junit:jar version: 4.12 -> 4.13".
+func (d *ProjectDiff) WriteText(w io.Writer) error {
+	for _, c := range d.Changes {
+		if _, err := fmt.Fprintln(w, c.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WriteJSON writes the changes as a JSON document.
+func (d *ProjectDiff) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
+
+// Diff compares two poms semantically: dependencies are matched by their
+// management key, plugins by groupId:artifactId, executions, profiles and
+// repositories by id and properties by name, so reordering elements is not
+// reported as a change.
+func Diff(a, b *Project) *ProjectDiff {
+	d := &differ{}
+
+	d.compare("project", projectEntities(a), projectEntities(b))
+	d.compare("parent", parentEntities(a.Parent), parentEntities(b.Parent))
+	d.compare("modules", stringEntities(a.ChildProjects()), stringEntities(b.ChildProjects()))
+	d.content("", contentOf(a), contentOf(b))
+
+	d.compare("profiles", profileEntities(a.Profiles), profileEntities(b.Profiles))
+	if a.Profiles != nil && b.Profiles != nil {
+		for _, pa := range *a.Profiles {
+			for _, pb := range *b.Profiles {
+				if deref(pa.ID) == deref(pb.ID) {
+					d.content(fmt.Sprintf("profiles[%s].", deref(pa.ID)), profileContentOf(pa), profileContentOf(pb))
+				}
+			}
+		}
+	}
+
+	return &ProjectDiff{Changes: d.changes}
+}
+
+// entity is a comparable entry of a section: a one line summary that is
+// reported when it is added or removed and the fields compared otherwise.
+type entity struct {
+	summary string
+	fields  map[string]string
+}
+
+type differ struct {
+	changes []Change
+}
+
+func (d *differ) compare(section string, a, b map[string]entity) {
+	for _, key := range sortedKeys(a, b) {
+		ea, inA := a[key]
+		eb, inB := b[key]
+		switch {
+		case !inA:
+			d.changes = append(d.changes, Change{Section: section, Key: key, Type: ChangeAdded, New: eb.summary})
+		case !inB:
+			d.changes = append(d.changes, Change{Section: section, Key: key, Type: ChangeRemoved, Old: ea.summary})
+		default:
+			for _, field := range sortedFieldKeys(ea.fields, eb.fields) {
+				if ea.fields[field] != eb.fields[field] {
+					d.changes = append(d.changes, Change{
+						Section: section,
+						Key:     key,
+						Type:    ChangeChanged,
+						Field:   field,
+						Old:     ea.fields[field],
+						New:     eb.fields[field],
+					})
+				}
+			}
+		}
+	}
+}
+
+// content is the part of the model shared by projects and profiles.
+type content struct {
+	properties           *Properties
+	dependencies         *[]Dependency
+	dependencyManagement *DependencyManagement
+	build                *BuildBase
+	repositories         *[]Repository
+	pluginRepositories   *[]PluginRepository
+}
+
+func contentOf(p *Project) content {
+	c := content{
+		properties:           p.Properties,
+		dependencies:         p.Dependencies,
+		dependencyManagement: p.DependencyManagement,
+		repositories:         p.Repositories,
+		pluginRepositories:   p.PluginRepositories,
+	}
+	if p.Build != nil {
+		c.build = &p.Build.BuildBase
+	}
+	return c
+}
+
+func profileContentOf(p Profile) content {
+	return content{
+		properties:           p.Properties,
+		dependencies:         p.Dependencies,
+		dependencyManagement: p.DependencyManagement,
+		build:                p.Build,
+		repositories:         p.Repositories,
+		pluginRepositories:   p.PluginRepositories,
+	}
+}
+
+func (d *differ) content(prefix string, a, b content) {
+	d.compare(prefix+"properties", propertyEntities(a.properties), propertyEntities(b.properties))
+	d.compare(prefix+"dependencies", dependencyEntities(a.dependencies), dependencyEntities(b.dependencies))
+	d.compare(prefix+"dependencyManagement", dependencyEntities(managedDependencies(a.dependencyManagement)), dependencyEntities(managedDependencies(b.dependencyManagement)))
+	d.compare(prefix+"build.plugins", pluginEntities(plugins(a.build)), pluginEntities(plugins(b.build)))
+	d.compare(prefix+"build.pluginManagement", pluginEntities(managedPlugins(a.build)), pluginEntities(managedPlugins(b.build)))
+	d.compare(prefix+"repositories", repositoryEntities(a.repositories), repositoryEntities(b.repositories))
+	d.compare(prefix+"pluginRepositories", repositoryEntities(pluginRepositories(a.pluginRepositories)), repositoryEntities(pluginRepositories(b.pluginRepositories)))
+}
+
+func projectEntities(p *Project) map[string]entity {
+	return map[string]entity{"": {fields: map[string]string{
+		"modelVersion": deref(p.ModelVersion),
+		"groupId":      deref(p.GroupID),
+		"artifactId":   deref(p.ArtifactID),
+		"version":      deref(p.Version),
+		"packaging":    deref(p.Packaging),
+	}}}
+}
+
+func parentEntities(p *Parent) map[string]entity {
+	if p == nil {
+		return nil
+	}
+	c := p.Coordinates()
+	return map[string]entity{c.Key().String(): {
+		summary: c.Version,
+		fields: map[string]string{
+			"version":      c.Version,
+			"relativePath": deref(p.RelativePath),
+		},
+	}}
+}
+
+func stringEntities(values []string) map[string]entity {
+	entities := map[string]entity{}
+	for _, v := range values {
+		entities[v] = entity{}
+	}
+	return entities
+}
+
+func propertyEntities(p *Properties) map[string]entity {
+	if p == nil {
+		return nil
+	}
+	entities := map[string]entity{}
+	for k, v := range p.Entries {
+		entities[k] = entity{summary: v, fields: map[string]string{"": v}}
+	}
+	return entities
+}
+
+func managedDependencies(m *DependencyManagement) *[]Dependency {
+	if m == nil {
+		return nil
+	}
+	return m.Dependencies
+}
+
+func dependencyEntities(deps *[]Dependency) map[string]entity {
+	if deps == nil {
+		return nil
+	}
+	entities := map[string]entity{}
+	for _, dep := range *deps {
+		var exclusions []string
+		if dep.Exclusions != nil {
+			for _, e := range *dep.Exclusions {
+				exclusions = append(exclusions, deref(e.GroupID)+":"+deref(e.ArtifactID))
+			}
+			sort.Strings(exclusions)
+		}
+		entities[dep.Coordinates().ManagementKey()] = entity{
+			summary: deref(dep.Version),
+			fields: map[string]string{
+				"version":    deref(dep.Version),
+				"scope":      deref(dep.Scope),
+				"optional":   deref(dep.Optional),
+				"systemPath": deref(dep.SystemPath),
+				"exclusions": strings.Join(exclusions, ","),
+			},
+		}
+	}
+	return entities
+}
+
+func plugins(b *BuildBase) *[]Plugin {
+	if b == nil {
+		return nil
+	}
+	return b.Plugins
+}
+
+func managedPlugins(b *BuildBase) *[]Plugin {
+	if b == nil || b.PluginManagement == nil {
+		return nil
+	}
+	return b.PluginManagement.Plugins
+}
+
+func pluginEntities(plugins *[]Plugin) map[string]entity {
+	if plugins == nil {
+		return nil
+	}
+	entities := map[string]entity{}
+	for _, p := range *plugins {
+		fields := map[string]string{
+			"version":    deref(p.Version),
+			"extensions": deref(p.Extensions),
+			"inherited":  deref(p.Inherited),
+		}
+		addConfiguration(fields, "configuration.", p.Configuration)
+		if p.Dependencies != nil {
+			for key, e := range dependencyEntities(p.Dependencies) {
+				fields["dependencies["+key+"]"] = e.summary
+			}
+		}
+		if p.Executions != nil {
+			for _, e := range *p.Executions {
+				prefix := "executions[" + derefOr(e.ID, "default") + "]."
+				fields[prefix+"phase"] = deref(e.Phase)
+				if e.Goals != nil {
+					fields[prefix+"goals"] = strings.Join(*e.Goals, ",")
+				}
+				addConfiguration(fields, prefix+"configuration.", e.Configuration)
+			}
+		}
+		entities[p.Coordinates().Key().String()] = entity{summary: deref(p.Version), fields: fields}
+	}
+	return entities
+}
+
+func addConfiguration(fields map[string]string, prefix string, configuration *Properties) {
+	if configuration == nil {
+		return
+	}
+	for k, v := range configuration.Entries {
+		if raw, ok := configuration.RawXML[k]; ok {
+			v = raw
+		}
+		fields[prefix+k] = v
+	}
+}
+
+func pluginRepositories(repos *[]PluginRepository) *[]Repository {
+	if repos == nil {
+		return nil
+	}
+	converted := make([]Repository, len(*repos))
+	for i, r := range *repos {
+		converted[i] = Repository{Releases: r.Releases, Snapshots: r.Snapshots, ID: r.ID, Name: r.Name, URL: r.URL, Layout: r.Layout}
+	}
+	return &converted
+}
+
+func repositoryEntities(repos *[]Repository) map[string]entity {
+	if repos == nil {
+		return nil
+	}
+	entities := map[string]entity{}
+	for _, r := range *repos {
+		fields := map[string]string{
+			"url":    deref(r.URL),
+			"name":   deref(r.Name),
+			"layout": deref(r.Layout),
+		}
+		addPolicy(fields, "releases.", r.Releases)
+		addPolicy(fields, "snapshots.", r.Snapshots)
+		entities[deref(r.ID)] = entity{summary: deref(r.URL), fields: fields}
+	}
+	return entities
+}
+
+func addPolicy(fields map[string]string, prefix string, policy *RepositoryPolicy) {
+	if policy == nil {
+		return
+	}
+	fields[prefix+"enabled"] = deref(policy.Enabled)
+	fields[prefix+"updatePolicy"] = deref(policy.UpdatePolicy)
+	fields[prefix+"checksumPolicy"] = deref(policy.ChecksumPolicy)
+}
+
+func profileEntities(profiles *[]Profile) map[string]entity {
+	if profiles == nil {
+		return nil
+	}
+	entities := map[string]entity{}
+	for _, p := range *profiles {
+		fields := map[string]string{}
+		if a := p.Activation; a != nil {
+			if a.ActiveByDefault != nil {
+				fields["activation.activeByDefault"] = fmt.Sprint(*a.ActiveByDefault)
+			}
+			fields["activation.jdk"] = deref(a.JDK)
+			if a.OS != nil {
+				fields["activation.os.name"] = deref(a.OS.Name)
+				fields["activation.os.family"] = deref(a.OS.Family)
+				fields["activation.os.arch"] = deref(a.OS.Arch)
+				fields["activation.os.version"] = deref(a.OS.Version)
+			}
+			if a.Property != nil {
+				fields["activation.property"] = deref(a.Property.Name) + "=" + deref(a.Property.Value)
+			}
+			if a.File != nil {
+				fields["activation.file.exists"] = deref(a.File.Exists)
+				fields["activation.file.missing"] = deref(a.File.Missing)
+			}
+			fields["activation.packaging"] = deref(a.Packaging)
+			fields["activation.condition"] = deref(a.Condition)
+		}
+		fields["modules"] = strings.Join(p.ChildProjects(), ",")
+		entities[deref(p.ID)] = entity{fields: fields}
+	}
+	return entities
+}
+
+func sortedKeys(a, b map[string]entity) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, m := range []map[string]entity{a, b} {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedFieldKeys(a, b map[string]string) []string {
+	seen := map[string]bool{}
+	var keys []string
+	for _, m := range []map[string]string{a, b} {
+		for k := range m {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
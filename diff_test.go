@@ -0,0 +1,154 @@
+package gopom
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var diffBase = `<project>
+  <modelVersion>4.0.0</modelVersion>
+  <parent><groupId>org.springframework.boot</groupId><artifactId>spring-boot-starter-parent</artifactId><version>2.7.0</version></parent>
+  <artifactId>app</artifactId>
+  <version>1.0.0</version>
+  <properties>
+    <java.version>11</java.version>
+    <skipITs>true</skipITs>
+  </properties>
+  <dependencies>
+    <dependency><groupId>org.slf4j</groupId><artifactId>slf4j-api</artifactId><version>1.7.30</version></dependency>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><version>4.12</version><scope>test</scope></dependency>
+    <dependency><groupId>com.google.guava</groupId><artifactId>guava</artifactId><version>31.0-jre</version></dependency>
+  </dependencies>
+  <build>
+    <plugins>
+      <plugin>
+        <artifactId>maven-compiler-plugin</artifactId>
+        <version>3.8.1</version>
+        <configuration><release>11</release></configuration>
+      </plugin>
+      <plugin>
+        <artifactId>maven-surefire-plugin</artifactId>
+        <executions><execution><id>it</id><phase>integration-test</phase><goals><goal>test</goal></goals></execution></executions>
+      </plugin>
+    </plugins>
+  </build>
+  <repositories>
+    <repository><id>internal</id><url>http://repo.example.com</url></repository>
+  </repositories>
+  <profiles>
+    <profile><id>ci</id><properties><ci>true</ci></properties></profile>
+    <profile><id>old</id></profile>
+  </profiles>
+</project>`
+
+var diffHead = `<project>
+  <modelVersion>4.0.0</modelVersion>
+  <parent><groupId>org.springframework.boot</groupId><artifactId>spring-boot-starter-parent</artifactId><version>3.0.0</version></parent>
+  <artifactId>app</artifactId>
+  <version>1.1.0</version>
+  <properties>
+    <skipITs>true</skipITs>
+    <java.version>17</java.version>
+  </properties>
+  <dependencies>
+    <dependency><groupId>com.google.guava</groupId><artifactId>guava</artifactId><version>31.0-jre</version></dependency>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><version>4.13</version><scope>test</scope></dependency>
+    <dependency><groupId>org.junit.jupiter</groupId><artifactId>junit-jupiter</artifactId><version>5.9.0</version><scope>test</scope></dependency>
+  </dependencies>
+  <build>
+    <plugins>
+      <plugin>
+        <artifactId>maven-surefire-plugin</artifactId>
+        <executions><execution><id>it</id><phase>verify</phase><goals><goal>test</goal></goals></execution></executions>
+      </plugin>
+      <plugin>
+        <artifactId>maven-compiler-plugin</artifactId>
+        <version>3.10.1</version>
+        <configuration><release>17</release></configuration>
+      </plugin>
+    </plugins>
+  </build>
+  <repositories>
+    <repository><id>internal</id><url>https://repo.example.com</url></repository>
+  </repositories>
+  <profiles>
+    <profile><id>new</id></profile>
+    <profile><id>ci</id><properties><ci>false</ci></properties></profile>
+  </profiles>
+</project>`
+
+func parseDiffPoms(t *testing.T) (*Project, *Project) {
+	a, err := ParseFromReader(strings.NewReader(diffBase))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := ParseFromReader(strings.NewReader(diffHead))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a, b
+}
+
+func Test_DiffText(t *testing.T) {
+	a, b := parseDiffPoms(t)
+
+	var buf bytes.Buffer
+	assert.Nil(t, Diff(a, b).WriteText(&buf))
+	assert.Equal(t, `~ project version: 1.0.0 -> 1.1.0
+~ parent org.springframework.boot:spring-boot-starter-parent version: 2.7.0 -> 3.0.0
+~ properties java.version: 11 -> 17
+~ dependencies junit:junit:jar version: 4.12 -> 4.13
++ dependencies org.junit.jupiter:junit-jupiter:jar 5.9.0
+- dependencies org.slf4j:slf4j-api:jar 1.7.30
+~ build.plugins org.apache.maven.plugins:maven-compiler-plugin configuration.release: 11 -> 17
+~ build.plugins org.apache.maven.plugins:maven-compiler-plugin version: 3.8.1 -> 3.10.1
+~ build.plugins org.apache.maven.plugins:maven-surefire-plugin executions[it].phase: integration-test -> verify
+~ repositories internal url: http://repo.example.com -> https://repo.example.com
++ profiles new
+- profiles old
+~ profiles[ci].properties ci: true -> false
+`, buf.String())
+}
+
+func Test_DiffJSON(t *testing.T) {
+	a, b := parseDiffPoms(t)
+
+	var buf bytes.Buffer
+	assert.Nil(t, Diff(a, b).WriteJSON(&buf))
+
+	var decoded ProjectDiff
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, Change{Section: "dependencies", Key: "junit:junit:jar", Type: ChangeChanged, Field: "version", Old: "4.12", New: "4.13"}, decoded.Changes[3])
+	assert.Contains(t, buf.String(), `"type": "added"`)
+}
+
+func Test_DiffIgnoresReordering(t *testing.T) {
+	a, _ := parseDiffPoms(t)
+	b, _ := parseDiffPoms(t)
+	deps := *b.Dependencies
+	deps[0], deps[2] = deps[2], deps[0]
+	assert.True(t, Diff(a, b).Empty())
+}
+
+func Test_DiffProfileActivation(t *testing.T) {
+	parse := func(activation string) *Project {
+		p, err := ParseFromReader(strings.NewReader(`<project><profiles><profile><id>native</id><activation>` + activation + `</activation></profile></profiles></project>`))
+		assert.Nil(t, err)
+		return p
+	}
+	a := parse(`<os><family>unix</family></os><file><exists>src/native</exists></file><packaging>jar</packaging>`)
+	b := parse(`<os><family>windows</family><arch>amd64</arch></os><file><missing>src/native</missing></file><packaging>war</packaging>`)
+
+	var buf bytes.Buffer
+	assert.Nil(t, Diff(a, b).WriteText(&buf))
+	assert.Equal(t, `~ profiles native activation.file.exists: src/native -> ""
+~ profiles native activation.file.missing: "" -> src/native
+~ profiles native activation.os.arch: "" -> amd64
+~ profiles native activation.os.family: unix -> windows
+~ profiles native activation.packaging: jar -> war
+`, buf.String())
+}
@@ -0,0 +1,387 @@
+package gopom
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Document is the text of a pom that can be edited in place. Edits only
+// touch the affected elements, so comments, indentation and the order of
+// everything else are preserved.
+//
+// Elements are addressed by paths relative to the project element, one
+// element name per segment, optionally followed by an index among the
+// siblings of that name, e.g. "dependencies", "dependency[2]", "version".
+type Document struct {
+	src  []byte
+	root *docElement
+}
+
+// docElement is the position of an element in the source.
+type docElement struct {
+	name string
+	// start and end span the whole element, contentStart and contentEnd
+	// the text between its tags.
+	start, end               int
+	contentStart, contentEnd int
+	selfClosing              bool
+	children                 []*docElement
+}
+
+// ParseDocument parses the text of a pom for editing.
+func ParseDocument(src []byte) (*Document, error) {
+	d := &Document{}
+	if err := d.reset(src); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+func (d *Document) reset(src []byte) error {
+	dec := xml.NewDecoder(bytes.NewReader(src))
+	var root *docElement
+	var stack []*docElement
+	for {
+		offset := int(dec.InputOffset())
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			e := &docElement{name: t.Name.Local, start: offset, contentStart: int(dec.InputOffset())}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, e)
+			} else if root == nil {
+				root = e
+			}
+			stack = append(stack, e)
+		case xml.EndElement:
+			if len(stack) == 0 {
+				return fmt.Errorf("unexpected end element </%s>", t.Name.Local)
+			}
+			e := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			e.end = int(dec.InputOffset())
+			e.contentEnd = offset
+			e.selfClosing = e.end == offset
+		}
+	}
+	if root == nil {
+		return fmt.Errorf("document has no root element")
+	}
+	if len(stack) > 0 {
+		return fmt.Errorf("element <%s> is not closed", stack[len(stack)-1].name)
+	}
+	d.src, d.root = src, root
+	return nil
+}
+
+// Bytes returns the current text of the document.
+func (d *Document) Bytes() []byte {
+	return d.src
+}
+
+// Project decodes the current text of the document.
+func (d *Document) Project() (*Project, error) {
+	return ParseFromReader(bytes.NewReader(d.src))
+}
+
+// Has reports whether the element at path exists.
+func (d *Document) Has(path ...string) bool {
+	e, _, _, err := d.find(path)
+	return err == nil && e != nil
+}
+
+// Text returns the unescaped text of the element at path.
+func (d *Document) Text(path ...string) (string, error) {
+	e, _, _, err := d.find(path)
+	if err != nil {
+		return "", err
+	}
+	if e == nil {
+		return "", fmt.Errorf("%s not found", strings.Join(path, "."))
+	}
+	var text strings.Builder
+	dec := xml.NewDecoder(bytes.NewReader(d.src[e.start:e.end]))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return text.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		if c, ok := tok.(xml.CharData); ok {
+			text.Write(c)
+		}
+	}
+}
+
+// SetText sets the text of the element at path, creating it and any missing
+// ancestors at the end of their parents.
+func (d *Document) SetText(value string, path ...string) error {
+	var escaped bytes.Buffer
+	if err := xml.EscapeText(&escaped, []byte(value)); err != nil {
+		return err
+	}
+	e, ancestor, missing, err := d.find(path)
+	if err != nil {
+		return err
+	}
+	if e == nil {
+		return d.insert(ancestor, d.chain(missing, escaped.String(), d.childIndent(ancestor), d.inline(ancestor)))
+	}
+	if len(e.children) > 0 {
+		return fmt.Errorf("%s has child elements", strings.Join(path, "."))
+	}
+	if e.selfClosing {
+		return d.splice(e.start, e.end, d.openTag(e)+escaped.String()+"</"+e.name+">")
+	}
+	return d.splice(e.contentStart, e.contentEnd, escaped.String())
+}
+
+// Remove removes the element at path together with the line it was on when
+// nothing else is on that line. Removing a missing element is not an error.
+func (d *Document) Remove(path ...string) error {
+	e, _, _, err := d.find(path)
+	if err != nil || e == nil {
+		return err
+	}
+	start, end := e.start, e.end
+	lineStart := start
+	for lineStart > 0 && (d.src[lineStart-1] == ' ' || d.src[lineStart-1] == '\t') {
+		lineStart--
+	}
+	lineEnd := end
+	for lineEnd < len(d.src) && (d.src[lineEnd] == ' ' || d.src[lineEnd] == '\t' || d.src[lineEnd] == '\r') {
+		lineEnd++
+	}
+	if (lineStart == 0 || d.src[lineStart-1] == '\n') && (lineEnd == len(d.src) || d.src[lineEnd] == '\n') {
+		start, end = lineStart, lineEnd
+		if end < len(d.src) {
+			end++
+		} else if start > 0 {
+			start--
+		}
+	}
+	return d.splice(start, end, "")
+}
+
+// Append marshals v as an element named name and appends it to the children
+// of the element at path, which is created when it is missing.
+func (d *Document) Append(v interface{}, name string, path ...string) error {
+	e, _, _, err := d.find(path)
+	if err != nil {
+		return err
+	}
+	if e == nil {
+		if err := d.SetText("", path...); err != nil {
+			return err
+		}
+		if e, _, _, err = d.find(path); err != nil {
+			return err
+		}
+	}
+	fragment, err := d.marshal(v, name, d.childIndent(e), d.inline(e))
+	if err != nil {
+		return err
+	}
+	return d.insert(e, fragment)
+}
+
+// Replace marshals v in place of the element at path, keeping its name.
+func (d *Document) Replace(v interface{}, path ...string) error {
+	e, _, _, err := d.find(path)
+	if err != nil {
+		return err
+	}
+	if e == nil {
+		return fmt.Errorf("%s not found", strings.Join(path, "."))
+	}
+	fragment, err := d.marshal(v, e.name, d.lineIndent(e.start), !d.startsLine(e.start))
+	if err != nil {
+		return err
+	}
+	return d.splice(e.start, e.end, fragment)
+}
+
+// find returns the element at path. When it is missing, find returns its
+// nearest existing ancestor and the segments of path below that ancestor.
+func (d *Document) find(path []string) (e, ancestor *docElement, missing []string, err error) {
+	e = d.root
+	for i, segment := range path {
+		name, index, err := parseSegment(segment)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		var next *docElement
+		for _, child := range e.children {
+			if child.name == name {
+				if index == 0 {
+					next = child
+					break
+				}
+				index--
+			}
+		}
+		if next == nil {
+			return nil, e, path[i:], nil
+		}
+		e = next
+	}
+	return e, nil, nil, nil
+}
+
+func parseSegment(segment string) (string, int, error) {
+	open := strings.IndexByte(segment, '[')
+	if open < 0 {
+		return segment, 0, nil
+	}
+	if !strings.HasSuffix(segment, "]") {
+		return "", 0, fmt.Errorf("invalid path segment %q", segment)
+	}
+	index, err := strconv.Atoi(segment[open+1 : len(segment)-1])
+	if err != nil || index < 0 {
+		return "", 0, fmt.Errorf("invalid path segment %q", segment)
+	}
+	return segment[:open], index, nil
+}
+
+// chain renders nested new elements for the path segments, the innermost
+// holding text, on one line when they are inline.
+func (d *Document) chain(segments []string, text, indent string, inline bool) string {
+	name, _, _ := parseSegment(segments[0])
+	if len(segments) == 1 {
+		return "<" + name + ">" + text + "</" + name + ">"
+	}
+	if inline {
+		return "<" + name + ">" + d.chain(segments[1:], text, indent, true) + "</" + name + ">"
+	}
+	inner := indent + d.indentUnit()
+	return "<" + name + ">\n" + inner + d.chain(segments[1:], text, inner, false) + "\n" + indent + "</" + name + ">"
+}
+
+// insert adds an already indented fragment as the last child of e. The
+// children of inline elements are added on the same line.
+func (d *Document) insert(e *docElement, fragment string) error {
+	if d.inline(e) {
+		if len(e.children) > 0 {
+			pos := e.children[len(e.children)-1].end
+			return d.splice(pos, pos, fragment)
+		}
+		if e.selfClosing {
+			return d.splice(e.start, e.end, d.openTag(e)+fragment+"</"+e.name+">")
+		}
+		if strings.TrimSpace(string(d.src[e.contentStart:e.contentEnd])) != "" {
+			return fmt.Errorf("<%s> has text content", e.name)
+		}
+		return d.splice(e.contentStart, e.contentEnd, fragment)
+	}
+	indent := d.childIndent(e)
+	if len(e.children) > 0 {
+		// Insert after the rest of the line of the last child, e.g. a trailing comment.
+		pos := e.children[len(e.children)-1].end
+		if newline := bytes.IndexByte(d.src[pos:e.contentEnd], '\n'); newline >= 0 {
+			pos += newline
+			if pos > 0 && d.src[pos-1] == '\r' {
+				pos--
+			}
+		}
+		return d.splice(pos, pos, "\n"+indent+fragment)
+	}
+	closing := "\n" + d.lineIndent(e.start) + "</" + e.name + ">"
+	if e.selfClosing {
+		return d.splice(e.start, e.end, d.openTag(e)+"\n"+indent+fragment+closing)
+	}
+	if strings.TrimSpace(string(d.src[e.contentStart:e.contentEnd])) != "" {
+		return fmt.Errorf("<%s> has text content", e.name)
+	}
+	return d.splice(e.contentStart, e.end, "\n"+indent+fragment+closing)
+}
+
+// openTag returns the start tag of a self-closing element as an opening tag.
+func (d *Document) openTag(e *docElement) string {
+	tag := strings.TrimSpace(strings.TrimSuffix(string(d.src[e.start:e.end]), "/>"))
+	return tag + ">"
+}
+
+func (d *Document) marshal(v interface{}, name, indent string, inline bool) (string, error) {
+	var buf bytes.Buffer
+	enc := xml.NewEncoder(&buf)
+	if !inline {
+		enc.Indent(indent, d.indentUnit())
+	}
+	if err := enc.EncodeElement(v, xml.StartElement{Name: xml.Name{Local: name}}); err != nil {
+		return "", err
+	}
+	if err := enc.Flush(); err != nil {
+		return "", err
+	}
+	return strings.TrimPrefix(buf.String(), indent), nil
+}
+
+// startsLine reports whether only whitespace precedes pos on its line.
+func (d *Document) startsLine(pos int) bool {
+	for pos > 0 && (d.src[pos-1] == ' ' || d.src[pos-1] == '\t') {
+		pos--
+	}
+	return pos == 0 || d.src[pos-1] == '\n'
+}
+
+// inline reports whether the children of e are on the line of its start
+// tag, e.g. <dependency><groupId>g</groupId></dependency>, or would be as e
+// shares its line with other elements.
+func (d *Document) inline(e *docElement) bool {
+	if len(e.children) > 0 {
+		return !d.startsLine(e.children[len(e.children)-1].start)
+	}
+	return e != d.root && !d.startsLine(e.start)
+}
+
+// lineIndent returns the whitespace before pos when pos starts its line.
+func (d *Document) lineIndent(pos int) string {
+	start := pos
+	for start > 0 && (d.src[start-1] == ' ' || d.src[start-1] == '\t') {
+		start--
+	}
+	if start > 0 && d.src[start-1] != '\n' {
+		return ""
+	}
+	return string(d.src[start:pos])
+}
+
+// childIndent returns the indentation for a new child of e, following its
+// existing children when it has some.
+func (d *Document) childIndent(e *docElement) string {
+	if len(e.children) > 0 {
+		return d.lineIndent(e.children[len(e.children)-1].start)
+	}
+	return d.lineIndent(e.start) + d.indentUnit()
+}
+
+// indentUnit guesses one level of indentation from the first child of the project.
+func (d *Document) indentUnit() string {
+	if len(d.root.children) > 0 {
+		if unit := d.lineIndent(d.root.children[0].start); unit != "" {
+			return unit
+		}
+	}
+	return "  "
+}
+
+func (d *Document) splice(start, end int, text string) error {
+	src := make([]byte, 0, len(d.src)-(end-start)+len(text))
+	src = append(src, d.src[:start]...)
+	src = append(src, text...)
+	src = append(src, d.src[end:]...)
+	return d.reset(src)
+}
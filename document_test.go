@@ -0,0 +1,173 @@
+package gopom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const documentPom = `<?xml version="1.0" encoding="UTF-8"?>
+<!-- keep me -->
+<project>
+    <groupId>com.example</groupId>
+    <artifactId>app</artifactId>
+    <version>1.0</version>
+    <properties>
+        <java.version>11</java.version> <!-- LTS -->
+    </properties>
+    <dependencies>
+        <dependency>
+            <groupId>junit</groupId>
+            <artifactId>junit</artifactId>
+            <version>4.12</version>
+        </dependency>
+        <dependency>
+            <groupId>org.slf4j</groupId>
+            <artifactId>slf4j-api</artifactId>
+            <version>1.7.30</version>
+        </dependency>
+    </dependencies>
+    <modules/>
+</project>
+`
+
+func Test_DocumentSetText(t *testing.T) {
+	doc, err := ParseDocument([]byte(documentPom))
+	assert.Nil(t, err)
+
+	assert.Nil(t, doc.SetText("1.1", "version"))
+	assert.Nil(t, doc.SetText("17", "properties", "java.version"))
+	assert.Nil(t, doc.SetText("a&b", "properties", "new.property"))
+	assert.Nil(t, doc.SetText("test", "dependencies", "dependency[0]", "scope"))
+	assert.Nil(t, doc.SetText("My app", "name"))
+	assert.Nil(t, doc.SetText("core", "modules", "module"))
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<!-- keep me -->
+<project>
+    <groupId>com.example</groupId>
+    <artifactId>app</artifactId>
+    <version>1.1</version>
+    <properties>
+        <java.version>17</java.version> <!-- LTS -->
+        <new.property>a&amp;b</new.property>
+    </properties>
+    <dependencies>
+        <dependency>
+            <groupId>junit</groupId>
+            <artifactId>junit</artifactId>
+            <version>4.12</version>
+            <scope>test</scope>
+        </dependency>
+        <dependency>
+            <groupId>org.slf4j</groupId>
+            <artifactId>slf4j-api</artifactId>
+            <version>1.7.30</version>
+        </dependency>
+    </dependencies>
+    <modules>
+        <module>core</module>
+    </modules>
+    <name>My app</name>
+</project>
+`
+	assert.Equal(t, expected, string(doc.Bytes()))
+
+	text, err := doc.Text("properties", "new.property")
+	assert.Nil(t, err)
+	assert.Equal(t, "a&b", text)
+	assert.Error(t, doc.SetText("x", "dependencies"))
+}
+
+func Test_DocumentRemove(t *testing.T) {
+	doc, err := ParseDocument([]byte(documentPom))
+	assert.Nil(t, err)
+
+	assert.Nil(t, doc.Remove("dependencies", "dependency[0]"))
+	assert.Nil(t, doc.Remove("properties"))
+	assert.Nil(t, doc.Remove("missing"))
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<!-- keep me -->
+<project>
+    <groupId>com.example</groupId>
+    <artifactId>app</artifactId>
+    <version>1.0</version>
+    <dependencies>
+        <dependency>
+            <groupId>org.slf4j</groupId>
+            <artifactId>slf4j-api</artifactId>
+            <version>1.7.30</version>
+        </dependency>
+    </dependencies>
+    <modules/>
+</project>
+`
+	assert.Equal(t, expected, string(doc.Bytes()))
+	assert.False(t, doc.Has("dependencies", "dependency[1]"))
+}
+
+func Test_DocumentAppendAndReplace(t *testing.T) {
+	doc, err := ParseDocument([]byte(documentPom))
+	assert.Nil(t, err)
+
+	dep := Dependency{GroupID: str("org.assertj"), ArtifactID: str("assertj-core"), Version: str("3.19.0"), Scope: str("test")}
+	assert.Nil(t, doc.Append(dep, "dependency", "dependencies"))
+	assert.Nil(t, doc.Replace(Dependency{GroupID: str("org.slf4j"), ArtifactID: str("slf4j-api"), Version: str("2.0.0")}, "dependencies", "dependency[1]"))
+	assert.Nil(t, doc.Append(Plugin{ArtifactID: str("maven-jar-plugin")}, "plugin", "build", "plugins"))
+
+	project, err := doc.Project()
+	assert.Nil(t, err)
+	deps := *project.Dependencies
+	assert.Len(t, deps, 3)
+	assert.Equal(t, "2.0.0", *deps[1].Version)
+	assert.Equal(t, "assertj-core", *deps[2].ArtifactID)
+	assert.Equal(t, "maven-jar-plugin", *(*project.Build.Plugins)[0].ArtifactID)
+
+	assert.Contains(t, string(doc.Bytes()), `        </dependency>
+        <dependency>
+            <groupId>org.assertj</groupId>
+            <artifactId>assertj-core</artifactId>
+            <version>3.19.0</version>
+            <scope>test</scope>
+        </dependency>
+    </dependencies>`)
+	assert.Contains(t, string(doc.Bytes()), `    <modules/>
+    <build>
+        <plugins>
+            <plugin>
+                <artifactId>maven-jar-plugin</artifactId>
+            </plugin>
+        </plugins>
+    </build>
+</project>`)
+}
+
+func Test_DocumentInlineElements(t *testing.T) {
+	doc, err := ParseDocument([]byte(`<project>
+  <dependencies>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId></dependency>
+  </dependencies>
+</project>
+`))
+	assert.Nil(t, err)
+
+	assert.Nil(t, doc.SetText("test", "dependencies", "dependency[0]", "scope"))
+	assert.Nil(t, doc.SetText("a", "dependencies", "dependency[0]", "exclusions", "exclusion", "groupId"))
+	assert.Nil(t, doc.Append(Exclusion{GroupID: str("b"), ArtifactID: str("c")}, "exclusion", "dependencies", "dependency[0]", "exclusions"))
+
+	expected := `<project>
+  <dependencies>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><scope>test</scope><exclusions><exclusion><groupId>a</groupId></exclusion><exclusion><artifactId>c</artifactId><groupId>b</groupId></exclusion></exclusions></dependency>
+  </dependencies>
+</project>
+`
+	assert.Equal(t, expected, string(doc.Bytes()))
+}
+
+func Test_ParseDocumentErrors(t *testing.T) {
+	_, err := ParseDocument([]byte("<project><version>1</project>"))
+	assert.Error(t, err)
+	_, err = ParseDocument([]byte("<!-- empty -->"))
+	assert.EqualError(t, err, "document has no root element")
+}
@@ -0,0 +1,505 @@
+package gopom
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// PomLoader loads the pom of an artifact, e.g. from a local repository.
+type PomLoader interface {
+	Load(c Coordinates) (*Project, error)
+}
+
+// VersionLister is implemented by loaders that can list the available
+// versions of an artifact, which is needed to resolve version ranges.
+type VersionLister interface {
+	Versions(key ArtifactKey) ([]string, error)
+}
+
+// LocalRepository loads poms from a local Maven repository directory such as ~/.m2/repository.
+type LocalRepository string
+
+// Path returns the path of the file with the given extension for the artifact in the repository.
+func (r LocalRepository) Path(c Coordinates, extension string) string {
+	name := c.ArtifactID + "-" + c.Version
+	if c.Classifier != "" {
+		name += "-" + c.Classifier
+	}
+	dir := filepath.Join(append([]string{string(r)}, strings.Split(c.GroupID, ".")...)...)
+	return filepath.Join(dir, c.ArtifactID, c.Version, name+"."+extension)
+}
+
+// Load parses the pom of the artifact. The error satisfies os.IsNotExist when
+// the repository does not contain it.
+func (r LocalRepository) Load(c Coordinates) (*Project, error) {
+	return Parse(r.Path(Coordinates{GroupID: c.GroupID, ArtifactID: c.ArtifactID, Version: c.Version}, "pom"))
+}
+
+// Versions lists the versions of the artifact found in the repository.
+func (r LocalRepository) Versions(key ArtifactKey) ([]string, error) {
+	dir := filepath.Dir(filepath.Dir(r.Path(Coordinates{GroupID: key.GroupID, ArtifactID: key.ArtifactID, Version: "v"}, "pom")))
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var versions []string
+	for _, info := range infos {
+		if info.IsDir() {
+			versions = append(versions, info.Name())
+		}
+	}
+	return versions, nil
+}
+
+// EffectiveProject builds the effective model of p the way Maven does before
+// a build: the parents are loaded and inherited, imported boms are merged into
+// dependencyManagement, expressions are interpolated and the managed versions,
+// scopes and exclusions are applied to dependencies and plugins. Profiles are
+// not activated. p itself is not modified.
+func EffectiveProject(p *Project, loader PomLoader) (*Project, error) {
+	return effectiveProject(p, loader, map[string]bool{})
+}
+
+func effectiveProject(p *Project, loader PomLoader, building map[string]bool) (*Project, error) {
+	gav := p.Coordinates().GAV()
+	if building[gav] {
+		return nil, fmt.Errorf("cycle in parents or imported boms of %s", gav)
+	}
+	building[gav] = true
+	defer delete(building, gav)
+
+	result, err := cloneProject(p)
+	if err != nil {
+		return nil, err
+	}
+	if err := inheritParents(result, p, loader, building); err != nil {
+		return nil, err
+	}
+
+	// Interpolating after inheritance lets the properties and coordinates of
+	// the child apply to what it inherits, as Maven does.
+	interpolateStrings(reflect.ValueOf(result), result)
+
+	if err := importBoms(result, loader, building); err != nil {
+		return nil, err
+	}
+	applyManagement(result)
+	return result, nil
+}
+
+// inheritParents merges the chain of parents of p into result. The parents are
+// not interpolated, so that their expressions are resolved for the child.
+func inheritParents(result, p *Project, loader PomLoader, building map[string]bool) error {
+	if p.Parent == nil {
+		return nil
+	}
+	parentPom, err := loader.Load(p.Parent.Coordinates())
+	if err != nil {
+		return fmt.Errorf("loading parent %s of %s: %v", p.Parent.Coordinates(), p.Coordinates().GAV(), err)
+	}
+	gav := parentPom.Coordinates().GAV()
+	if building[gav] {
+		return fmt.Errorf("cycle in parents or imported boms of %s", gav)
+	}
+	building[gav] = true
+	defer delete(building, gav)
+
+	parent, err := cloneProject(parentPom)
+	if err != nil {
+		return err
+	}
+	if err := inheritParents(parent, parentPom, loader, building); err != nil {
+		return err
+	}
+	inherit(result, parent)
+	return nil
+}
+
+func cloneProject(p *Project) (*Project, error) {
+	b, err := xml.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	var clone Project
+	if err := xml.Unmarshal(b, &clone); err != nil {
+		return nil, err
+	}
+	clone.Locations = p.Locations
+	return &clone, nil
+}
+
+// notInherited are the fields of Project that children never take from their parent.
+var notInherited = map[string]bool{
+	"XMLName":                   true,
+	"Xmlns":                     true,
+	"Root":                      true,
+	"ChildURLInheritAppendPath": true,
+	"ModelVersion":              true,
+	"Parent":                    true,
+	"ArtifactID":                true,
+	"Packaging":                 true,
+	"Name":                      true,
+	"Prerequisites":             true,
+	"Modules":                   true,
+	"Subprojects":               true,
+	"Profiles":                  true,
+	"Locations":                 true,
+}
+
+// inherit merges the effective parent into the child: elements missing in the
+// child are taken from the parent, and keyed lists are merged with the
+// entries of the child taking precedence.
+func inherit(child, parent *Project) {
+	appendPath := deref(parent.ChildURLInheritAppendPath) != "false"
+	if child.URL == nil && parent.URL != nil && appendPath {
+		url := appendArtifactID(*parent.URL, deref(child.ArtifactID))
+		child.URL = &url
+	}
+	if child.SCM != nil && parent.SCM != nil {
+		inheritScm(child.SCM, parent.SCM, deref(child.ArtifactID))
+	} else if child.SCM == nil && parent.SCM != nil {
+		scm := *parent.SCM
+		child.SCM = &Scm{}
+		inheritScm(child.SCM, &scm, deref(child.ArtifactID))
+	}
+
+	child.Properties = mergeProperties(child.Properties, parent.Properties)
+	child.Dependencies = mergeDependencies(child.Dependencies, parent.Dependencies)
+	if parent.DependencyManagement != nil {
+		if child.DependencyManagement == nil {
+			child.DependencyManagement = &DependencyManagement{}
+		}
+		child.DependencyManagement.Dependencies = mergeDependencies(child.DependencyManagement.Dependencies, parent.DependencyManagement.Dependencies)
+	}
+	child.Repositories = mergeRepositories(child.Repositories, parent.Repositories)
+	if parent.Build != nil {
+		if child.Build == nil {
+			child.Build = &Build{}
+		}
+		inheritBuild(child.Build, parent.Build)
+	}
+
+	cv, pv := reflect.ValueOf(child).Elem(), reflect.ValueOf(parent).Elem()
+	for i := 0; i < cv.NumField(); i++ {
+		name := cv.Type().Field(i).Name
+		if notInherited[name] {
+			continue
+		}
+		if f := cv.Field(i); f.Kind() == reflect.Ptr && f.IsNil() {
+			f.Set(pv.Field(i))
+		}
+	}
+}
+
+func appendArtifactID(url, artifactID string) string {
+	if url == "" || artifactID == "" {
+		return url
+	}
+	return strings.TrimSuffix(url, "/") + "/" + artifactID
+}
+
+func inheritScm(child, parent *Scm, artifactID string) {
+	inheritURL := func(c **string, p *string, appendPath *string) {
+		if *c != nil || p == nil {
+			return
+		}
+		url := *p
+		if deref(appendPath) != "false" {
+			url = appendArtifactID(url, artifactID)
+		}
+		*c = &url
+	}
+	inheritURL(&child.Connection, parent.Connection, parent.ChildConnectionInheritAppendPath)
+	inheritURL(&child.DeveloperConnection, parent.DeveloperConnection, parent.ChildDeveloperConnectionInheritAppendPath)
+	inheritURL(&child.URL, parent.URL, parent.ChildURLInheritAppendPath)
+	if child.Tag == nil {
+		child.Tag = parent.Tag
+	}
+}
+
+func inheritBuild(child, parent *Build) {
+	cv, pv := reflect.ValueOf(child).Elem(), reflect.ValueOf(parent).Elem()
+	for i := 0; i < cv.NumField(); i++ {
+		if f := cv.Field(i); f.Kind() == reflect.Ptr && f.IsNil() {
+			f.Set(pv.Field(i))
+		}
+	}
+	child.Plugins = mergePlugins(child.Plugins, parent.Plugins, true)
+	if parent.PluginManagement != nil {
+		if child.PluginManagement == nil {
+			child.PluginManagement = &PluginManagement{}
+		}
+		child.PluginManagement.Plugins = mergePlugins(child.PluginManagement.Plugins, parent.PluginManagement.Plugins, false)
+	}
+	bv, pbv := reflect.ValueOf(&child.BuildBase).Elem(), reflect.ValueOf(&parent.BuildBase).Elem()
+	for i := 0; i < bv.NumField(); i++ {
+		if f := bv.Field(i); f.Kind() == reflect.Ptr && f.IsNil() {
+			f.Set(pbv.Field(i))
+		}
+	}
+}
+
+func mergeProperties(child, parent *Properties) *Properties {
+	if parent == nil {
+		return child
+	}
+	merged := &Properties{Entries: map[string]string{}}
+	add := func(p *Properties) {
+		for k, v := range p.Entries {
+			merged.Entries[k] = v
+			delete(merged.RawXML, k)
+			if raw, ok := p.RawXML[k]; ok {
+				if merged.RawXML == nil {
+					merged.RawXML = map[string]string{}
+				}
+				merged.RawXML[k] = raw
+			}
+		}
+	}
+	add(parent)
+	if child != nil {
+		add(child)
+	}
+	return merged
+}
+
+func mergeDependencies(child, parent *[]Dependency) *[]Dependency {
+	if parent == nil {
+		return child
+	}
+	var merged []Dependency
+	seen := map[string]bool{}
+	if child != nil {
+		for _, d := range *child {
+			seen[d.Coordinates().ManagementKey()] = true
+		}
+	}
+	for _, d := range *parent {
+		if !seen[d.Coordinates().ManagementKey()] {
+			merged = append(merged, d)
+		}
+	}
+	if child != nil {
+		merged = append(merged, *child...)
+	}
+	return &merged
+}
+
+func mergeRepositories(child, parent *[]Repository) *[]Repository {
+	if parent == nil {
+		return child
+	}
+	var merged []Repository
+	seen := map[string]bool{}
+	if child != nil {
+		merged = append(merged, *child...)
+		for _, r := range *child {
+			seen[deref(r.ID)] = true
+		}
+	}
+	for _, r := range *parent {
+		if !seen[deref(r.ID)] {
+			merged = append(merged, r)
+		}
+	}
+	return &merged
+}
+
+// mergePlugins merges the plugins of the parent into the ones of the child.
+// Plugins the parent marks as not inherited are skipped when honorInherited is set.
+func mergePlugins(child, parent *[]Plugin, honorInherited bool) *[]Plugin {
+	if parent == nil {
+		return child
+	}
+	var merged []Plugin
+	index := map[string]int{}
+	for _, p := range *parent {
+		if inherited, err := p.IsInherited(nil); honorInherited && err == nil && !inherited {
+			continue
+		}
+		index[p.Coordinates().Key().String()] = len(merged)
+		merged = append(merged, p)
+	}
+	if child != nil {
+		for _, c := range *child {
+			key := c.Coordinates().Key().String()
+			if i, ok := index[key]; ok {
+				merged[i] = mergePlugin(c, merged[i])
+			} else {
+				index[key] = len(merged)
+				merged = append(merged, c)
+			}
+		}
+	}
+	return &merged
+}
+
+// mergePlugin overlays plugin c on plugin p.
+func mergePlugin(c, p Plugin) Plugin {
+	if c.GroupID == nil {
+		c.GroupID = p.GroupID
+	}
+	if c.Version == nil {
+		c.Version = p.Version
+	}
+	if c.Extensions == nil {
+		c.Extensions = p.Extensions
+	}
+	c.Configuration = mergeProperties(c.Configuration, p.Configuration)
+	c.Dependencies = mergeDependencies(c.Dependencies, p.Dependencies)
+	if p.Executions != nil {
+		var executions []PluginExecution
+		ids := map[string]bool{}
+		if c.Executions != nil {
+			for _, e := range *c.Executions {
+				ids[derefOr(e.ID, "default")] = true
+			}
+		}
+		for _, e := range *p.Executions {
+			if !ids[derefOr(e.ID, "default")] {
+				executions = append(executions, e)
+			}
+		}
+		if c.Executions != nil {
+			executions = append(executions, *c.Executions...)
+		}
+		c.Executions = &executions
+	}
+	return c
+}
+
+// interpolateStrings replaces the expressions in every string of v.
+func interpolateStrings(v reflect.Value, p *Project) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		if s, ok := v.Interface().(*string); ok {
+			*s = p.Interpolate(*s)
+			return
+		}
+		if props, ok := v.Interface().(*Properties); ok {
+			for k, value := range props.Entries {
+				props.Entries[k] = p.Interpolate(value)
+			}
+			return
+		}
+		interpolateStrings(v.Elem(), p)
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			interpolateStrings(v.Index(i).Addr(), p)
+		}
+	case reflect.String:
+		if v.CanSet() {
+			v.SetString(p.Interpolate(v.String()))
+		}
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(Properties{}) {
+			interpolateStrings(v.Addr(), p)
+			return
+		}
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Field(i)
+			if f.Kind() == reflect.Struct && f.CanAddr() {
+				interpolateStrings(f.Addr(), p)
+			} else {
+				interpolateStrings(f, p)
+			}
+		}
+	}
+}
+
+// importBoms replaces the dependencies with scope import in
+// dependencyManagement by the dependencyManagement of the imported boms.
+func importBoms(p *Project, loader PomLoader, building map[string]bool) error {
+	if p.DependencyManagement == nil || p.DependencyManagement.Dependencies == nil {
+		return nil
+	}
+	var managed, imported []Dependency
+	for _, d := range *p.DependencyManagement.Dependencies {
+		if deref(d.Scope) != string(ScopeImport) {
+			managed = append(managed, d)
+			continue
+		}
+		bomPom, err := loader.Load(d.Coordinates())
+		if err != nil {
+			return fmt.Errorf("loading imported bom %s: %v", d.Coordinates(), err)
+		}
+		bom, err := effectiveProject(bomPom, loader, building)
+		if err != nil {
+			return err
+		}
+		if bom.DependencyManagement != nil && bom.DependencyManagement.Dependencies != nil {
+			imported = append(imported, *bom.DependencyManagement.Dependencies...)
+		}
+	}
+
+	// Entries declared directly win over imported ones, and earlier imports over later ones.
+	seen := map[string]bool{}
+	for _, d := range managed {
+		seen[d.Coordinates().ManagementKey()] = true
+	}
+	for _, d := range imported {
+		key := d.Coordinates().ManagementKey()
+		if !seen[key] {
+			seen[key] = true
+			managed = append(managed, d)
+		}
+	}
+	p.DependencyManagement.Dependencies = &managed
+	return nil
+}
+
+// applyManagement fills in the versions, scopes, exclusions and optional
+// flags of dependencies, and the versions and configuration of plugins, from
+// the management sections.
+func applyManagement(p *Project) {
+	if p.Dependencies != nil && p.DependencyManagement != nil && p.DependencyManagement.Dependencies != nil {
+		managed := map[string]Dependency{}
+		for _, d := range *p.DependencyManagement.Dependencies {
+			managed[d.Coordinates().ManagementKey()] = d
+		}
+		for i := range *p.Dependencies {
+			d := &(*p.Dependencies)[i]
+			if m, ok := managed[d.Coordinates().ManagementKey()]; ok {
+				manageDependency(d, m)
+			}
+		}
+	}
+
+	if p.Build != nil && p.Build.Plugins != nil && p.Build.PluginManagement != nil && p.Build.PluginManagement.Plugins != nil {
+		managed := map[string]Plugin{}
+		for _, m := range *p.Build.PluginManagement.Plugins {
+			managed[m.Coordinates().Key().String()] = m
+		}
+		for i := range *p.Build.Plugins {
+			plugin := &(*p.Build.Plugins)[i]
+			if m, ok := managed[plugin.Coordinates().Key().String()]; ok {
+				*plugin = mergePlugin(*plugin, m)
+			}
+		}
+	}
+}
+
+func manageDependency(d *Dependency, m Dependency) {
+	if isEmpty(d.Version) {
+		d.Version = m.Version
+	}
+	if isEmpty(d.Scope) {
+		d.Scope = m.Scope
+	}
+	if d.Exclusions == nil {
+		d.Exclusions = m.Exclusions
+	}
+	if d.Optional == nil {
+		d.Optional = m.Optional
+	}
+	if d.SystemPath == nil {
+		d.SystemPath = m.SystemPath
+	}
+}
@@ -0,0 +1,197 @@
+package gopom
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var resolvePoms = map[string]string{
+	"org/acme/parent/1/parent-1.pom": `<project>
+  <groupId>org.acme</groupId><artifactId>parent</artifactId><version>1</version><packaging>pom</packaging>
+  <name>Acme parent</name>
+  <url>http://acme.org</url>
+  <properties><lib.version>2.0</lib.version></properties>
+  <dependencyManagement><dependencies>
+    <dependency><groupId>org.acme</groupId><artifactId>lib</artifactId><version>${lib.version}</version></dependency>
+    <dependency><groupId>org.acme</groupId><artifactId>bom</artifactId><version>1</version><type>pom</type><scope>import</scope></dependency>
+  </dependencies></dependencyManagement>
+  <build><pluginManagement><plugins>
+    <plugin><artifactId>maven-compiler-plugin</artifactId><version>3.8.1</version><configuration><release>11</release></configuration></plugin>
+  </plugins></pluginManagement></build>
+</project>`,
+	"org/acme/bom/1/bom-1.pom": `<project>
+  <groupId>org.acme</groupId><artifactId>bom</artifactId><version>1</version><packaging>pom</packaging>
+  <dependencyManagement><dependencies>
+    <dependency><groupId>org.acme</groupId><artifactId>util</artifactId><version>1.5</version></dependency>
+  </dependencies></dependencyManagement>
+</project>`,
+	"org/acme/lib/2.0/lib-2.0.pom": `<project>
+  <groupId>org.acme</groupId><artifactId>lib</artifactId><version>2.0</version>
+  <dependencies>
+    <dependency><groupId>org.acme</groupId><artifactId>util</artifactId><version>1.0</version></dependency>
+    <dependency><groupId>org.acme</groupId><artifactId>rt</artifactId><version>1</version><scope>runtime</scope></dependency>
+    <dependency><groupId>org.acme</groupId><artifactId>extra</artifactId><version>1</version><optional>true</optional></dependency>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><version>4.13</version><scope>test</scope></dependency>
+  </dependencies>
+</project>`,
+	"org/acme/lib/3.0/lib-3.0.pom": `<project>
+  <groupId>org.acme</groupId><artifactId>lib</artifactId><version>3.0</version>
+  <dependencies>
+    <dependency><groupId>org.acme</groupId><artifactId>util</artifactId><version>1.0</version></dependency>
+    <dependency><groupId>org.acme</groupId><artifactId>fresh</artifactId><version>1</version></dependency>
+  </dependencies>
+</project>`,
+	"org/acme/rt/1/rt-1.pom": `<project>
+  <groupId>org.acme</groupId><artifactId>rt</artifactId><version>1</version>
+  <dependencies>
+    <dependency><groupId>org.acme</groupId><artifactId>unwanted</artifactId><version>1</version></dependency>
+    <dependency><groupId>org.acme</groupId><artifactId>util</artifactId><version>0.9</version></dependency>
+  </dependencies>
+</project>`,
+}
+
+const resolveApp = `<project>
+  <parent><groupId>org.acme</groupId><artifactId>parent</artifactId><version>1</version></parent>
+  <artifactId>app</artifactId>
+  <dependencies>
+    <dependency>
+      <groupId>org.acme</groupId><artifactId>lib</artifactId>
+      <exclusions><exclusion><groupId>org.acme</groupId><artifactId>unwanted</artifactId></exclusion></exclusions>
+    </dependency>
+    <dependency><groupId>org.acme</groupId><artifactId>missing</artifactId><version>1</version></dependency>
+  </dependencies>
+  <build><plugins><plugin><artifactId>maven-compiler-plugin</artifactId></plugin></plugins></build>
+</project>`
+
+func Test_EffectiveProject(t *testing.T) {
+	repo := LocalRepository(writeRepository(t, resolvePoms))
+	app, err := ParseFromReader(strings.NewReader(resolveApp))
+	assert.Nil(t, err)
+
+	effective, err := EffectiveProject(app, repo)
+	assert.Nil(t, err)
+	assert.Equal(t, "org.acme", *effective.GroupID)
+	assert.Equal(t, "1", *effective.Version)
+	assert.Equal(t, "http://acme.org/app", *effective.URL)
+	assert.Nil(t, effective.Name)
+	assert.Nil(t, effective.Packaging)
+	assert.Equal(t, "2.0", effective.Properties.Entries["lib.version"])
+
+	deps := *effective.Dependencies
+	assert.Equal(t, "2.0", *deps[0].Version)
+
+	var managed []string
+	for _, d := range *effective.DependencyManagement.Dependencies {
+		managed = append(managed, d.Coordinates().String())
+	}
+	assert.Equal(t, []string{"org.acme:lib:2.0", "org.acme:util:1.5"}, managed)
+
+	plugin := (*effective.Build.Plugins)[0]
+	assert.Equal(t, "3.8.1", *plugin.Version)
+	assert.Equal(t, "11", plugin.Configuration.Entries["release"])
+
+	// The input is left untouched.
+	assert.Nil(t, app.GroupID)
+	assert.Nil(t, (*app.Dependencies)[0].Version)
+}
+
+func Test_EffectiveProjectNestedConfiguration(t *testing.T) {
+	repo := LocalRepository(writeRepository(t, map[string]string{
+		"org/acme/base/1/base-1.pom": `<project>
+  <groupId>org.acme</groupId><artifactId>base</artifactId><version>1</version><packaging>pom</packaging>
+  <build><pluginManagement><plugins>
+    <plugin>
+      <artifactId>maven-surefire-plugin</artifactId><version>3.2.2</version>
+      <configuration><excludes><exclude>**/*IT.java</exclude></excludes><forkCount>2</forkCount></configuration>
+    </plugin>
+  </plugins></pluginManagement></build>
+</project>`,
+	}))
+	app, err := ParseFromReader(strings.NewReader(`<project>
+  <parent><groupId>org.acme</groupId><artifactId>base</artifactId><version>1</version></parent>
+  <artifactId>app</artifactId>
+  <build><plugins>
+    <plugin>
+      <artifactId>maven-surefire-plugin</artifactId>
+      <configuration><includes><include>**/*Test.java</include></includes></configuration>
+    </plugin>
+  </plugins></build>
+</project>`))
+	assert.Nil(t, err)
+
+	effective, err := EffectiveProject(app, repo)
+	assert.Nil(t, err)
+	b, err := xml.Marshal((*effective.Build.Plugins)[0].Configuration)
+	assert.Nil(t, err)
+	assert.Equal(t, "<Properties><excludes><exclude>**/*IT.java</exclude></excludes><forkCount>2</forkCount>"+
+		"<includes><include>**/*Test.java</include></includes></Properties>", string(b))
+}
+
+func Test_EffectiveProjectChildOverridesProperties(t *testing.T) {
+	repo := LocalRepository(writeRepository(t, map[string]string{
+		"org/acme/base/1/base-1.pom": `<project>
+  <groupId>org.acme</groupId><artifactId>base</artifactId><version>1</version><packaging>pom</packaging>
+  <properties><jackson.version>2.9</jackson.version></properties>
+  <dependencyManagement><dependencies>
+    <dependency><groupId>com.fasterxml.jackson.core</groupId><artifactId>jackson-databind</artifactId><version>${jackson.version}</version></dependency>
+    <dependency><groupId>org.acme</groupId><artifactId>sibling</artifactId><version>${project.version}</version></dependency>
+  </dependencies></dependencyManagement>
+</project>`,
+		"org/acme/parent/1/parent-1.pom": `<project>
+  <parent><groupId>org.acme</groupId><artifactId>base</artifactId><version>1</version></parent>
+  <artifactId>parent</artifactId><packaging>pom</packaging>
+  <properties><jackson.version>2.10</jackson.version><surefire.version>3.1.2</surefire.version></properties>
+  <build><pluginManagement><plugins>
+    <plugin><artifactId>maven-surefire-plugin</artifactId><version>${surefire.version}</version></plugin>
+  </plugins></pluginManagement></build>
+</project>`,
+	}))
+	app, err := ParseFromReader(strings.NewReader(`<project>
+  <parent><groupId>org.acme</groupId><artifactId>parent</artifactId><version>1</version></parent>
+  <artifactId>app</artifactId><version>2</version>
+  <properties><jackson.version>2.15</jackson.version><surefire.version>3.2.2</surefire.version></properties>
+  <dependencies>
+    <dependency><groupId>com.fasterxml.jackson.core</groupId><artifactId>jackson-databind</artifactId></dependency>
+    <dependency><groupId>org.acme</groupId><artifactId>sibling</artifactId></dependency>
+  </dependencies>
+  <build><plugins><plugin><artifactId>maven-surefire-plugin</artifactId></plugin></plugins></build>
+</project>`))
+	assert.Nil(t, err)
+
+	effective, err := EffectiveProject(app, repo)
+	assert.Nil(t, err)
+	var versions []string
+	for _, d := range *effective.Dependencies {
+		versions = append(versions, d.Coordinates().String())
+	}
+	for _, d := range *effective.DependencyManagement.Dependencies {
+		versions = append(versions, d.Coordinates().String())
+	}
+	assert.Equal(t, []string{
+		"com.fasterxml.jackson.core:jackson-databind:2.15",
+		"org.acme:sibling:2",
+		"com.fasterxml.jackson.core:jackson-databind:2.15",
+		"org.acme:sibling:2",
+	}, versions)
+	assert.Equal(t, "3.2.2", *(*effective.Build.Plugins)[0].Version)
+}
+
+func Test_EffectiveProjectMissingParent(t *testing.T) {
+	repo := LocalRepository(t.TempDir())
+	app, err := ParseFromReader(strings.NewReader(resolveApp))
+	assert.Nil(t, err)
+
+	_, err = EffectiveProject(app, repo)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "loading parent org.acme:parent:pom:1")
+}
+
+func Test_LocalRepositoryVersions(t *testing.T) {
+	repo := LocalRepository(writeRepository(t, resolvePoms))
+	versions, err := repo.Versions(ArtifactKey{GroupID: "org.acme", ArtifactID: "lib"})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"2.0", "3.0"}, versions)
+}
@@ -0,0 +1,276 @@
+package gopom
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParseJSON decodes a pom from the JSON encoding written by WriteJSON.
+func ParseJSON(r io.Reader) (*Project, error) {
+	var p Project
+	if err := json.NewDecoder(r).Decode(&p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// ParseYAML decodes a pom from the YAML encoding written by WriteYAML.
+func ParseYAML(r io.Reader) (*Project, error) {
+	var p Project
+	if err := yaml.NewDecoder(r).Decode(&p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// WriteJSON encodes the pom as indented JSON. Keys are the names of the
+// Maven elements, lists are arrays named after their wrapper element,
+// properties are objects and nested configuration is a tree of objects.
+func (p *Project) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(p)
+}
+
+// WriteYAML encodes the pom as YAML with the same structure as WriteJSON.
+func (p *Project) WriteYAML(w io.Writer) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(p); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// MarshalJSON encodes the entries as an object. Entries with nested elements
+// are encoded as trees: child elements become keys, repeated elements arrays
+// and attributes keys prefixed with @. Decoding a tree writes the child
+// elements sorted by name.
+func (p Properties) MarshalJSON() ([]byte, error) {
+	tree, err := p.tree()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(tree)
+}
+
+// UnmarshalJSON decodes the object written by MarshalJSON.
+func (p *Properties) UnmarshalJSON(b []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	var tree map[string]interface{}
+	if err := dec.Decode(&tree); err != nil {
+		return err
+	}
+	return p.fromTree(tree)
+}
+
+// MarshalYAML encodes the entries as a mapping like MarshalJSON.
+func (p Properties) MarshalYAML() (interface{}, error) {
+	return p.tree()
+}
+
+// UnmarshalYAML decodes the mapping written by MarshalYAML. Scalars are
+// kept as written, so 1.10 stays "1.10".
+func (p *Properties) UnmarshalYAML(node *yaml.Node) error {
+	tree, ok := yamlValue(node).(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("line %d: expected a mapping of properties", node.Line)
+	}
+	return p.fromTree(tree)
+}
+
+func yamlValue(node *yaml.Node) interface{} {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) > 0 {
+			return yamlValue(node.Content[0])
+		}
+		return nil
+	case yaml.AliasNode:
+		return yamlValue(node.Alias)
+	case yaml.MappingNode:
+		m := map[string]interface{}{}
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			m[node.Content[i].Value] = yamlValue(node.Content[i+1])
+		}
+		return m
+	case yaml.SequenceNode:
+		list := []interface{}{}
+		for _, item := range node.Content {
+			list = append(list, yamlValue(item))
+		}
+		return list
+	}
+	if node.Tag == "!!null" {
+		return nil
+	}
+	return node.Value
+}
+
+func (p Properties) tree() (map[string]interface{}, error) {
+	tree := map[string]interface{}{}
+	for key, value := range p.Entries {
+		raw, ok := p.RawXML[key]
+		if !ok {
+			tree[key] = value
+			continue
+		}
+		node, err := xmlTree(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", key, err)
+		}
+		tree[key] = node
+	}
+	return tree, nil
+}
+
+func (p *Properties) fromTree(tree map[string]interface{}) error {
+	p.Entries = map[string]string{}
+	p.RawXML = nil
+	for key, value := range tree {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			var buf bytes.Buffer
+			enc := xml.NewEncoder(&buf)
+			if err := encodeTreeContent(enc, v); err != nil {
+				return fmt.Errorf("%s: %v", key, err)
+			}
+			if err := enc.Flush(); err != nil {
+				return err
+			}
+			if p.RawXML == nil {
+				p.RawXML = map[string]string{}
+			}
+			p.Entries[key] = ""
+			p.RawXML[key] = buf.String()
+		case []interface{}:
+			return fmt.Errorf("%s: expected a value or an object, got a list", key)
+		case nil:
+			p.Entries[key] = ""
+		default:
+			p.Entries[key] = fmt.Sprint(v)
+		}
+	}
+	return nil
+}
+
+// xmlTree converts inner XML into nested maps, lists and strings.
+func xmlTree(raw string) (map[string]interface{}, error) {
+	d := xml.NewDecoder(strings.NewReader(raw))
+	root := map[string]interface{}{}
+	stack := []map[string]interface{}{root}
+	var names []string
+	var texts []strings.Builder
+	texts = append(texts, strings.Builder{})
+	for {
+		tok, err := d.RawToken()
+		if err == io.EOF {
+			return root, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			node := map[string]interface{}{}
+			for _, a := range t.Attr {
+				node["@"+a.Name.Local] = a.Value
+			}
+			stack = append(stack, node)
+			names = append(names, t.Name.Local)
+			texts = append(texts, strings.Builder{})
+		case xml.EndElement:
+			node, name, text := stack[len(stack)-1], names[len(names)-1], texts[len(texts)-1].String()
+			stack, names, texts = stack[:len(stack)-1], names[:len(names)-1], texts[:len(texts)-1]
+			var value interface{} = node
+			if len(node) == 0 {
+				value = text
+			} else if strings.TrimSpace(text) != "" {
+				node["#text"] = text
+			}
+			parent := stack[len(stack)-1]
+			switch existing := parent[name].(type) {
+			case nil:
+				parent[name] = value
+			case []interface{}:
+				parent[name] = append(existing, value)
+			default:
+				parent[name] = []interface{}{existing, value}
+			}
+		case xml.CharData:
+			texts[len(texts)-1].Write(t)
+		}
+	}
+}
+
+// encodeTreeContent writes the children of a tree node built by xmlTree.
+func encodeTreeContent(enc *xml.Encoder, node map[string]interface{}) error {
+	keys := make([]string, 0, len(node))
+	for k := range node {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if strings.HasPrefix(k, "@") {
+			continue
+		}
+		if k == "#text" {
+			if err := enc.EncodeToken(xml.CharData(fmt.Sprint(node[k]))); err != nil {
+				return err
+			}
+			continue
+		}
+		items, ok := node[k].([]interface{})
+		if !ok {
+			items = []interface{}{node[k]}
+		}
+		for _, item := range items {
+			if err := encodeTreeElement(enc, k, item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func encodeTreeElement(enc *xml.Encoder, name string, value interface{}) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	node, isNode := value.(map[string]interface{})
+	if isNode {
+		var attrs []string
+		for k := range node {
+			if strings.HasPrefix(k, "@") {
+				attrs = append(attrs, k)
+			}
+		}
+		sort.Strings(attrs)
+		for _, k := range attrs {
+			start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: k[1:]}, Value: fmt.Sprint(node[k])})
+		}
+	}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if err := encodeTreeContent(enc, v); err != nil {
+			return err
+		}
+	case []interface{}:
+		return fmt.Errorf("%s: nested lists are not supported", name)
+	case nil:
+	default:
+		if err := enc.EncodeToken(xml.CharData(fmt.Sprint(v))); err != nil {
+			return err
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
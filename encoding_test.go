@@ -0,0 +1,147 @@
+package gopom
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const configurationPom = `<project xmlns="http://maven.apache.org/POM/4.0.0">
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>com.example</groupId>
+  <artifactId>app</artifactId>
+  <version>1.10</version>
+  <properties>
+    <java.version>11</java.version>
+  </properties>
+  <modules>
+    <module>core</module>
+  </modules>
+  <build>
+    <finalName>app</finalName>
+    <plugins>
+      <plugin>
+        <artifactId>maven-compiler-plugin</artifactId>
+        <configuration>
+          <release>11</release>
+          <compilerArgs>
+            <arg>-Xlint</arg>
+            <arg>-parameters</arg>
+          </compilerArgs>
+          <annotationProcessorPaths>
+            <path combine.children="append">
+              <groupId>org.projectlombok</groupId>
+              <artifactId>lombok</artifactId>
+            </path>
+          </annotationProcessorPaths>
+        </configuration>
+      </plugin>
+    </plugins>
+  </build>
+</project>`
+
+func Test_WriteJSON(t *testing.T) {
+	project, err := ParseFromReader(strings.NewReader(configurationPom))
+	assert.Nil(t, err)
+
+	var buf bytes.Buffer
+	assert.Nil(t, project.WriteJSON(&buf))
+
+	var doc map[string]interface{}
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "1.10", doc["version"])
+	assert.Equal(t, []interface{}{"core"}, doc["modules"])
+	assert.Equal(t, map[string]interface{}{"java.version": "11"}, doc["properties"])
+
+	build := doc["build"].(map[string]interface{})
+	assert.Equal(t, "app", build["finalName"])
+	plugin := build["plugins"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{
+		"release":      "11",
+		"compilerArgs": map[string]interface{}{"arg": []interface{}{"-Xlint", "-parameters"}},
+		"annotationProcessorPaths": map[string]interface{}{"path": map[string]interface{}{
+			"@combine.children": "append",
+			"groupId":           "org.projectlombok",
+			"artifactId":        "lombok",
+		}},
+	}, plugin["configuration"])
+}
+
+func Test_JSONRoundTrip(t *testing.T) {
+	for _, pom := range []string{configurationPom, examplePom} {
+		project, err := ParseFromReader(strings.NewReader(pom))
+		assert.Nil(t, err)
+
+		var first bytes.Buffer
+		assert.Nil(t, project.WriteJSON(&first))
+		decoded, err := ParseJSON(bytes.NewReader(first.Bytes()))
+		assert.Nil(t, err)
+		assert.Equal(t, project.Dependencies, decoded.Dependencies)
+		assert.Equal(t, project.Properties, decoded.Properties)
+
+		var second bytes.Buffer
+		assert.Nil(t, decoded.WriteJSON(&second))
+		assert.Equal(t, first.String(), second.String())
+	}
+}
+
+func Test_YAMLRoundTrip(t *testing.T) {
+	for _, pom := range []string{configurationPom, examplePom} {
+		project, err := ParseFromReader(strings.NewReader(pom))
+		assert.Nil(t, err)
+
+		var first bytes.Buffer
+		assert.Nil(t, project.WriteYAML(&first))
+		decoded, err := ParseYAML(bytes.NewReader(first.Bytes()))
+		assert.Nil(t, err)
+		assert.Equal(t, project.Version, decoded.Version)
+		assert.Equal(t, project.Dependencies, decoded.Dependencies)
+		assert.Equal(t, project.Properties, decoded.Properties)
+
+		var second bytes.Buffer
+		assert.Nil(t, decoded.WriteYAML(&second))
+		assert.Equal(t, first.String(), second.String())
+	}
+}
+
+func Test_WriteYAML(t *testing.T) {
+	project, err := ParseFromReader(strings.NewReader(configurationPom))
+	assert.Nil(t, err)
+
+	var buf bytes.Buffer
+	assert.Nil(t, project.WriteYAML(&buf))
+	yml := buf.String()
+	assert.Contains(t, yml, `version: "1.10"`)
+	assert.Contains(t, yml, `
+  plugins:
+    - artifactId: maven-compiler-plugin
+      configuration:
+        annotationProcessorPaths:
+          path:
+            '@combine.children': append
+            artifactId: lombok
+            groupId: org.projectlombok
+        compilerArgs:
+          arg:
+            - -Xlint
+            - -parameters
+        release: "11"
+`)
+}
+
+func Test_ParseJSONConfigurationToXML(t *testing.T) {
+	project, err := ParseJSON(strings.NewReader(`{
+  "artifactId": "app",
+  "build": {"plugins": [{"artifactId": "maven-surefire-plugin", "configuration": {"forkCount": 2, "includes": {"include": ["**/*Test.java", "**/*IT.java"]}}}]}
+}`))
+	assert.Nil(t, err)
+	configuration := (*project.Build.Plugins)[0].Configuration
+	assert.Equal(t, "2", configuration.Entries["forkCount"])
+	assert.Equal(t, "<include>**/*Test.java</include><include>**/*IT.java</include>", configuration.RawXML["includes"])
+
+	_, err = ParseJSON(strings.NewReader(`{"properties": {"list": ["a"]}}`))
+	assert.EqualError(t, err, "list: expected a value or an object, got a list")
+}
@@ -0,0 +1,197 @@
+package gopom
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Evaluate returns the values an expression selects from the project, with
+// properties interpolated. The expression is either a property name, optionally
+// written as ${name}, or a path through the elements of the pom starting with
+// project, such as project.build.plugins[artifactId=maven-compiler-plugin].version.
+// Lists are selected by the name of the list element's parent, e.g.
+// project.dependencies, and can be narrowed with [name=value] filters and a
+// 1-based [n] index. A path through a list yields a value per item. Objects
+// and lists are returned as JSON. Evaluate returns no values when nothing matches.
+func (p *Project) Evaluate(expr string) ([]string, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "${") && strings.HasSuffix(expr, "}") {
+		expr = expr[2 : len(expr)-1]
+	}
+	if !strings.ContainsAny(expr, "[]") {
+		if value, ok := p.lookup(expr); ok {
+			return []string{p.Interpolate(value)}, nil
+		}
+	}
+	if expr != "project" && !strings.HasPrefix(expr, "project.") {
+		return nil, nil
+	}
+
+	segments, err := parseExpression(expr)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var tree interface{}
+	if err := dec.Decode(&tree); err != nil {
+		return nil, err
+	}
+
+	nodes := []interface{}{tree}
+	for i := 1; i < len(segments); {
+		var next []interface{}
+		consumed := 1
+		for _, node := range nodes {
+			values, n := selectField(node, segments[i:])
+			next = append(next, values...)
+			if n > consumed {
+				consumed = n
+			}
+		}
+		nodes = next
+		i += consumed
+	}
+
+	var values []string
+	for _, node := range nodes {
+		switch v := node.(type) {
+		case string:
+			values = append(values, p.Interpolate(v))
+		case json.Number, bool:
+			values = append(values, fmt.Sprint(v))
+		default:
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, string(encoded))
+		}
+	}
+	return values, nil
+}
+
+// expressionSegment is a field of an expression with its filters and index.
+type expressionSegment struct {
+	name    string
+	filters [][2]string
+	index   int
+}
+
+func parseExpression(expr string) ([]expressionSegment, error) {
+	invalid := func(reason string) ([]expressionSegment, error) {
+		return nil, fmt.Errorf("invalid expression %q: %s", expr, reason)
+	}
+	var segments []expressionSegment
+	var current expressionSegment
+	for i := 0; i < len(expr); i++ {
+		switch c := expr[i]; c {
+		case '.':
+			if current.name == "" {
+				return invalid("empty field name")
+			}
+			segments = append(segments, current)
+			current = expressionSegment{}
+		case '[':
+			end := strings.IndexByte(expr[i:], ']')
+			if end < 0 || current.name == "" {
+				return invalid("misplaced [")
+			}
+			selector := strings.TrimSpace(expr[i+1 : i+end])
+			i += end
+			if eq := strings.IndexByte(selector, '='); eq > 0 {
+				current.filters = append(current.filters, [2]string{strings.TrimSpace(selector[:eq]), strings.TrimSpace(selector[eq+1:])})
+			} else if n, err := strconv.Atoi(selector); err == nil && n > 0 && current.index == 0 {
+				current.index = n
+			} else {
+				return invalid(fmt.Sprintf("invalid selector [%s]", selector))
+			}
+			if i+1 < len(expr) && expr[i+1] != '.' && expr[i+1] != '[' {
+				return invalid("expected . after ]")
+			}
+		default:
+			current.name += string(c)
+		}
+	}
+	if current.name == "" {
+		return invalid("empty field name")
+	}
+	return append(segments, current), nil
+}
+
+// selectField applies the first segment to node and returns the selected
+// values and the number of segments consumed. Keys that contain dots, such as
+// property names, consume several segments.
+func selectField(node interface{}, segments []expressionSegment) ([]interface{}, int) {
+	if list, ok := node.([]interface{}); ok {
+		var values []interface{}
+		consumed := 1
+		for _, item := range list {
+			v, n := selectField(item, segments)
+			values = append(values, v...)
+			if n > consumed {
+				consumed = n
+			}
+		}
+		return values, consumed
+	}
+	object, ok := node.(map[string]interface{})
+	if !ok {
+		return nil, 1
+	}
+
+	for n := len(segments); n >= 1; n-- {
+		names := make([]string, n)
+		plain := true
+		for i, s := range segments[:n] {
+			names[i] = s.name
+			plain = plain && (i == n-1 || len(s.filters) == 0 && s.index == 0)
+		}
+		if !plain {
+			continue
+		}
+		value, ok := object[strings.Join(names, ".")]
+		if !ok {
+			continue
+		}
+		return applySelectors(value, segments[n-1]), n
+	}
+	return nil, 1
+}
+
+func applySelectors(value interface{}, s expressionSegment) []interface{} {
+	if len(s.filters) == 0 && s.index == 0 {
+		return []interface{}{value}
+	}
+	list, ok := value.([]interface{})
+	if !ok {
+		list = []interface{}{value}
+	}
+	var selected []interface{}
+	for _, item := range list {
+		object, _ := item.(map[string]interface{})
+		matches := true
+		for _, f := range s.filters {
+			if value, ok := object[f[0]]; !ok || fmt.Sprint(value) != f[1] {
+				matches = false
+			}
+		}
+		if matches {
+			selected = append(selected, item)
+		}
+	}
+	if s.index > 0 {
+		if s.index > len(selected) {
+			return nil
+		}
+		return selected[s.index-1 : s.index]
+	}
+	return selected
+}
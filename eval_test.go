@@ -0,0 +1,69 @@
+package gopom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const evalPom = `<project>
+  <parent><groupId>org.acme</groupId><artifactId>parent</artifactId><version>1.2</version></parent>
+  <artifactId>app</artifactId>
+  <properties><junit.version>4.13.2</junit.version><skip>true</skip></properties>
+  <modules><module>core</module><module>web</module></modules>
+  <dependencies>
+    <dependency><groupId>org.acme</groupId><artifactId>core</artifactId><version>${project.version}</version></dependency>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><version>${junit.version}</version><scope>test</scope></dependency>
+  </dependencies>
+  <build><plugins>
+    <plugin><artifactId>maven-compiler-plugin</artifactId><version>3.11.0</version><configuration><release>17</release></configuration></plugin>
+  </plugins></build>
+</project>`
+
+func Test_Evaluate(t *testing.T) {
+	p, err := ParseFromReader(strings.NewReader(evalPom))
+	assert.Nil(t, err)
+
+	tests := map[string][]string{
+		"project.version":                                {"1.2"},
+		"project.groupId":                                {"org.acme"},
+		"project.artifactId":                             {"app"},
+		"junit.version":                                  {"4.13.2"},
+		"${junit.version}":                               {"4.13.2"},
+		"project.properties.junit.version":               {"4.13.2"},
+		"project.dependencies[artifactId=junit].version": {"4.13.2"},
+		"project.dependencies[groupId=org.acme].version": {"1.2"},
+		"project.dependencies.artifactId":                {"core", "junit"},
+		"project.dependencies[2].scope":                  {"test"},
+		"project.dependencies[scope=test][1].artifactId": {"junit"},
+		"project.modules":                                {`["core","web"]`},
+		"project.modules[2]":                             {"web"},
+		"project.build.plugins[artifactId=maven-compiler-plugin].configuration.release": {"17"},
+		"project.parent": {`{"artifactId":"parent","groupId":"org.acme","version":"1.2"}`},
+		"project.dependencies[artifactId=missing].version": nil,
+		"project.description":                              nil,
+		"missing.property":                                 nil,
+		"project.dependencies[3]":                          nil,
+	}
+	for expr, expected := range tests {
+		values, err := p.Evaluate(expr)
+		assert.Nil(t, err, expr)
+		assert.Equal(t, expected, values, expr)
+	}
+}
+
+func Test_EvaluateInvalid(t *testing.T) {
+	p, err := ParseFromReader(strings.NewReader(evalPom))
+	assert.Nil(t, err)
+	for expr, message := range map[string]string{
+		"project..version":         "empty field name",
+		"project.dependencies[0]":  "invalid selector [0]",
+		"project.dependencies[x":   "misplaced [",
+		"project.dependencies[1]x": "expected . after ]",
+		"project.dependencies.":    "empty field name",
+	} {
+		_, err := p.Evaluate(expr)
+		assert.EqualError(t, err, "invalid expression \""+expr+"\": "+message)
+	}
+}
@@ -1,94 +1,172 @@
 package gopom
 
 import (
+	"bytes"
 	"encoding/xml"
 	"io"
-	"io/ioutil"
 	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
 )
 
-func Parse(path string) (*Project, error) {
+// Parse parses the pom file at path. Files ending in .yml or .yaml are read
+// with ParsePolyglotYAML, which ignores the options.
+func Parse(path string, opts ...ParseOption) (*Project, error) {
 	file, err := os.Open(path)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
-	b, _ := ioutil.ReadAll(file)
-	var project Project
-
-	err = xml.Unmarshal(b, &project)
-	if err != nil {
-		return nil, err
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yml" || ext == ".yaml" {
+		return ParsePolyglotYAML(file)
 	}
-	return &project, nil
+	return parse(file, append([]ParseOption{WithSource(path)}, opts...))
 }
 
-func ParseFromReader(reader io.Reader) (*Project, error) {
-	b, _ := ioutil.ReadAll(reader)
-	var project Project
-
-	err := xml.Unmarshal(b, &project)
-	if err != nil {
-		return nil, err
-	}
-	return &project, nil
+// ParseFromReader parses a pom from reader. The input is decoded as it is
+// read, so large poms never have to be held in memory as a whole.
+func ParseFromReader(reader io.Reader, opts ...ParseOption) (*Project, error) {
+	return parse(reader, opts)
 }
 
 type Project struct {
-	XMLName                *xml.Name               `xml:"project,omitempty"`
-	ModelVersion           *string                 `xml:"modelVersion,omitempty"`
-	Parent                 *Parent                 `xml:"parent,omitempty"`
-	GroupID                *string                 `xml:"groupId,omitempty"`
-	ArtifactID             *string                 `xml:"artifactId,omitempty"`
-	Version                *string                 `xml:"version,omitempty"`
-	Packaging              *string                 `xml:"packaging,omitempty"`
-	Name                   *string                 `xml:"name,omitempty"`
-	Description            *string                 `xml:"description,omitempty"`
-	URL                    *string                 `xml:"url,omitempty"`
-	InceptionYear          *string                 `xml:"inceptionYear,omitempty"`
-	Organization           *Organization           `xml:"organization,omitempty"`
-	Licenses               *[]License              `xml:"licenses>license,omitempty"`
-	Developers             *[]Developer            `xml:"developers>developer,omitempty"`
-	Contributors           *[]Contributor          `xml:"contributors>contributor,omitempty"`
-	MailingLists           *[]MailingList          `xml:"mailingLists>mailingList,omitempty"`
-	Prerequisites          *Prerequisites          `xml:"prerequisites,omitempty"`
-	Modules                *[]string               `xml:"modules>module,omitempty"`
-	SCM                    *Scm                    `xml:"scm,omitempty"`
-	IssueManagement        *IssueManagement        `xml:"issueManagement,omitempty"`
-	CIManagement           *CIManagement           `xml:"ciManagement,omitempty"`
-	DistributionManagement *DistributionManagement `xml:"distributionManagement,omitempty"`
-	DependencyManagement   *DependencyManagement   `xml:"dependencyManagement,omitempty"`
-	Dependencies           *[]Dependency           `xml:"dependencies>dependency,omitempty"`
-	Repositories           *[]Repository           `xml:"repositories>repository,omitempty"`
-	PluginRepositories     *[]PluginRepository     `xml:"pluginRepositories>pluginRepository,omitempty"`
-	Build                  *Build                  `xml:"build,omitempty"`
-	Reporting              *Reporting              `xml:"reporting,omitempty"`
-	Profiles               *[]Profile              `xml:"profiles>profile,omitempty"`
-	Properties             *Properties             `xml:"properties,omitempty"`
+	XMLName                   *xml.Name               `xml:"project,omitempty" json:"-" yaml:"-"`
+	Xmlns                     *string                 `xml:"xmlns,attr,omitempty" json:"xmlns,omitempty" yaml:"xmlns,omitempty"`
+	Root                      *string                 `xml:"root,attr,omitempty" json:"root,omitempty" yaml:"root,omitempty"`
+	ChildURLInheritAppendPath *string                 `xml:"child.project.url.inherit.append.path,attr,omitempty" json:"child.project.url.inherit.append.path,omitempty" yaml:"child.project.url.inherit.append.path,omitempty"`
+	ModelVersion              *string                 `xml:"modelVersion,omitempty" json:"modelVersion,omitempty" yaml:"modelVersion,omitempty"`
+	Parent                    *Parent                 `xml:"parent,omitempty" json:"parent,omitempty" yaml:"parent,omitempty"`
+	GroupID                   *string                 `xml:"groupId,omitempty" json:"groupId,omitempty" yaml:"groupId,omitempty"`
+	ArtifactID                *string                 `xml:"artifactId,omitempty" json:"artifactId,omitempty" yaml:"artifactId,omitempty"`
+	Version                   *string                 `xml:"version,omitempty" json:"version,omitempty" yaml:"version,omitempty"`
+	Packaging                 *string                 `xml:"packaging,omitempty" json:"packaging,omitempty" yaml:"packaging,omitempty"`
+	Name                      *string                 `xml:"name,omitempty" json:"name,omitempty" yaml:"name,omitempty"`
+	Description               *string                 `xml:"description,omitempty" json:"description,omitempty" yaml:"description,omitempty"`
+	URL                       *string                 `xml:"url,omitempty" json:"url,omitempty" yaml:"url,omitempty"`
+	InceptionYear             *string                 `xml:"inceptionYear,omitempty" json:"inceptionYear,omitempty" yaml:"inceptionYear,omitempty"`
+	Organization              *Organization           `xml:"organization,omitempty" json:"organization,omitempty" yaml:"organization,omitempty"`
+	Licenses                  *[]License              `xml:"licenses>license,omitempty" json:"licenses,omitempty" yaml:"licenses,omitempty"`
+	Developers                *[]Developer            `xml:"developers>developer,omitempty" json:"developers,omitempty" yaml:"developers,omitempty"`
+	Contributors              *[]Contributor          `xml:"contributors>contributor,omitempty" json:"contributors,omitempty" yaml:"contributors,omitempty"`
+	MailingLists              *[]MailingList          `xml:"mailingLists>mailingList,omitempty" json:"mailingLists,omitempty" yaml:"mailingLists,omitempty"`
+	Prerequisites             *Prerequisites          `xml:"prerequisites,omitempty" json:"prerequisites,omitempty" yaml:"prerequisites,omitempty"`
+	Modules                   *[]string               `xml:"modules>module,omitempty" json:"modules,omitempty" yaml:"modules,omitempty"`
+	Subprojects               *[]string               `xml:"subprojects>subproject,omitempty" json:"subprojects,omitempty" yaml:"subprojects,omitempty"`
+	SCM                       *Scm                    `xml:"scm,omitempty" json:"scm,omitempty" yaml:"scm,omitempty"`
+	IssueManagement           *IssueManagement        `xml:"issueManagement,omitempty" json:"issueManagement,omitempty" yaml:"issueManagement,omitempty"`
+	CIManagement              *CIManagement           `xml:"ciManagement,omitempty" json:"ciManagement,omitempty" yaml:"ciManagement,omitempty"`
+	DistributionManagement    *DistributionManagement `xml:"distributionManagement,omitempty" json:"distributionManagement,omitempty" yaml:"distributionManagement,omitempty"`
+	DependencyManagement      *DependencyManagement   `xml:"dependencyManagement,omitempty" json:"dependencyManagement,omitempty" yaml:"dependencyManagement,omitempty"`
+	Dependencies              *[]Dependency           `xml:"dependencies>dependency,omitempty" json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+	Repositories              *[]Repository           `xml:"repositories>repository,omitempty" json:"repositories,omitempty" yaml:"repositories,omitempty"`
+	PluginRepositories        *[]PluginRepository     `xml:"pluginRepositories>pluginRepository,omitempty" json:"pluginRepositories,omitempty" yaml:"pluginRepositories,omitempty"`
+	Build                     *Build                  `xml:"build,omitempty" json:"build,omitempty" yaml:"build,omitempty"`
+	Reports                   *Properties             `xml:"reports,omitempty" json:"reports,omitempty" yaml:"reports,omitempty"`
+	Reporting                 *Reporting              `xml:"reporting,omitempty" json:"reporting,omitempty" yaml:"reporting,omitempty"`
+	Profiles                  *[]Profile              `xml:"profiles>profile,omitempty" json:"profiles,omitempty" yaml:"profiles,omitempty"`
+	Properties                *Properties             `xml:"properties,omitempty" json:"properties,omitempty" yaml:"properties,omitempty"`
+
+	// Locations holds the input location of every element when parsed WithLocations.
+	Locations Locations `xml:"-" json:"-" yaml:"-"`
 }
 
 type Properties struct {
 	Entries map[string]string
+	// RawXML holds the inner XML of the entries that contain elements, such
+	// as lists in plugin configuration. It is only used for keys that are in Entries.
+	RawXML map[string]string
 }
 
-func (p *Properties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) (err error) {
-	type entry struct {
-		XMLName xml.Name
-		Key     string `xml:"name,attr"`
-		Value   string `xml:",chardata"`
-	}
-	e := entry{}
+func (p *Properties) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
 	p.Entries = map[string]string{}
-	for err = d.Decode(&e); err == nil; err = d.Decode(&e) {
-		e.Key = e.XMLName.Local
-		p.Entries[e.Key] = e.Value
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			value, raw, err := readPropertyEntry(d)
+			if err != nil {
+				return err
+			}
+			p.Entries[t.Name.Local] = value
+			if raw != "" {
+				if p.RawXML == nil {
+					p.RawXML = map[string]string{}
+				}
+				p.RawXML[t.Name.Local] = raw
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// readPropertyEntry reads the rest of an entry and returns its own text and,
+// when it has child elements, its inner XML without whitespace between elements.
+func readPropertyEntry(d *xml.Decoder) (string, string, error) {
+	var value strings.Builder
+	var inner []xml.Token
+	nested := false
+	for depth := 0; ; {
+		tok, err := d.Token()
+		if err != nil {
+			return "", "", err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			depth++
+			nested = true
+			inner = append(inner, localStart(t))
+		case xml.EndElement:
+			if depth == 0 {
+				if !nested {
+					return value.String(), "", nil
+				}
+				raw, err := encodeTokens(inner)
+				return strings.TrimSpace(value.String()), raw, err
+			}
+			depth--
+			inner = append(inner, xml.EndElement{Name: xml.Name{Local: t.Name.Local}})
+		case xml.CharData:
+			if depth == 0 {
+				value.Write(t)
+			}
+			if strings.TrimSpace(string(t)) != "" {
+				inner = append(inner, t.Copy())
+			}
+		}
 	}
-	if err != nil && err != io.EOF {
-		return err
+}
+
+// localStart strips the namespaces of an element that was read with a namespace aware decoder.
+func localStart(t xml.StartElement) xml.StartElement {
+	start := xml.StartElement{Name: xml.Name{Local: t.Name.Local}}
+	for _, a := range t.Attr {
+		if a.Name.Space == "xmlns" || a.Name.Space == "" && a.Name.Local == "xmlns" {
+			continue
+		}
+		start.Attr = append(start.Attr, xml.Attr{Name: xml.Name{Local: a.Name.Local}, Value: a.Value})
 	}
+	return start
+}
 
-	return nil
+func encodeTokens(tokens []xml.Token) (string, error) {
+	var buf bytes.Buffer
+	enc := xml.NewEncoder(&buf)
+	for _, t := range tokens {
+		if err := enc.EncodeToken(t); err != nil {
+			return "", err
+		}
+	}
+	if err := enc.Flush(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
 }
 
 // MarshalXML marshals Properties into XML.
@@ -96,9 +174,24 @@ func (p Properties) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 
 	tokens := []xml.Token{start}
 
-	for key, value := range p.Entries {
+	keys := make([]string, 0, len(p.Entries))
+	for key := range p.Entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
 		t := xml.StartElement{Name: xml.Name{Local: key}}
-		tokens = append(tokens, t, xml.CharData(value), xml.EndElement{Name: t.Name})
+		tokens = append(tokens, t)
+		if raw, ok := p.RawXML[key]; ok {
+			inner, err := decodeTokens(raw)
+			if err != nil {
+				return err
+			}
+			tokens = append(tokens, inner...)
+		} else {
+			tokens = append(tokens, xml.CharData(p.Entries[key]))
+		}
+		tokens = append(tokens, xml.EndElement{Name: t.Name})
 	}
 
 	tokens = append(tokens, xml.EndElement{Name: start.Name})
@@ -113,272 +206,336 @@ func (p Properties) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 	return e.Flush()
 }
 
+func decodeTokens(raw string) ([]xml.Token, error) {
+	var tokens []xml.Token
+	d := xml.NewDecoder(strings.NewReader(raw))
+	for {
+		tok, err := d.RawToken()
+		if err == io.EOF {
+			return tokens, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, xml.CopyToken(tok))
+	}
+}
+
 type Parent struct {
-	GroupID      *string `xml:"groupId,omitempty"`
-	ArtifactID   *string `xml:"artifactId,omitempty"`
-	Version      *string `xml:"version,omitempty"`
-	RelativePath *string `xml:"relativePath,omitempty"`
+	GroupID      *string `xml:"groupId,omitempty" json:"groupId,omitempty" yaml:"groupId,omitempty"`
+	ArtifactID   *string `xml:"artifactId,omitempty" json:"artifactId,omitempty" yaml:"artifactId,omitempty"`
+	Version      *string `xml:"version,omitempty" json:"version,omitempty" yaml:"version,omitempty"`
+	RelativePath *string `xml:"relativePath,omitempty" json:"relativePath,omitempty" yaml:"relativePath,omitempty"`
 }
 
 type Organization struct {
-	Name *string `xml:"name,omitempty"`
-	URL  *string `xml:"url,omitempty"`
+	Name *string `xml:"name,omitempty" json:"name,omitempty" yaml:"name,omitempty"`
+	URL  *string `xml:"url,omitempty" json:"url,omitempty" yaml:"url,omitempty"`
 }
 
 type License struct {
-	Name         *string `xml:"name,omitempty"`
-	URL          *string `xml:"url,omitempty"`
-	Distribution *string `xml:"distribution,omitempty"`
-	Comments     *string `xml:"comments,omitempty"`
+	Name         *string `xml:"name,omitempty" json:"name,omitempty" yaml:"name,omitempty"`
+	URL          *string `xml:"url,omitempty" json:"url,omitempty" yaml:"url,omitempty"`
+	Distribution *string `xml:"distribution,omitempty" json:"distribution,omitempty" yaml:"distribution,omitempty"`
+	Comments     *string `xml:"comments,omitempty" json:"comments,omitempty" yaml:"comments,omitempty"`
 }
 
 type Developer struct {
-	ID              *string     `xml:"id,omitempty"`
-	Name            *string     `xml:"name,omitempty"`
-	Email           *string     `xml:"email,omitempty"`
-	URL             *string     `xml:"url,omitempty"`
-	Organization    *string     `xml:"organization,omitempty"`
-	OrganizationURL *string     `xml:"organizationUrl,omitempty"`
-	Roles           *[]string   `xml:"roles>role,omitempty"`
-	Timezone        *string     `xml:"timezone,omitempty"`
-	Properties      *Properties `xml:"properties,omitempty"`
+	ID              *string     `xml:"id,omitempty" json:"id,omitempty" yaml:"id,omitempty"`
+	Name            *string     `xml:"name,omitempty" json:"name,omitempty" yaml:"name,omitempty"`
+	Email           *string     `xml:"email,omitempty" json:"email,omitempty" yaml:"email,omitempty"`
+	URL             *string     `xml:"url,omitempty" json:"url,omitempty" yaml:"url,omitempty"`
+	Organization    *string     `xml:"organization,omitempty" json:"organization,omitempty" yaml:"organization,omitempty"`
+	OrganizationURL *string     `xml:"organizationUrl,omitempty" json:"organizationUrl,omitempty" yaml:"organizationUrl,omitempty"`
+	Roles           *[]string   `xml:"roles>role,omitempty" json:"roles,omitempty" yaml:"roles,omitempty"`
+	Timezone        *string     `xml:"timezone,omitempty" json:"timezone,omitempty" yaml:"timezone,omitempty"`
+	Properties      *Properties `xml:"properties,omitempty" json:"properties,omitempty" yaml:"properties,omitempty"`
 }
 
 type Contributor struct {
-	Name            *string     `xml:"name,omitempty"`
-	Email           *string     `xml:"email,omitempty"`
-	URL             *string     `xml:"url,omitempty"`
-	Organization    *string     `xml:"organization,omitempty"`
-	OrganizationURL *string     `xml:"organizationUrl,omitempty"`
-	Roles           *[]string   `xml:"roles>role,omitempty"`
-	Timezone        *string     `xml:"timezone,omitempty"`
-	Properties      *Properties `xml:"properties,omitempty"`
+	Name            *string     `xml:"name,omitempty" json:"name,omitempty" yaml:"name,omitempty"`
+	Email           *string     `xml:"email,omitempty" json:"email,omitempty" yaml:"email,omitempty"`
+	URL             *string     `xml:"url,omitempty" json:"url,omitempty" yaml:"url,omitempty"`
+	Organization    *string     `xml:"organization,omitempty" json:"organization,omitempty" yaml:"organization,omitempty"`
+	OrganizationURL *string     `xml:"organizationUrl,omitempty" json:"organizationUrl,omitempty" yaml:"organizationUrl,omitempty"`
+	Roles           *[]string   `xml:"roles>role,omitempty" json:"roles,omitempty" yaml:"roles,omitempty"`
+	Timezone        *string     `xml:"timezone,omitempty" json:"timezone,omitempty" yaml:"timezone,omitempty"`
+	Properties      *Properties `xml:"properties,omitempty" json:"properties,omitempty" yaml:"properties,omitempty"`
 }
 
 type MailingList struct {
-	Name          *string   `xml:"name,omitempty"`
-	Subscribe     *string   `xml:"subscribe,omitempty"`
-	Unsubscribe   *string   `xml:"unsubscribe,omitempty"`
-	Post          *string   `xml:"post,omitempty"`
-	Archive       *string   `xml:"archive,omitempty"`
-	OtherArchives *[]string `xml:"otherArchives>otherArchive,omitempty"`
+	Name          *string   `xml:"name,omitempty" json:"name,omitempty" yaml:"name,omitempty"`
+	Subscribe     *string   `xml:"subscribe,omitempty" json:"subscribe,omitempty" yaml:"subscribe,omitempty"`
+	Unsubscribe   *string   `xml:"unsubscribe,omitempty" json:"unsubscribe,omitempty" yaml:"unsubscribe,omitempty"`
+	Post          *string   `xml:"post,omitempty" json:"post,omitempty" yaml:"post,omitempty"`
+	Archive       *string   `xml:"archive,omitempty" json:"archive,omitempty" yaml:"archive,omitempty"`
+	OtherArchives *[]string `xml:"otherArchives>otherArchive,omitempty" json:"otherArchives,omitempty" yaml:"otherArchives,omitempty"`
 }
 
 type Prerequisites struct {
-	Maven *string `xml:"maven,omitempty"`
+	Maven *string `xml:"maven,omitempty" json:"maven,omitempty" yaml:"maven,omitempty"`
 }
 
 type Scm struct {
-	Connection          *string `xml:"connection,omitempty"`
-	DeveloperConnection *string `xml:"developerConnection,omitempty"`
-	Tag                 *string `xml:"tag,omitempty"`
-	URL                 *string `xml:"url,omitempty"`
+	ChildConnectionInheritAppendPath          *string `xml:"child.scm.connection.inherit.append.path,attr,omitempty" json:"child.scm.connection.inherit.append.path,omitempty" yaml:"child.scm.connection.inherit.append.path,omitempty"`
+	ChildDeveloperConnectionInheritAppendPath *string `xml:"child.scm.developerConnection.inherit.append.path,attr,omitempty" json:"child.scm.developerConnection.inherit.append.path,omitempty" yaml:"child.scm.developerConnection.inherit.append.path,omitempty"`
+	ChildURLInheritAppendPath                 *string `xml:"child.scm.url.inherit.append.path,attr,omitempty" json:"child.scm.url.inherit.append.path,omitempty" yaml:"child.scm.url.inherit.append.path,omitempty"`
+	Connection                                *string `xml:"connection,omitempty" json:"connection,omitempty" yaml:"connection,omitempty"`
+	DeveloperConnection                       *string `xml:"developerConnection,omitempty" json:"developerConnection,omitempty" yaml:"developerConnection,omitempty"`
+	Tag                                       *string `xml:"tag,omitempty" json:"tag,omitempty" yaml:"tag,omitempty"`
+	URL                                       *string `xml:"url,omitempty" json:"url,omitempty" yaml:"url,omitempty"`
 }
 
 type IssueManagement struct {
-	System *string `xml:"system,omitempty"`
-	URL    *string `xml:"url,omitempty"`
+	System *string `xml:"system,omitempty" json:"system,omitempty" yaml:"system,omitempty"`
+	URL    *string `xml:"url,omitempty" json:"url,omitempty" yaml:"url,omitempty"`
 }
 
 type CIManagement struct {
-	System    *string     `xml:"system,omitempty"`
-	URL       *string     `xml:"url,omitempty"`
-	Notifiers *[]Notifier `xml:"notifiers>notifier,omitempty"`
+	System    *string     `xml:"system,omitempty" json:"system,omitempty" yaml:"system,omitempty"`
+	URL       *string     `xml:"url,omitempty" json:"url,omitempty" yaml:"url,omitempty"`
+	Notifiers *[]Notifier `xml:"notifiers>notifier,omitempty" json:"notifiers,omitempty" yaml:"notifiers,omitempty"`
 }
 
 type Notifier struct {
-	Type          *string     `xml:"type,omitempty"`
-	SendOnError   *bool       `xml:"sendOnError,omitempty"`
-	SendOnFailure *bool       `xml:"sendOnFailure,omitempty"`
-	SendOnSuccess *bool       `xml:"sendOnSuccess,omitempty"`
-	SendOnWarning *bool       `xml:"sendOnWarning,omitempty"`
-	Address       *string     `xml:"address,omitempty"`
-	Configuration *Properties `xml:"configuration,omitempty"`
+	Type          *string     `xml:"type,omitempty" json:"type,omitempty" yaml:"type,omitempty"`
+	SendOnError   *bool       `xml:"sendOnError,omitempty" json:"sendOnError,omitempty" yaml:"sendOnError,omitempty"`
+	SendOnFailure *bool       `xml:"sendOnFailure,omitempty" json:"sendOnFailure,omitempty" yaml:"sendOnFailure,omitempty"`
+	SendOnSuccess *bool       `xml:"sendOnSuccess,omitempty" json:"sendOnSuccess,omitempty" yaml:"sendOnSuccess,omitempty"`
+	SendOnWarning *bool       `xml:"sendOnWarning,omitempty" json:"sendOnWarning,omitempty" yaml:"sendOnWarning,omitempty"`
+	Address       *string     `xml:"address,omitempty" json:"address,omitempty" yaml:"address,omitempty"`
+	Configuration *Properties `xml:"configuration,omitempty" json:"configuration,omitempty" yaml:"configuration,omitempty"`
 }
 
 type DistributionManagement struct {
-	Repository         *Repository `xml:"repository,omitempty"`
-	SnapshotRepository *Repository `xml:"snapshotRepository,omitempty"`
-	Site               *Site       `xml:"site,omitempty"`
-	DownloadURL        *string     `xml:"downloadUrl,omitempty"`
-	Relocation         *Relocation `xml:"relocation,omitempty"`
-	Status             *string     `xml:"status,omitempty"`
+	Repository         *Repository `xml:"repository,omitempty" json:"repository,omitempty" yaml:"repository,omitempty"`
+	SnapshotRepository *Repository `xml:"snapshotRepository,omitempty" json:"snapshotRepository,omitempty" yaml:"snapshotRepository,omitempty"`
+	Site               *Site       `xml:"site,omitempty" json:"site,omitempty" yaml:"site,omitempty"`
+	DownloadURL        *string     `xml:"downloadUrl,omitempty" json:"downloadUrl,omitempty" yaml:"downloadUrl,omitempty"`
+	Relocation         *Relocation `xml:"relocation,omitempty" json:"relocation,omitempty" yaml:"relocation,omitempty"`
+	Status             *string     `xml:"status,omitempty" json:"status,omitempty" yaml:"status,omitempty"`
 }
 
 type Site struct {
-	ID   *string `xml:"id,omitempty"`
-	Name *string `xml:"name,omitempty"`
-	URL  *string `xml:"url,omitempty"`
+	ChildURLInheritAppendPath *string `xml:"child.site.url.inherit.append.path,attr,omitempty" json:"child.site.url.inherit.append.path,omitempty" yaml:"child.site.url.inherit.append.path,omitempty"`
+	ID                        *string `xml:"id,omitempty" json:"id,omitempty" yaml:"id,omitempty"`
+	Name                      *string `xml:"name,omitempty" json:"name,omitempty" yaml:"name,omitempty"`
+	URL                       *string `xml:"url,omitempty" json:"url,omitempty" yaml:"url,omitempty"`
 }
 
 type Relocation struct {
-	GroupID    *string `xml:"groupId,omitempty"`
-	ArtifactID *string `xml:"artifactId,omitempty"`
-	Version    *string `xml:"version,omitempty"`
-	Message    *string `xml:"message,omitempty"`
+	GroupID    *string `xml:"groupId,omitempty" json:"groupId,omitempty" yaml:"groupId,omitempty"`
+	ArtifactID *string `xml:"artifactId,omitempty" json:"artifactId,omitempty" yaml:"artifactId,omitempty"`
+	Version    *string `xml:"version,omitempty" json:"version,omitempty" yaml:"version,omitempty"`
+	Message    *string `xml:"message,omitempty" json:"message,omitempty" yaml:"message,omitempty"`
 }
 
 type DependencyManagement struct {
-	Dependencies *[]Dependency `xml:"dependencies>dependency,omitempty"`
+	Dependencies *[]Dependency `xml:"dependencies>dependency,omitempty" json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
 }
 
 type Dependency struct {
-	GroupID    *string      `xml:"groupId,omitempty"`
-	ArtifactID *string      `xml:"artifactId,omitempty"`
-	Version    *string      `xml:"version,omitempty"`
-	Type       *string      `xml:"type,omitempty"`
-	Classifier *string      `xml:"classifier,omitempty"`
-	Scope      *string      `xml:"scope,omitempty"`
-	SystemPath *string      `xml:"systemPath,omitempty"`
-	Exclusions *[]Exclusion `xml:"exclusions>exclusion,omitempty"`
-	Optional   *string      `xml:"optional,omitempty"`
+	GroupID    *string      `xml:"groupId,omitempty" json:"groupId,omitempty" yaml:"groupId,omitempty"`
+	ArtifactID *string      `xml:"artifactId,omitempty" json:"artifactId,omitempty" yaml:"artifactId,omitempty"`
+	Version    *string      `xml:"version,omitempty" json:"version,omitempty" yaml:"version,omitempty"`
+	Type       *string      `xml:"type,omitempty" json:"type,omitempty" yaml:"type,omitempty"`
+	Classifier *string      `xml:"classifier,omitempty" json:"classifier,omitempty" yaml:"classifier,omitempty"`
+	Scope      *string      `xml:"scope,omitempty" json:"scope,omitempty" yaml:"scope,omitempty"`
+	SystemPath *string      `xml:"systemPath,omitempty" json:"systemPath,omitempty" yaml:"systemPath,omitempty"`
+	Exclusions *[]Exclusion `xml:"exclusions>exclusion,omitempty" json:"exclusions,omitempty" yaml:"exclusions,omitempty"`
+	Optional   *string      `xml:"optional,omitempty" json:"optional,omitempty" yaml:"optional,omitempty"`
 }
 
 type Exclusion struct {
-	ArtifactID *string `xml:"artifactId,omitempty"`
-	GroupID    *string `xml:"groupId,omitempty"`
+	ArtifactID *string `xml:"artifactId,omitempty" json:"artifactId,omitempty" yaml:"artifactId,omitempty"`
+	GroupID    *string `xml:"groupId,omitempty" json:"groupId,omitempty" yaml:"groupId,omitempty"`
 }
 
 type Repository struct {
-	UniqueVersion *bool             `xml:"uniqueVersion,omitempty"`
-	Releases      *RepositoryPolicy `xml:"releases,omitempty"`
-	Snapshots     *RepositoryPolicy `xml:"snapshots,omitempty"`
-	ID            *string           `xml:"id,omitempty"`
-	Name          *string           `xml:"name,omitempty"`
-	URL           *string           `xml:"url,omitempty"`
-	Layout        *string           `xml:"layout,omitempty"`
+	UniqueVersion *bool             `xml:"uniqueVersion,omitempty" json:"uniqueVersion,omitempty" yaml:"uniqueVersion,omitempty"`
+	Releases      *RepositoryPolicy `xml:"releases,omitempty" json:"releases,omitempty" yaml:"releases,omitempty"`
+	Snapshots     *RepositoryPolicy `xml:"snapshots,omitempty" json:"snapshots,omitempty" yaml:"snapshots,omitempty"`
+	ID            *string           `xml:"id,omitempty" json:"id,omitempty" yaml:"id,omitempty"`
+	Name          *string           `xml:"name,omitempty" json:"name,omitempty" yaml:"name,omitempty"`
+	URL           *string           `xml:"url,omitempty" json:"url,omitempty" yaml:"url,omitempty"`
+	Layout        *string           `xml:"layout,omitempty" json:"layout,omitempty" yaml:"layout,omitempty"`
 }
 
 type RepositoryPolicy struct {
-	Enabled        *string `xml:"enabled,omitempty"`
-	UpdatePolicy   *string `xml:"updatePolicy,omitempty"`
-	ChecksumPolicy *string `xml:"checksumPolicy,omitempty"`
+	Enabled        *string `xml:"enabled,omitempty" json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	UpdatePolicy   *string `xml:"updatePolicy,omitempty" json:"updatePolicy,omitempty" yaml:"updatePolicy,omitempty"`
+	ChecksumPolicy *string `xml:"checksumPolicy,omitempty" json:"checksumPolicy,omitempty" yaml:"checksumPolicy,omitempty"`
 }
 
 type PluginRepository struct {
-	Releases  *RepositoryPolicy `xml:"releases,omitempty"`
-	Snapshots *RepositoryPolicy `xml:"snapshots,omitempty"`
-	ID        *string           `xml:"id,omitempty"`
-	Name      *string           `xml:"name,omitempty"`
-	URL       *string           `xml:"url,omitempty"`
-	Layout    *string           `xml:"layout,omitempty"`
+	Releases  *RepositoryPolicy `xml:"releases,omitempty" json:"releases,omitempty" yaml:"releases,omitempty"`
+	Snapshots *RepositoryPolicy `xml:"snapshots,omitempty" json:"snapshots,omitempty" yaml:"snapshots,omitempty"`
+	ID        *string           `xml:"id,omitempty" json:"id,omitempty" yaml:"id,omitempty"`
+	Name      *string           `xml:"name,omitempty" json:"name,omitempty" yaml:"name,omitempty"`
+	URL       *string           `xml:"url,omitempty" json:"url,omitempty" yaml:"url,omitempty"`
+	Layout    *string           `xml:"layout,omitempty" json:"layout,omitempty" yaml:"layout,omitempty"`
 }
 
 type BuildBase struct {
-	DefaultGoal      *string           `xml:"defaultGoal,omitempty"`
-	Resources        *[]Resource       `xml:"resources>resource,omitempty"`
-	TestResources    *[]Resource       `xml:"testResources>testResource,omitempty"`
-	Directory        *string           `xml:"directory,omitempty"`
-	FinalName        *string           `xml:"finalName,omitempty"`
-	Filters          *[]string         `xml:"filters>filter,omitempty"`
-	PluginManagement *PluginManagement `xml:"pluginManagement,omitempty"`
-	Plugins          *[]Plugin         `xml:"plugins>plugin,omitempty"`
+	DefaultGoal      *string           `xml:"defaultGoal,omitempty" json:"defaultGoal,omitempty" yaml:"defaultGoal,omitempty"`
+	Resources        *[]Resource       `xml:"resources>resource,omitempty" json:"resources,omitempty" yaml:"resources,omitempty"`
+	TestResources    *[]Resource       `xml:"testResources>testResource,omitempty" json:"testResources,omitempty" yaml:"testResources,omitempty"`
+	Directory        *string           `xml:"directory,omitempty" json:"directory,omitempty" yaml:"directory,omitempty"`
+	FinalName        *string           `xml:"finalName,omitempty" json:"finalName,omitempty" yaml:"finalName,omitempty"`
+	Filters          *[]string         `xml:"filters>filter,omitempty" json:"filters,omitempty" yaml:"filters,omitempty"`
+	PluginManagement *PluginManagement `xml:"pluginManagement,omitempty" json:"pluginManagement,omitempty" yaml:"pluginManagement,omitempty"`
+	Plugins          *[]Plugin         `xml:"plugins>plugin,omitempty" json:"plugins,omitempty" yaml:"plugins,omitempty"`
 }
 
 type Build struct {
-	SourceDirectory       *string      `xml:"sourceDirectory,omitempty"`
-	ScriptSourceDirectory *string      `xml:"scriptSourceDirectory,omitempty"`
-	TestSourceDirectory   *string      `xml:"testSourceDirectory,omitempty"`
-	OutputDirectory       *string      `xml:"outputDirectory,omitempty"`
-	TestOutputDirectory   *string      `xml:"testOutputDirectory,omitempty"`
-	Extensions            *[]Extension `xml:"extensions>extension,omitempty"`
-	BuildBase
+	SourceDirectory       *string      `xml:"sourceDirectory,omitempty" json:"sourceDirectory,omitempty" yaml:"sourceDirectory,omitempty"`
+	ScriptSourceDirectory *string      `xml:"scriptSourceDirectory,omitempty" json:"scriptSourceDirectory,omitempty" yaml:"scriptSourceDirectory,omitempty"`
+	TestSourceDirectory   *string      `xml:"testSourceDirectory,omitempty" json:"testSourceDirectory,omitempty" yaml:"testSourceDirectory,omitempty"`
+	OutputDirectory       *string      `xml:"outputDirectory,omitempty" json:"outputDirectory,omitempty" yaml:"outputDirectory,omitempty"`
+	TestOutputDirectory   *string      `xml:"testOutputDirectory,omitempty" json:"testOutputDirectory,omitempty" yaml:"testOutputDirectory,omitempty"`
+	Extensions            *[]Extension `xml:"extensions>extension,omitempty" json:"extensions,omitempty" yaml:"extensions,omitempty"`
+	Sources               *[]Source    `xml:"sources>source,omitempty" json:"sources,omitempty" yaml:"sources,omitempty"`
+	BuildBase             `yaml:",inline"`
+}
+
+type Source struct {
+	Scope           *string   `xml:"scope,omitempty" json:"scope,omitempty" yaml:"scope,omitempty"`
+	Lang            *string   `xml:"lang,omitempty" json:"lang,omitempty" yaml:"lang,omitempty"`
+	Module          *string   `xml:"module,omitempty" json:"module,omitempty" yaml:"module,omitempty"`
+	TargetVersion   *string   `xml:"targetVersion,omitempty" json:"targetVersion,omitempty" yaml:"targetVersion,omitempty"`
+	Directory       *string   `xml:"directory,omitempty" json:"directory,omitempty" yaml:"directory,omitempty"`
+	Includes        *[]string `xml:"includes>include,omitempty" json:"includes,omitempty" yaml:"includes,omitempty"`
+	Excludes        *[]string `xml:"excludes>exclude,omitempty" json:"excludes,omitempty" yaml:"excludes,omitempty"`
+	StringFiltering *string   `xml:"stringFiltering,omitempty" json:"stringFiltering,omitempty" yaml:"stringFiltering,omitempty"`
+	TargetPath      *string   `xml:"targetPath,omitempty" json:"targetPath,omitempty" yaml:"targetPath,omitempty"`
+	Enabled         *string   `xml:"enabled,omitempty" json:"enabled,omitempty" yaml:"enabled,omitempty"`
 }
 
 type Extension struct {
-	GroupID    *string `xml:"groupId,omitempty"`
-	ArtifactID *string `xml:"artifactId,omitempty"`
-	Version    *string `xml:"version,omitempty"`
+	GroupID    *string `xml:"groupId,omitempty" json:"groupId,omitempty" yaml:"groupId,omitempty"`
+	ArtifactID *string `xml:"artifactId,omitempty" json:"artifactId,omitempty" yaml:"artifactId,omitempty"`
+	Version    *string `xml:"version,omitempty" json:"version,omitempty" yaml:"version,omitempty"`
 }
 
 type Resource struct {
-	TargetPath *string   `xml:"targetPath,omitempty"`
-	Filtering  *string   `xml:"filtering,omitempty"`
-	Directory  *string   `xml:"directory,omitempty"`
-	Includes   *[]string `xml:"includes>include,omitempty"`
-	Excludes   *[]string `xml:"excludes>exclude,omitempty"`
+	TargetPath *string   `xml:"targetPath,omitempty" json:"targetPath,omitempty" yaml:"targetPath,omitempty"`
+	Filtering  *string   `xml:"filtering,omitempty" json:"filtering,omitempty" yaml:"filtering,omitempty"`
+	Directory  *string   `xml:"directory,omitempty" json:"directory,omitempty" yaml:"directory,omitempty"`
+	Includes   *[]string `xml:"includes>include,omitempty" json:"includes,omitempty" yaml:"includes,omitempty"`
+	Excludes   *[]string `xml:"excludes>exclude,omitempty" json:"excludes,omitempty" yaml:"excludes,omitempty"`
 }
 
 type PluginManagement struct {
-	Plugins *[]Plugin `xml:"plugins>plugin,omitempty"`
+	Plugins *[]Plugin `xml:"plugins>plugin,omitempty" json:"plugins,omitempty" yaml:"plugins,omitempty"`
 }
 
 type Plugin struct {
-	GroupID       *string            `xml:"groupId,omitempty"`
-	ArtifactID    *string            `xml:"artifactId,omitempty"`
-	Version       *string            `xml:"version,omitempty"`
-	Extensions    *string            `xml:"extensions,omitempty"`
-	Executions    *[]PluginExecution `xml:"executions>execution,omitempty"`
-	Dependencies  *[]Dependency      `xml:"dependencies>dependency,omitempty"`
-	Inherited     *string            `xml:"inherited,omitempty"`
-	Configuration *Properties        `xml:"configuration,omitempty"`
+	GroupID       *string            `xml:"groupId,omitempty" json:"groupId,omitempty" yaml:"groupId,omitempty"`
+	ArtifactID    *string            `xml:"artifactId,omitempty" json:"artifactId,omitempty" yaml:"artifactId,omitempty"`
+	Version       *string            `xml:"version,omitempty" json:"version,omitempty" yaml:"version,omitempty"`
+	Extensions    *string            `xml:"extensions,omitempty" json:"extensions,omitempty" yaml:"extensions,omitempty"`
+	Executions    *[]PluginExecution `xml:"executions>execution,omitempty" json:"executions,omitempty" yaml:"executions,omitempty"`
+	Dependencies  *[]Dependency      `xml:"dependencies>dependency,omitempty" json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+	Goals         *[]string          `xml:"goals>goal,omitempty" json:"goals,omitempty" yaml:"goals,omitempty"`
+	Inherited     *string            `xml:"inherited,omitempty" json:"inherited,omitempty" yaml:"inherited,omitempty"`
+	Configuration *Properties        `xml:"configuration,omitempty" json:"configuration,omitempty" yaml:"configuration,omitempty"`
 }
 
 type PluginExecution struct {
-	ID            *string     `xml:"id,omitempty"`
-	Phase         *string     `xml:"phase,omitempty"`
-	Goals         *[]string   `xml:"goals>goal,omitempty"`
-	Inherited     *string     `xml:"inherited,omitempty"`
-	Configuration *Properties `xml:"configuration,omitempty"`
+	ID            *string     `xml:"id,omitempty" json:"id,omitempty" yaml:"id,omitempty"`
+	Phase         *string     `xml:"phase,omitempty" json:"phase,omitempty" yaml:"phase,omitempty"`
+	Priority      *string     `xml:"priority,omitempty" json:"priority,omitempty" yaml:"priority,omitempty"`
+	Goals         *[]string   `xml:"goals>goal,omitempty" json:"goals,omitempty" yaml:"goals,omitempty"`
+	Inherited     *string     `xml:"inherited,omitempty" json:"inherited,omitempty" yaml:"inherited,omitempty"`
+	Configuration *Properties `xml:"configuration,omitempty" json:"configuration,omitempty" yaml:"configuration,omitempty"`
+}
+
+// ExecutionsByPriority returns the executions of the plugin in the order Maven
+// runs executions bound to the same phase: by ascending priority, keeping the
+// declaration order for equal priorities. A missing priority counts as 0.
+func (p *Plugin) ExecutionsByPriority() []PluginExecution {
+	if p.Executions == nil {
+		return nil
+	}
+	executions := append([]PluginExecution(nil), *p.Executions...)
+	sort.SliceStable(executions, func(i, j int) bool {
+		return executions[i].priority() < executions[j].priority()
+	})
+	return executions
+}
+
+func (e PluginExecution) priority() int {
+	if e.Priority == nil {
+		return 0
+	}
+	priority, err := strconv.Atoi(strings.TrimSpace(*e.Priority))
+	if err != nil {
+		return 0
+	}
+	return priority
 }
 
 type Reporting struct {
-	ExcludeDefaults *string            `xml:"excludeDefaults,omitempty"`
-	OutputDirectory *string            `xml:"outputDirectory,omitempty"`
-	Plugins         *[]ReportingPlugin `xml:"plugins>plugin,omitempty"`
+	ExcludeDefaults *string            `xml:"excludeDefaults,omitempty" json:"excludeDefaults,omitempty" yaml:"excludeDefaults,omitempty"`
+	OutputDirectory *string            `xml:"outputDirectory,omitempty" json:"outputDirectory,omitempty" yaml:"outputDirectory,omitempty"`
+	Plugins         *[]ReportingPlugin `xml:"plugins>plugin,omitempty" json:"plugins,omitempty" yaml:"plugins,omitempty"`
 }
 
 type ReportingPlugin struct {
-	GroupID       *string      `xml:"groupId,omitempty"`
-	ArtifactID    *string      `xml:"artifactId,omitempty"`
-	Version       *string      `xml:"version,omitempty"`
-	Inherited     *string      `xml:"inherited,omitempty"`
-	ReportSets    *[]ReportSet `xml:"reportSets>reportSet,omitempty"`
-	Configuration *Properties  `xml:"configuration,omitempty"`
+	GroupID       *string      `xml:"groupId,omitempty" json:"groupId,omitempty" yaml:"groupId,omitempty"`
+	ArtifactID    *string      `xml:"artifactId,omitempty" json:"artifactId,omitempty" yaml:"artifactId,omitempty"`
+	Version       *string      `xml:"version,omitempty" json:"version,omitempty" yaml:"version,omitempty"`
+	Inherited     *string      `xml:"inherited,omitempty" json:"inherited,omitempty" yaml:"inherited,omitempty"`
+	ReportSets    *[]ReportSet `xml:"reportSets>reportSet,omitempty" json:"reportSets,omitempty" yaml:"reportSets,omitempty"`
+	Configuration *Properties  `xml:"configuration,omitempty" json:"configuration,omitempty" yaml:"configuration,omitempty"`
 }
 
 type ReportSet struct {
-	ID            *string     `xml:"id,omitempty"`
-	Reports       *[]string   `xml:"reports>report,omitempty"`
-	Inherited     *string     `xml:"inherited,omitempty"`
-	Configuration *Properties `xml:"configuration,omitempty"`
+	ID            *string     `xml:"id,omitempty" json:"id,omitempty" yaml:"id,omitempty"`
+	Reports       *[]string   `xml:"reports>report,omitempty" json:"reports,omitempty" yaml:"reports,omitempty"`
+	Inherited     *string     `xml:"inherited,omitempty" json:"inherited,omitempty" yaml:"inherited,omitempty"`
+	Configuration *Properties `xml:"configuration,omitempty" json:"configuration,omitempty" yaml:"configuration,omitempty"`
 }
 
 type Profile struct {
-	ID                     *string                 `xml:"id,omitempty"`
-	Activation             *Activation             `xml:"activation,omitempty"`
-	Build                  *BuildBase              `xml:"build,omitempty"`
-	Modules                *[]string               `xml:"modules>module,omitempty"`
-	DistributionManagement *DistributionManagement `xml:"distributionManagement,omitempty"`
-	Properties             *Properties             `xml:"properties,omitempty"`
-	DependencyManagement   *DependencyManagement   `xml:"dependencyManagement,omitempty"`
-	Dependencies           *[]Dependency           `xml:"dependencies>dependency,omitempty"`
-	Repositories           *[]Repository           `xml:"repositories>repository,omitempty"`
-	PluginRepositories     *[]PluginRepository     `xml:"pluginRepositories>pluginRepository,omitempty"`
-	Reporting              *Reporting              `xml:"reporting,omitempty"`
+	ID                     *string                 `xml:"id,omitempty" json:"id,omitempty" yaml:"id,omitempty"`
+	Activation             *Activation             `xml:"activation,omitempty" json:"activation,omitempty" yaml:"activation,omitempty"`
+	Build                  *BuildBase              `xml:"build,omitempty" json:"build,omitempty" yaml:"build,omitempty"`
+	Modules                *[]string               `xml:"modules>module,omitempty" json:"modules,omitempty" yaml:"modules,omitempty"`
+	Subprojects            *[]string               `xml:"subprojects>subproject,omitempty" json:"subprojects,omitempty" yaml:"subprojects,omitempty"`
+	DistributionManagement *DistributionManagement `xml:"distributionManagement,omitempty" json:"distributionManagement,omitempty" yaml:"distributionManagement,omitempty"`
+	Properties             *Properties             `xml:"properties,omitempty" json:"properties,omitempty" yaml:"properties,omitempty"`
+	DependencyManagement   *DependencyManagement   `xml:"dependencyManagement,omitempty" json:"dependencyManagement,omitempty" yaml:"dependencyManagement,omitempty"`
+	Dependencies           *[]Dependency           `xml:"dependencies>dependency,omitempty" json:"dependencies,omitempty" yaml:"dependencies,omitempty"`
+	Repositories           *[]Repository           `xml:"repositories>repository,omitempty" json:"repositories,omitempty" yaml:"repositories,omitempty"`
+	PluginRepositories     *[]PluginRepository     `xml:"pluginRepositories>pluginRepository,omitempty" json:"pluginRepositories,omitempty" yaml:"pluginRepositories,omitempty"`
+	Reports                *Properties             `xml:"reports,omitempty" json:"reports,omitempty" yaml:"reports,omitempty"`
+	Reporting              *Reporting              `xml:"reporting,omitempty" json:"reporting,omitempty" yaml:"reporting,omitempty"`
 }
 
 type Activation struct {
-	ActiveByDefault *bool               `xml:"activeByDefault,omitempty"`
-	JDK             *string             `xml:"jdk,omitempty"`
-	OS              *ActivationOS       `xml:"os,omitempty"`
-	Property        *ActivationProperty `xml:"property,omitempty"`
-	File            *ActivationFile     `xml:"file,omitempty"`
+	ActiveByDefault *bool               `xml:"activeByDefault,omitempty" json:"activeByDefault,omitempty" yaml:"activeByDefault,omitempty"`
+	JDK             *string             `xml:"jdk,omitempty" json:"jdk,omitempty" yaml:"jdk,omitempty"`
+	OS              *ActivationOS       `xml:"os,omitempty" json:"os,omitempty" yaml:"os,omitempty"`
+	Property        *ActivationProperty `xml:"property,omitempty" json:"property,omitempty" yaml:"property,omitempty"`
+	File            *ActivationFile     `xml:"file,omitempty" json:"file,omitempty" yaml:"file,omitempty"`
+	Packaging       *string             `xml:"packaging,omitempty" json:"packaging,omitempty" yaml:"packaging,omitempty"`
+	Condition       *string             `xml:"condition,omitempty" json:"condition,omitempty" yaml:"condition,omitempty"`
 }
 
 type ActivationOS struct {
-	Name    *string `xml:"name,omitempty"`
-	Family  *string `xml:"family,omitempty"`
-	Arch    *string `xml:"arch,omitempty"`
-	Version *string `xml:"version,omitempty"`
+	Name    *string `xml:"name,omitempty" json:"name,omitempty" yaml:"name,omitempty"`
+	Family  *string `xml:"family,omitempty" json:"family,omitempty" yaml:"family,omitempty"`
+	Arch    *string `xml:"arch,omitempty" json:"arch,omitempty" yaml:"arch,omitempty"`
+	Version *string `xml:"version,omitempty" json:"version,omitempty" yaml:"version,omitempty"`
 }
 
 type ActivationProperty struct {
-	Name  *string `xml:"name,omitempty"`
-	Value *string `xml:"value,omitempty"`
+	Name  *string `xml:"name,omitempty" json:"name,omitempty" yaml:"name,omitempty"`
+	Value *string `xml:"value,omitempty" json:"value,omitempty" yaml:"value,omitempty"`
 }
 
 type ActivationFile struct {
-	Missing *string `xml:"missing,omitempty"`
-	Exists  *string `xml:"exists,omitempty"`
+	Missing *string `xml:"missing,omitempty" json:"missing,omitempty" yaml:"missing,omitempty"`
+	Exists  *string `xml:"exists,omitempty" json:"exists,omitempty" yaml:"exists,omitempty"`
 }
@@ -2,10 +2,14 @@ package gopom
 
 import (
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"io"
+	"io/ioutil"
 	"os"
 	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -876,3 +880,224 @@ func Test_MarshallingShouldOmitEmpty(t *testing.T) {
 	// Should only include the properties added
 	assert.Equal(t, string(marshaledXml), "<project><name>testing</name></project>")
 }
+
+func Test_ParsingLegacyPluginGoals(t *testing.T) {
+	plugin := (*p.Build.PluginManagement.Plugins)[0]
+	assert.Equal(t, []string{"goal"}, *plugin.Goals)
+	assert.NotNil(t, p.Reports)
+}
+
+var inheritancePom = `<project child.project.url.inherit.append.path="false">
+  <parent>
+    <groupId>com.test</groupId>
+    <artifactId>parent</artifactId>
+  </parent>
+  <artifactId>child</artifactId>
+  <scm child.scm.connection.inherit.append.path="false" child.scm.developerConnection.inherit.append.path="true" child.scm.url.inherit.append.path="false">
+    <url>https://example.com/scm</url>
+  </scm>
+  <distributionManagement>
+    <site child.site.url.inherit.append.path="false">
+      <id>site</id>
+    </site>
+  </distributionManagement>
+  <build>
+    <plugins>
+      <plugin>
+        <artifactId>maven-antrun-plugin</artifactId>
+        <executions>
+          <execution><id>last</id><priority>10</priority></execution>
+          <execution><id>default</id></execution>
+          <execution><id>first</id><priority>-1</priority></execution>
+          <execution><id>default2</id><priority>0</priority></execution>
+        </executions>
+      </plugin>
+    </plugins>
+  </build>
+  <profiles>
+    <profile>
+      <activation><packaging>war</packaging></activation>
+      <reports><report>index</report></reports>
+    </profile>
+  </profiles>
+</project>`
+
+func Test_ParsingInheritanceAttributes(t *testing.T) {
+	project, err := ParseFromReader(strings.NewReader(inheritancePom), WithStrict())
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Equal(t, "false", *project.ChildURLInheritAppendPath)
+	assert.Equal(t, "false", *project.SCM.ChildConnectionInheritAppendPath)
+	assert.Equal(t, "true", *project.SCM.ChildDeveloperConnectionInheritAppendPath)
+	assert.Equal(t, "false", *project.SCM.ChildURLInheritAppendPath)
+	assert.Equal(t, "false", *project.DistributionManagement.Site.ChildURLInheritAppendPath)
+	assert.Nil(t, project.Parent.Version)
+	assert.Equal(t, "war", *(*project.Profiles)[0].Activation.Packaging)
+	assert.Equal(t, "index", (*project.Profiles)[0].Reports.Entries["report"])
+}
+
+func Test_MarshalingInheritanceAttributesRoundTrip(t *testing.T) {
+	project, err := ParseFromReader(strings.NewReader(inheritancePom))
+	assert.Nil(t, err)
+
+	b, err := xml.Marshal(project)
+	assert.Nil(t, err)
+	for _, s := range []string{
+		`<project child.project.url.inherit.append.path="false">`,
+		`<scm child.scm.connection.inherit.append.path="false" child.scm.developerConnection.inherit.append.path="true" child.scm.url.inherit.append.path="false">`,
+		`<site child.site.url.inherit.append.path="false">`,
+		`<parent><groupId>com.test</groupId><artifactId>parent</artifactId></parent>`,
+		`<activation><packaging>war</packaging></activation>`,
+		`<execution><id>last</id><priority>10</priority></execution>`,
+	} {
+		assert.Contains(t, string(b), s)
+	}
+
+	var parsed Project
+	assert.Nil(t, xml.Unmarshal(b, &parsed))
+	assert.Equal(t, *project, parsed)
+}
+
+func Test_ExecutionsByPriority(t *testing.T) {
+	project, err := ParseFromReader(strings.NewReader(inheritancePom))
+	assert.Nil(t, err)
+
+	plugin := (*project.Build.Plugins)[0]
+	var ids []string
+	for _, e := range plugin.ExecutionsByPriority() {
+		ids = append(ids, *e.ID)
+	}
+	assert.Equal(t, []string{"first", "default", "default2", "last"}, ids)
+	assert.Equal(t, "last", *(*plugin.Executions)[0].ID)
+}
+
+type failingReader struct {
+	r io.Reader
+}
+
+func (f failingReader) Read(b []byte) (int, error) {
+	n, err := f.r.Read(b)
+	if err == io.EOF {
+		return n, errors.New("connection reset")
+	}
+	return n, err
+}
+
+func Test_ParseFromReaderReportsReadErrors(t *testing.T) {
+	_, err := ParseFromReader(failingReader{strings.NewReader(examplePom[:200])})
+	assert.EqualError(t, err, "connection reset")
+
+	_, err = ParseFromReader(failingReader{strings.NewReader(examplePom[:200])}, WithSections(SectionCoordinates))
+	assert.EqualError(t, err, "connection reset")
+}
+
+func Test_ParseMissingFile(t *testing.T) {
+	_, err := Parse("does-not-exist.xml")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func Test_ParseWithSections(t *testing.T) {
+	project, err := ParseFromReader(strings.NewReader(examplePom), WithSections(SectionCoordinates, SectionDependencies, SectionModules))
+	assert.Nil(t, err)
+
+	assert.Equal(t, "4.0.0", *project.ModelVersion)
+	assert.Equal(t, "com.test", *project.GroupID)
+	assert.Equal(t, "test-application", *project.ArtifactID)
+	assert.Equal(t, "1.0.0", *project.Version)
+	assert.Equal(t, "war", *project.Packaging)
+	assert.Equal(t, *p.Dependencies, *project.Dependencies)
+	assert.Equal(t, []string{"module1", "module2"}, *project.Modules)
+
+	assert.Nil(t, project.Parent)
+	assert.Nil(t, project.Name)
+	assert.Nil(t, project.Build)
+	assert.Nil(t, project.Profiles)
+	assert.Nil(t, project.Properties)
+}
+
+func Test_ParseWithSectionsAndLocations(t *testing.T) {
+	project, err := ParseFromReader(strings.NewReader(locationPom), WithSections("properties"), WithLocations())
+	assert.Nil(t, err)
+
+	assert.Nil(t, project.Dependencies)
+	assert.Equal(t, "11", project.Properties.Entries["java.version"])
+	assert.Equal(t, "14:5", project.Locations["project.properties.java.version"].String())
+}
+
+// largePom is examplePom with a thousand additional dependencies, the size of
+// the biggest poms found in a typical repository.
+var largePom = strings.Replace(examplePom, "<dependencies>", "<dependencies>"+strings.Repeat(`
+    <dependency>
+      <groupId>org.example</groupId>
+      <artifactId>library</artifactId>
+      <version>1.0.0</version>
+      <exclusions>
+        <exclusion>
+          <groupId>commons-logging</groupId>
+          <artifactId>commons-logging</artifactId>
+        </exclusion>
+      </exclusions>
+    </dependency>`, 1000), 1)
+
+// BenchmarkUnmarshal measures reading the whole input and unmarshaling it at once,
+// the way poms were parsed before the decoding was streamed.
+func BenchmarkUnmarshal(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		data, err := ioutil.ReadAll(strings.NewReader(largePom))
+		if err != nil {
+			b.Fatal(err)
+		}
+		var project Project
+		if err := xml.Unmarshal(data, &project); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseFromReader(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseFromReader(strings.NewReader(largePom)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseFromReaderSections(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseFromReader(strings.NewReader(largePom), WithSections(SectionCoordinates, SectionParent)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseFromReaderLocations(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseFromReader(strings.NewReader(largePom), WithLocations()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func Test_NestedConfigurationRoundTrip(t *testing.T) {
+	project, err := ParseFromReader(strings.NewReader(`<project>
+  <build><plugins><plugin>
+    <configuration>
+      <release>11</release>
+      <compilerArgs>
+        <arg>-Xlint</arg>
+        <arg>-parameters</arg>
+      </compilerArgs>
+    </configuration>
+  </plugin></plugins></build>
+</project>`))
+	assert.Nil(t, err)
+	configuration := (*project.Build.Plugins)[0].Configuration
+	assert.Equal(t, "11", configuration.Entries["release"])
+	assert.Equal(t, map[string]string{"compilerArgs": "<arg>-Xlint</arg><arg>-parameters</arg>"}, configuration.RawXML)
+
+	out, err := xml.Marshal(configuration)
+	assert.Nil(t, err)
+	assert.Equal(t, "<Properties><compilerArgs><arg>-Xlint</arg><arg>-parameters</arg></compilerArgs><release>11</release></Properties>", string(out))
+}
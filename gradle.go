@@ -0,0 +1,445 @@
+package gopom
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GradleExport is a Gradle Kotlin DSL build converted from a pom.
+type GradleExport struct {
+	// BuildScript is the content of build.gradle.kts.
+	BuildScript string
+	// SettingsScript is the content of settings.gradle.kts.
+	SettingsScript string
+	// Untranslated lists the parts of the pom that have no equivalent in the scripts.
+	Untranslated []UntranslatedItem
+}
+
+// UntranslatedItem is a part of a pom that could not be converted.
+type UntranslatedItem struct {
+	// Element is the path of the element, e.g. "build.plugins[maven-enforcer-plugin]".
+	Element string `json:"element"`
+	Reason  string `json:"reason"`
+}
+
+func (u UntranslatedItem) String() string {
+	return u.Element + ": " + u.Reason
+}
+
+// gradleConfigurations maps Maven scopes to Gradle configurations.
+var gradleConfigurations = map[Scope]string{
+	ScopeCompile:  "implementation",
+	ScopeProvided: "compileOnly",
+	ScopeRuntime:  "runtimeOnly",
+	ScopeTest:     "testImplementation",
+}
+
+// gradlePlugins are the Gradle plugins replacing Maven build plugins, keyed
+// by groupId:artifactId. An empty plugin means that Gradle covers the Maven
+// plugin without configuration.
+var gradlePlugins = map[string]string{
+	"org.apache.maven.plugins:maven-clean-plugin":       "",
+	"org.apache.maven.plugins:maven-compiler-plugin":    "",
+	"org.apache.maven.plugins:maven-deploy-plugin":      "",
+	"org.apache.maven.plugins:maven-install-plugin":     "",
+	"org.apache.maven.plugins:maven-jar-plugin":         "",
+	"org.apache.maven.plugins:maven-javadoc-plugin":     "",
+	"org.apache.maven.plugins:maven-resources-plugin":   "",
+	"org.apache.maven.plugins:maven-source-plugin":      "",
+	"org.apache.maven.plugins:maven-surefire-plugin":    "",
+	"org.apache.maven.plugins:maven-war-plugin":         "war",
+	"org.apache.maven.plugins:maven-shade-plugin":       `id("com.github.johnrengelman.shadow") version "7.1.2"`,
+	"org.springframework.boot:spring-boot-maven-plugin": `id("org.springframework.boot")`,
+	"org.jetbrains.kotlin:kotlin-maven-plugin":          `kotlin("jvm")`,
+}
+
+// ExportGradle converts a pom into a Gradle Kotlin DSL build. Pass the
+// effective pom to include what the project inherits from its parents.
+// Expressions are interpolated with the properties of the project.
+func ExportGradle(p *Project) *GradleExport {
+	g := &gradleWriter{p: p}
+	g.write()
+	return &GradleExport{BuildScript: g.build.String(), SettingsScript: g.settings(), Untranslated: g.untranslated}
+}
+
+type gradleWriter struct {
+	p            *Project
+	build        strings.Builder
+	untranslated []UntranslatedItem
+}
+
+func (g *gradleWriter) skip(element, reason string) {
+	g.untranslated = append(g.untranslated, UntranslatedItem{Element: element, Reason: reason})
+}
+
+// str returns s interpolated as a Kotlin string literal.
+func (g *gradleWriter) str(element, s string) string {
+	s = g.p.Interpolate(s)
+	if strings.Contains(s, "${") {
+		g.skip(element, fmt.Sprintf("unresolved expression in %q", s))
+	}
+	return kotlinString(s)
+}
+
+func kotlinString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`, "\n", `\n`).Replace(s) + `"`
+}
+
+func (g *gradleWriter) line(format string, args ...interface{}) {
+	fmt.Fprintf(&g.build, format+"\n", args...)
+}
+
+func (g *gradleWriter) write() {
+	p := g.p
+	g.plugins()
+
+	if p.GroupID != nil {
+		g.line("group = %s", g.str("groupId", *p.GroupID))
+	} else if p.Parent != nil && p.Parent.GroupID != nil {
+		g.line("group = %s", g.str("parent.groupId", *p.Parent.GroupID))
+	}
+	if p.Version != nil {
+		g.line("version = %s", g.str("version", *p.Version))
+	} else if p.Parent != nil && p.Parent.Version != nil {
+		g.line("version = %s", g.str("parent.version", *p.Parent.Version))
+	}
+	if p.Description != nil {
+		g.line("description = %s", g.str("description", strings.TrimSpace(*p.Description)))
+	}
+	if !strings.HasSuffix(g.build.String(), "\n\n") && g.build.Len() > 0 {
+		g.line("")
+	}
+
+	g.java()
+	g.repositories()
+	g.dependencies()
+	g.tasks()
+
+	if p.Parent != nil {
+		g.skip("parent", "parent poms have no Gradle equivalent, export the effective pom to include inherited configuration")
+	}
+	if p.Profiles != nil {
+		for _, profile := range *p.Profiles {
+			g.skip(fmt.Sprintf("profiles[%s]", deref(profile.ID)), "profiles have no Gradle equivalent")
+		}
+	}
+	if p.DistributionManagement != nil {
+		g.skip("distributionManagement", "configure publishing with the maven-publish plugin")
+	}
+}
+
+func (g *gradleWriter) packaging() string {
+	return derefOr(g.p.Packaging, string(PackagingJar))
+}
+
+func (g *gradleWriter) plugins() {
+	var plugins []string
+	add := func(plugin string) {
+		for _, existing := range plugins {
+			if existing == plugin {
+				return
+			}
+		}
+		plugins = append(plugins, plugin)
+	}
+
+	switch packaging := g.packaging(); packaging {
+	case string(PackagingJar):
+		add("java")
+	case string(PackagingWar):
+		add("war")
+	case string(PackagingPom):
+		if g.p.DependencyManagement != nil && g.p.DependencyManagement.Dependencies != nil {
+			add("`java-platform`")
+		}
+	default:
+		add("java")
+		g.skip("packaging", fmt.Sprintf("packaging %s is built as a jar", packaging))
+	}
+
+	for _, plugin := range g.buildPlugins() {
+		key := plugin.Coordinates().Key().String()
+		element := fmt.Sprintf("build.plugins[%s]", plugin.Coordinates().ArtifactID)
+		gradle, ok := gradlePlugins[key]
+		switch {
+		case !ok:
+			g.skip(element, "no known Gradle equivalent")
+		case gradle == "war":
+			add(gradle)
+		case strings.HasPrefix(gradle, "id(") || strings.HasPrefix(gradle, "kotlin("):
+			if plugin.Version != nil && !strings.Contains(gradle, " version ") {
+				gradle += " version " + g.str(element+".version", *plugin.Version)
+			}
+			add(gradle)
+		}
+	}
+
+	if len(plugins) == 0 {
+		return
+	}
+	g.line("plugins {")
+	for _, plugin := range plugins {
+		g.line("    %s", plugin)
+	}
+	g.line("}")
+	g.line("")
+}
+
+func (g *gradleWriter) buildPlugins() []Plugin {
+	if g.p.Build == nil || g.p.Build.Plugins == nil {
+		return nil
+	}
+	return *g.p.Build.Plugins
+}
+
+func (g *gradleWriter) plugin(artifactID string) *Plugin {
+	for _, plugin := range g.buildPlugins() {
+		c := plugin.Coordinates()
+		if c.GroupID == "org.apache.maven.plugins" && c.ArtifactID == artifactID {
+			return &plugin
+		}
+	}
+	return nil
+}
+
+// javaVersion returns the Java release from the compiler plugin
+// configuration or the properties it reads by default.
+func (g *gradleWriter) javaVersion() string {
+	var candidates []string
+	if compiler := g.plugin("maven-compiler-plugin"); compiler != nil && compiler.Configuration != nil {
+		entries := compiler.Configuration.Entries
+		candidates = append(candidates, entries["release"], entries["target"], entries["source"])
+	}
+	if g.p.Properties != nil {
+		entries := g.p.Properties.Entries
+		candidates = append(candidates, entries["maven.compiler.release"], entries["maven.compiler.target"], entries["maven.compiler.source"], entries["java.version"])
+	}
+	for _, c := range candidates {
+		if v := strings.TrimSpace(g.p.Interpolate(c)); v != "" && !strings.Contains(v, "${") {
+			return strings.TrimPrefix(v, "1.")
+		}
+	}
+	return ""
+}
+
+func (g *gradleWriter) java() {
+	if g.packaging() == string(PackagingPom) {
+		return
+	}
+	version := g.javaVersion()
+	sources := g.plugin("maven-source-plugin") != nil
+	javadoc := g.plugin("maven-javadoc-plugin") != nil
+	if version == "" && !sources && !javadoc {
+		return
+	}
+	g.line("java {")
+	if version != "" {
+		g.line("    toolchain {")
+		g.line("        languageVersion.set(JavaLanguageVersion.of(%s))", version)
+		g.line("    }")
+	}
+	if sources {
+		g.line("    withSourcesJar()")
+	}
+	if javadoc {
+		g.line("    withJavadocJar()")
+	}
+	g.line("}")
+	g.line("")
+}
+
+func (g *gradleWriter) repositories() {
+	g.line("repositories {")
+	g.line("    mavenCentral()")
+	if g.p.Repositories != nil {
+		for _, repo := range *g.p.Repositories {
+			if deref(repo.ID) == "central" || repo.URL == nil {
+				continue
+			}
+			g.line("    maven {")
+			g.line("        url = uri(%s)", g.str(fmt.Sprintf("repositories[%s].url", deref(repo.ID)), *repo.URL))
+			g.line("    }")
+			if layout := deref(repo.Layout); layout != "" && layout != "default" {
+				g.skip(fmt.Sprintf("repositories[%s].layout", deref(repo.ID)), fmt.Sprintf("layout %s is not supported", layout))
+			}
+		}
+	}
+	g.line("}")
+	g.line("")
+}
+
+func (g *gradleWriter) dependencies() {
+	var lines []string
+	var constraints []string
+	platform := g.packaging() == string(PackagingPom)
+
+	if g.p.DependencyManagement != nil && g.p.DependencyManagement.Dependencies != nil {
+		for i, d := range *g.p.DependencyManagement.Dependencies {
+			element := fmt.Sprintf("dependencyManagement.dependencies[%d]", i)
+			notation := g.notation(element, d)
+			switch {
+			case deref(d.Scope) == string(ScopeImport):
+				configuration := "implementation"
+				if platform {
+					configuration = "api"
+				}
+				lines = append(lines, fmt.Sprintf("%s(platform(%s))", configuration, notation))
+			case platform:
+				constraints = append(constraints, fmt.Sprintf("api(%s)", notation))
+			default:
+				constraints = append(constraints, fmt.Sprintf("implementation(%s)", notation))
+			}
+		}
+	}
+
+	if g.p.Dependencies != nil {
+		for i, d := range *g.p.Dependencies {
+			element := fmt.Sprintf("dependencies[%d]", i)
+			if line, ok := g.dependency(element, d); ok {
+				lines = append(lines, line)
+			}
+		}
+	}
+
+	if len(lines) == 0 && len(constraints) == 0 {
+		return
+	}
+	if platform && len(constraints) > 0 {
+		g.line("javaPlatform {")
+		g.line("    allowDependencies()")
+		g.line("}")
+		g.line("")
+	}
+	g.line("dependencies {")
+	for _, line := range lines {
+		g.line("    %s", line)
+	}
+	if len(constraints) > 0 {
+		g.line("    constraints {")
+		for _, c := range constraints {
+			g.line("        %s", c)
+		}
+		g.line("    }")
+	}
+	g.line("}")
+	g.line("")
+}
+
+func (g *gradleWriter) dependency(element string, d Dependency) (string, bool) {
+	scope := Scope(g.p.Interpolate(derefOr(d.Scope, string(ScopeCompile))))
+	if scope == ScopeSystem {
+		g.skip(element, fmt.Sprintf("system dependency %s, use files(%q) instead", d.Coordinates(), deref(d.SystemPath)))
+		return "", false
+	}
+	configuration, ok := gradleConfigurations[scope]
+	if !ok {
+		g.skip(element, fmt.Sprintf("unknown scope %s", scope))
+		return "", false
+	}
+	if optional, _ := d.IsOptional(g.p); optional {
+		g.skip(element, "optional dependencies have no Gradle equivalent, it is declared as a regular dependency")
+	}
+
+	declaration := fmt.Sprintf("%s(%s)", configuration, g.notation(element, d))
+	if d.Type != nil && deref(d.Type) == "pom" {
+		declaration = fmt.Sprintf("%s(platform(%s))", configuration, g.notation(element, d))
+	}
+	if d.Exclusions == nil || len(*d.Exclusions) == 0 {
+		return declaration, true
+	}
+
+	var excludes []string
+	for _, e := range *d.Exclusions {
+		group, module := derefOr(e.GroupID, "*"), derefOr(e.ArtifactID, "*")
+		switch {
+		case group == "*" && module == "*":
+			excludes = append(excludes, "isTransitive = false")
+		case module == "*":
+			excludes = append(excludes, fmt.Sprintf("exclude(group = %s)", kotlinString(group)))
+		case group == "*":
+			excludes = append(excludes, fmt.Sprintf("exclude(module = %s)", kotlinString(module)))
+		default:
+			excludes = append(excludes, fmt.Sprintf("exclude(group = %s, module = %s)", kotlinString(group), kotlinString(module)))
+		}
+	}
+	return declaration + " {\n        " + strings.Join(excludes, "\n        ") + "\n    }", true
+}
+
+// notation returns the Gradle dependency notation group:name[:version[:classifier]][@extension].
+func (g *gradleWriter) notation(element string, d Dependency) string {
+	c := d.Coordinates()
+	parts := []string{c.GroupID, c.ArtifactID}
+	if c.Version != "" || c.Classifier != "" {
+		parts = append(parts, c.Version)
+	}
+	if c.Classifier != "" {
+		parts = append(parts, c.Classifier)
+	}
+	notation := strings.Join(parts, ":")
+	switch c.Type {
+	case "", "jar", "pom":
+	case "test-jar":
+		if c.Classifier == "" {
+			notation += ":tests"
+		}
+	default:
+		notation += "@" + c.Type
+	}
+	return g.str(element, notation)
+}
+
+func (g *gradleWriter) tasks() {
+	usesJUnit5 := false
+	if g.p.Dependencies != nil {
+		for _, d := range *g.p.Dependencies {
+			c := d.Coordinates()
+			usesJUnit5 = usesJUnit5 || strings.HasPrefix(c.GroupID, "org.junit.jupiter") || c.ArtifactID == "junit-bom"
+		}
+	}
+	if g.p.DependencyManagement != nil && g.p.DependencyManagement.Dependencies != nil {
+		for _, d := range *g.p.DependencyManagement.Dependencies {
+			usesJUnit5 = usesJUnit5 || d.Coordinates().ArtifactID == "junit-bom"
+		}
+	}
+	if usesJUnit5 && g.packaging() != string(PackagingPom) {
+		g.line("tasks.test {")
+		g.line("    useJUnitPlatform()")
+		g.line("}")
+		g.line("")
+	}
+
+	if g.p.Build == nil {
+		return
+	}
+	if g.p.Build.FinalName != nil {
+		g.skip("build.finalName", "set archiveFileName on the archive task instead")
+	}
+	for _, resources := range []struct {
+		element string
+		list    *[]Resource
+	}{{"build.resources", g.p.Build.Resources}, {"build.testResources", g.p.Build.TestResources}} {
+		if resources.list == nil {
+			continue
+		}
+		for _, r := range *resources.list {
+			if filtering, _ := r.IsFiltering(g.p); filtering {
+				g.skip(resources.element, fmt.Sprintf("filtering of %s, use processResources with expand()", deref(r.Directory)))
+			}
+		}
+	}
+}
+
+func (g *gradleWriter) settings() string {
+	var settings strings.Builder
+	if g.p.ArtifactID != nil {
+		fmt.Fprintf(&settings, "rootProject.name = %s\n", kotlinString(*g.p.ArtifactID))
+	}
+	modules := g.p.ChildProjects()
+	if len(modules) > 0 {
+		settings.WriteString("\n")
+		for _, module := range modules {
+			fmt.Fprintf(&settings, "include(%s)\n", kotlinString(strings.Trim(module, "/")))
+		}
+	}
+	return settings.String()
+}
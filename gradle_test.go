@@ -0,0 +1,138 @@
+package gopom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const gradlePom = `<project>
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>com.example</groupId>
+  <artifactId>service</artifactId>
+  <version>1.0.0-SNAPSHOT</version>
+  <description>Example "service"</description>
+  <properties>
+    <java.version>1.8</java.version>
+    <jackson.version>2.12.1</jackson.version>
+  </properties>
+  <modules>
+    <module>api</module>
+  </modules>
+  <repositories>
+    <repository><id>internal</id><url>https://repo.example.com/maven</url></repository>
+  </repositories>
+  <dependencyManagement>
+    <dependencies>
+      <dependency><groupId>org.junit</groupId><artifactId>junit-bom</artifactId><version>5.7.0</version><type>pom</type><scope>import</scope></dependency>
+      <dependency><groupId>com.google.guava</groupId><artifactId>guava</artifactId><version>30.1-jre</version></dependency>
+    </dependencies>
+  </dependencyManagement>
+  <dependencies>
+    <dependency>
+      <groupId>com.fasterxml.jackson.core</groupId><artifactId>jackson-databind</artifactId><version>${jackson.version}</version>
+      <exclusions>
+        <exclusion><groupId>com.fasterxml.jackson.core</groupId><artifactId>jackson-annotations</artifactId></exclusion>
+        <exclusion><groupId>org.slf4j</groupId><artifactId>*</artifactId></exclusion>
+      </exclusions>
+    </dependency>
+    <dependency><groupId>com.google.guava</groupId><artifactId>guava</artifactId></dependency>
+    <dependency><groupId>javax.servlet</groupId><artifactId>javax.servlet-api</artifactId><version>4.0.1</version><scope>provided</scope></dependency>
+    <dependency><groupId>org.postgresql</groupId><artifactId>postgresql</artifactId><version>42.2.18</version><scope>runtime</scope></dependency>
+    <dependency><groupId>org.junit.jupiter</groupId><artifactId>junit-jupiter</artifactId><scope>test</scope></dependency>
+    <dependency><groupId>com.example</groupId><artifactId>model</artifactId><version>${model.version}</version><type>test-jar</type><scope>test</scope></dependency>
+    <dependency><groupId>com.sun</groupId><artifactId>tools</artifactId><version>1.8</version><scope>system</scope><systemPath>/opt/tools.jar</systemPath></dependency>
+  </dependencies>
+  <build>
+    <plugins>
+      <plugin><artifactId>maven-compiler-plugin</artifactId><version>3.8.1</version></plugin>
+      <plugin><artifactId>maven-source-plugin</artifactId></plugin>
+      <plugin><groupId>org.springframework.boot</groupId><artifactId>spring-boot-maven-plugin</artifactId><version>2.4.2</version></plugin>
+      <plugin><artifactId>maven-enforcer-plugin</artifactId></plugin>
+    </plugins>
+  </build>
+  <profiles><profile><id>release</id></profile></profiles>
+</project>`
+
+func Test_ExportGradle(t *testing.T) {
+	project, err := ParseFromReader(strings.NewReader(gradlePom))
+	assert.Nil(t, err)
+
+	export := ExportGradle(project)
+	assert.Equal(t, `plugins {
+    java
+    id("org.springframework.boot") version "2.4.2"
+}
+
+group = "com.example"
+version = "1.0.0-SNAPSHOT"
+description = "Example \"service\""
+
+java {
+    toolchain {
+        languageVersion.set(JavaLanguageVersion.of(8))
+    }
+    withSourcesJar()
+}
+
+repositories {
+    mavenCentral()
+    maven {
+        url = uri("https://repo.example.com/maven")
+    }
+}
+
+dependencies {
+    implementation(platform("org.junit:junit-bom:5.7.0"))
+    implementation("com.fasterxml.jackson.core:jackson-databind:2.12.1") {
+        exclude(group = "com.fasterxml.jackson.core", module = "jackson-annotations")
+        exclude(group = "org.slf4j")
+    }
+    implementation("com.google.guava:guava")
+    compileOnly("javax.servlet:javax.servlet-api:4.0.1")
+    runtimeOnly("org.postgresql:postgresql:42.2.18")
+    testImplementation("org.junit.jupiter:junit-jupiter")
+    testImplementation("com.example:model:\${model.version}:tests")
+    constraints {
+        implementation("com.google.guava:guava:30.1-jre")
+    }
+}
+
+tasks.test {
+    useJUnitPlatform()
+}
+
+`, export.BuildScript)
+	assert.Equal(t, "rootProject.name = \"service\"\n\ninclude(\"api\")\n", export.SettingsScript)
+
+	var untranslated []string
+	for _, u := range export.Untranslated {
+		untranslated = append(untranslated, u.String())
+	}
+	assert.Equal(t, []string{
+		"build.plugins[maven-enforcer-plugin]: no known Gradle equivalent",
+		`dependencies[5]: unresolved expression in "com.example:model:${model.version}:tests"`,
+		`dependencies[6]: system dependency com.sun:tools:1.8, use files("/opt/tools.jar") instead`,
+		"profiles[release]: profiles have no Gradle equivalent",
+	}, untranslated)
+}
+
+func Test_ExportGradlePlatform(t *testing.T) {
+	project, err := ParseFromReader(strings.NewReader(`<project>
+  <groupId>com.example</groupId><artifactId>bom</artifactId><version>1</version><packaging>pom</packaging>
+  <dependencyManagement><dependencies>
+    <dependency><groupId>com.example</groupId><artifactId>core</artifactId><version>1</version></dependency>
+  </dependencies></dependencyManagement>
+</project>`))
+	assert.Nil(t, err)
+
+	export := ExportGradle(project)
+	assert.True(t, strings.HasPrefix(export.BuildScript, "plugins {\n    `java-platform`\n}\n"))
+	assert.Contains(t, export.BuildScript, `dependencies {
+    constraints {
+        api("com.example:core:1")
+    }
+}`)
+	assert.Empty(t, export.Untranslated)
+}
@@ -0,0 +1,203 @@
+package gopom
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// IndexEntry is what the repository indexer records about a single pom.
+type IndexEntry struct {
+	Path         string
+	Coordinates  Coordinates
+	Parent       *Coordinates
+	Packaging    string
+	Licenses     []License
+	Dependencies []IndexedDependency
+}
+
+// IndexedDependency is a dependency declared by an indexed pom.
+type IndexedDependency struct {
+	Coordinates Coordinates
+	Scope       string
+	Optional    bool
+	// Managed is set for entries of dependencyManagement.
+	Managed bool
+}
+
+// IndexError is a pom that could not be indexed.
+type IndexError struct {
+	Path string
+	Err  error
+}
+
+func (e IndexError) Error() string {
+	return e.Path + ": " + e.Err.Error()
+}
+
+// RepositoryIndex is the result of indexing a repository directory.
+type RepositoryIndex struct {
+	Entries []IndexEntry
+	Errors  []IndexError
+}
+
+// IndexOptions configures IndexRepository.
+type IndexOptions struct {
+	// Workers is the number of poms parsed concurrently, runtime.NumCPU() by default.
+	Workers int
+	// Progress is called after every pom with the number of poms processed so
+	// far. Calls are never concurrent.
+	Progress func(done int, path string)
+}
+
+// IndexRepository walks the local repository or mirror at root and parses
+// every *.pom file with a bounded pool of workers. Poms that cannot be parsed
+// and directories that cannot be read are collected in the Errors of the
+// index instead of failing the whole run.
+// When ctx is cancelled the poms indexed so far are returned with ctx.Err().
+func IndexRepository(ctx context.Context, root string, opts IndexOptions) (*RepositoryIndex, error) {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	index := &RepositoryIndex{}
+	var mu sync.Mutex
+	paths := make(chan string)
+	var walkErr error
+	go func() {
+		defer close(paths)
+		walkErr = filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+			// Only an unreadable root fails the run, anything below it is
+			// recorded and skipped.
+			if err != nil && path == root {
+				return err
+			}
+			if err != nil {
+				mu.Lock()
+				index.Errors = append(index.Errors, IndexError{Path: path, Err: err})
+				mu.Unlock()
+				return nil
+			}
+			if info.IsDir() || !strings.HasSuffix(info.Name(), ".pom") {
+				return nil
+			}
+			select {
+			case paths <- path:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+
+	var wg sync.WaitGroup
+	done := 0
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				if ctx.Err() != nil {
+					continue
+				}
+				entry, err := indexPom(path)
+
+				mu.Lock()
+				if err != nil {
+					index.Errors = append(index.Errors, IndexError{Path: path, Err: err})
+				} else {
+					index.Entries = append(index.Entries, entry)
+				}
+				done++
+				if opts.Progress != nil {
+					opts.Progress(done, path)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	sort.Slice(index.Entries, func(i, j int) bool { return index.Entries[i].Path < index.Entries[j].Path })
+	sort.Slice(index.Errors, func(i, j int) bool { return index.Errors[i].Path < index.Errors[j].Path })
+
+	if err := ctx.Err(); err != nil {
+		return index, err
+	}
+	if walkErr != nil {
+		return index, walkErr
+	}
+	return index, nil
+}
+
+func indexPom(path string) (IndexEntry, error) {
+	project, err := Parse(path, WithSections(SectionCoordinates, SectionParent, SectionLicenses, SectionDependencies, SectionDependencyManagement))
+	if err != nil {
+		return IndexEntry{}, err
+	}
+
+	entry := IndexEntry{
+		Path:        path,
+		Coordinates: project.Coordinates(),
+		Packaging:   derefOr(project.Packaging, string(PackagingJar)),
+	}
+	if project.Parent != nil {
+		parent := project.Parent.Coordinates()
+		entry.Parent = &parent
+	}
+	if project.Licenses != nil {
+		entry.Licenses = *project.Licenses
+	}
+	if project.Dependencies != nil {
+		entry.Dependencies = appendIndexedDependencies(entry.Dependencies, project, *project.Dependencies, false)
+	}
+	if project.DependencyManagement != nil && project.DependencyManagement.Dependencies != nil {
+		entry.Dependencies = appendIndexedDependencies(entry.Dependencies, project, *project.DependencyManagement.Dependencies, true)
+	}
+	return entry, nil
+}
+
+func appendIndexedDependencies(indexed []IndexedDependency, project *Project, deps []Dependency, managed bool) []IndexedDependency {
+	for _, d := range deps {
+		optional, _ := d.IsOptional(project)
+		indexed = append(indexed, IndexedDependency{
+			Coordinates: d.Coordinates(),
+			Scope:       deref(d.Scope),
+			Optional:    optional,
+			Managed:     managed,
+		})
+	}
+	return indexed
+}
+
+// Dependents returns the entries that directly depend on, or manage, the artifact with the given key.
+func (i *RepositoryIndex) Dependents(key ArtifactKey) []IndexEntry {
+	var dependents []IndexEntry
+	for _, e := range i.Entries {
+		for _, d := range e.Dependencies {
+			if d.Coordinates.Key() == key {
+				dependents = append(dependents, e)
+				break
+			}
+		}
+	}
+	return dependents
+}
+
+// Find returns the entry with the given groupId:artifactId:version.
+func (i *RepositoryIndex) Find(c Coordinates) (IndexEntry, bool) {
+	for _, e := range i.Entries {
+		if e.Coordinates.GAV() == c.GAV() {
+			return e, true
+		}
+	}
+	return IndexEntry{}, false
+}
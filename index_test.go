@@ -0,0 +1,116 @@
+package gopom
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func writeRepository(t *testing.T, poms map[string]string) string {
+	root := t.TempDir()
+	for path, content := range poms {
+		full := filepath.Join(root, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(full, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+var repositoryPoms = map[string]string{
+	"com/test/parent/1/parent-1.pom": `<project>
+  <groupId>com.test</groupId><artifactId>parent</artifactId><version>1</version><packaging>pom</packaging>
+  <licenses><license><name>Apache-2.0</name></license></licenses>
+  <dependencyManagement><dependencies>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><version>4.13</version></dependency>
+  </dependencies></dependencyManagement>
+</project>`,
+	"com/test/app/1/app-1.pom": `<project>
+  <parent><groupId>com.test</groupId><artifactId>parent</artifactId><version>1</version></parent>
+  <artifactId>app</artifactId>
+  <dependencies>
+    <dependency><groupId>com.test</groupId><artifactId>lib</artifactId><version>2</version></dependency>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><scope>test</scope><optional>true</optional></dependency>
+  </dependencies>
+</project>`,
+	"com/test/lib/2/lib-2.pom":       `<project><groupId>com.test</groupId><artifactId>lib</artifactId><version>2</version></project>`,
+	"com/test/lib/2/lib-2.jar":       `not a pom`,
+	"com/test/broken/1/broken-1.pom": `<project><groupId>`,
+}
+
+func Test_IndexRepository(t *testing.T) {
+	root := writeRepository(t, repositoryPoms)
+
+	var progress []int
+	index, err := IndexRepository(context.Background(), root, IndexOptions{
+		Workers:  2,
+		Progress: func(done int, path string) { progress = append(progress, done) },
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2, 3, 4}, progress)
+
+	assert.Equal(t, 3, len(index.Entries))
+	assert.Equal(t, 1, len(index.Errors))
+	assert.Equal(t, filepath.Join(root, "com", "test", "broken", "1", "broken-1.pom"), index.Errors[0].Path)
+
+	app, ok := index.Find(Coordinates{GroupID: "com.test", ArtifactID: "app", Version: "1"})
+	assert.True(t, ok)
+	assert.Equal(t, "jar", app.Packaging)
+	assert.Equal(t, "com.test:parent:pom:1", app.Parent.String())
+	assert.Equal(t, []IndexedDependency{
+		{Coordinates: Coordinates{GroupID: "com.test", ArtifactID: "lib", Version: "2"}},
+		{Coordinates: Coordinates{GroupID: "junit", ArtifactID: "junit"}, Scope: "test", Optional: true},
+	}, app.Dependencies)
+
+	parent, ok := index.Find(Coordinates{GroupID: "com.test", ArtifactID: "parent", Version: "1"})
+	assert.True(t, ok)
+	assert.Equal(t, "pom", parent.Packaging)
+	assert.Equal(t, "Apache-2.0", *parent.Licenses[0].Name)
+	assert.True(t, parent.Dependencies[0].Managed)
+
+	var dependents []string
+	for _, e := range index.Dependents(ArtifactKey{GroupID: "junit", ArtifactID: "junit"}) {
+		dependents = append(dependents, e.Coordinates.GAV())
+	}
+	assert.Equal(t, []string{"com.test:app:1", "com.test:parent:1"}, dependents)
+}
+
+func Test_IndexRepositoryCancelled(t *testing.T) {
+	root := writeRepository(t, repositoryPoms)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	index, err := IndexRepository(ctx, root, IndexOptions{})
+	assert.Equal(t, context.Canceled, err)
+	assert.Empty(t, index.Entries)
+}
+
+func Test_IndexRepositoryUnreadableDirectory(t *testing.T) {
+	root := writeRepository(t, repositoryPoms)
+	locked := filepath.Join(root, "com", "test", "lib")
+	assert.Nil(t, os.Chmod(locked, 0))
+	defer os.Chmod(locked, 0755)
+	if _, err := ioutil.ReadDir(locked); err == nil {
+		t.Skip("directory permissions are not enforced for this user")
+	}
+
+	index, err := IndexRepository(context.Background(), root, IndexOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(index.Entries))
+	assert.Equal(t, 2, len(index.Errors))
+	assert.Equal(t, filepath.Join(root, "com", "test", "broken", "1", "broken-1.pom"), index.Errors[0].Path)
+	assert.Equal(t, locked, index.Errors[1].Path)
+	assert.True(t, os.IsPermission(index.Errors[1].Err))
+}
+
+func Test_IndexRepositoryMissingRoot(t *testing.T) {
+	_, err := IndexRepository(context.Background(), filepath.Join(t.TempDir(), "missing"), IndexOptions{})
+	assert.True(t, os.IsNotExist(err))
+}
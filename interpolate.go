@@ -0,0 +1,86 @@
+package gopom
+
+import "strings"
+
+// Interpolate replaces the ${...} expressions in s that can be resolved from
+// the project itself: its properties and the project.* and pom.* coordinates.
+// Expressions that cannot be resolved are left in place.
+func (p *Project) Interpolate(s string) string {
+	return p.interpolate(s, map[string]bool{})
+}
+
+func (p *Project) interpolate(s string, resolving map[string]bool) string {
+	var b strings.Builder
+	for {
+		start := strings.Index(s, "${")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(s[start:], "}")
+		if end < 0 {
+			break
+		}
+		end += start
+		b.WriteString(s[:start])
+
+		expr := s[start+2 : end]
+		if value, ok := p.lookup(expr); ok && !resolving[expr] {
+			resolving[expr] = true
+			b.WriteString(p.interpolate(value, resolving))
+			delete(resolving, expr)
+		} else {
+			b.WriteString(s[start : end+1])
+		}
+		s = s[end+1:]
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+func (p *Project) lookup(expr string) (string, bool) {
+	if p.Properties != nil {
+		if value, ok := p.Properties.Entries[expr]; ok {
+			return value, true
+		}
+	}
+
+	field := expr
+	for _, prefix := range []string{"project.", "pom."} {
+		field = strings.TrimPrefix(field, prefix)
+	}
+	if field == expr && expr != "version" && expr != "groupId" && expr != "artifactId" {
+		return "", false
+	}
+
+	c := p.Coordinates()
+	var value string
+	switch field {
+	case "groupId":
+		value = c.GroupID
+	case "artifactId":
+		value = c.ArtifactID
+	case "version":
+		value = c.Version
+	case "packaging":
+		value = derefOr(p.Packaging, string(PackagingJar))
+	case "name":
+		value = deref(p.Name)
+	case "description":
+		value = deref(p.Description)
+	case "url":
+		value = deref(p.URL)
+	case "modelVersion":
+		value = deref(p.ModelVersion)
+	case "parent.groupId", "parent.artifactId", "parent.version":
+		if p.Parent == nil {
+			return "", false
+		}
+		pc := p.Parent.Coordinates()
+		value = map[string]string{
+			"parent.groupId":    pc.GroupID,
+			"parent.artifactId": pc.ArtifactID,
+			"parent.version":    pc.Version,
+		}[field]
+	}
+	return value, value != ""
+}
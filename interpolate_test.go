@@ -0,0 +1,27 @@
+package gopom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Interpolate(t *testing.T) {
+	project := Project{
+		Parent:     &Parent{GroupID: str("com.test"), ArtifactID: str("parent"), Version: str("2.0")},
+		ArtifactID: str("app"),
+		Properties: &Properties{Entries: map[string]string{
+			"spring.version": "5.3.0",
+			"lib.version":    "${spring.version}-patched",
+			"loop":           "${loop}",
+		}},
+	}
+
+	assert.Equal(t, "5.3.0-patched", project.Interpolate("${lib.version}"))
+	assert.Equal(t, "com.test:app:2.0", project.Interpolate("${project.groupId}:${pom.artifactId}:${version}"))
+	assert.Equal(t, "2.0", project.Interpolate("${project.parent.version}"))
+	assert.Equal(t, "jar", project.Interpolate("${project.packaging}"))
+	assert.Equal(t, "${unknown} and ${project.name}", project.Interpolate("${unknown} and ${project.name}"))
+	assert.Equal(t, "${loop}", project.Interpolate("${loop}"))
+	assert.Equal(t, "${unterminated", project.Interpolate("${unterminated"))
+}
@@ -0,0 +1,316 @@
+package gopom
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Confidence of a LicenseMatch, depending on how the license was recognized.
+const (
+	// ConfidenceExact is given when the name is an SPDX identifier.
+	ConfidenceExact = 1.0
+	// ConfidenceName is given when the name is a known spelling of the license.
+	ConfidenceName = 0.95
+	// ConfidenceURL is given when the URL is a known location of the license text.
+	ConfidenceURL = 0.9
+	// ConfidenceGuess is given when the license is guessed from the family
+	// and version the name mentions.
+	ConfidenceGuess = 0.6
+)
+
+// LicenseMatch is the SPDX license recognized for a POM license entry.
+type LicenseMatch struct {
+	License License
+	// ID is an SPDX identifier, or an SPDX expression when the name lists
+	// several licenses such as "CDDL/GPLv2+CE". It is empty when the license
+	// is not recognized.
+	ID string
+	// Confidence ranges from 0 for unknown licenses to ConfidenceExact.
+	Confidence float64
+}
+
+// Known reports whether the license was recognized.
+func (m LicenseMatch) Known() bool {
+	return m.ID != ""
+}
+
+// NormalizedLicenses are the SPDX licenses of all license entries of a POM.
+type NormalizedLicenses struct {
+	Matches []LicenseMatch
+	// Expression combines the licenses with OR, as Maven treats the licenses
+	// of a POM as alternatives. Unknown licenses appear as LicenseRef-
+	// identifiers. It is empty when the POM declares no licenses.
+	Expression string
+	// Unknown are the entries that were not recognized.
+	Unknown []License
+}
+
+// spdxLicenses is the offline mapping of SPDX identifiers to the names and
+// URLs POMs commonly use for them.
+var spdxLicenses = []struct {
+	id    string
+	names []string
+	urls  []string
+}{
+	{"Apache-1.1", []string{"Apache License 1.1", "Apache Software License, Version 1.1"},
+		[]string{"apache.org/licenses/LICENSE-1.1"}},
+	{"Apache-2.0", []string{"Apache License, Version 2.0", "The Apache Software License, Version 2.0", "Apache 2", "ASL 2.0", "ASF 2.0", "Apache Public License 2.0", "Apache License Version 2", "AL 2.0"},
+		[]string{"apache.org/licenses/LICENSE-2.0", "apache.org/licenses/LICENSE-2.0.txt", "apache.org/licenses/LICENSE-2.0.html", "opensource.org/licenses/Apache-2.0", "repository.jboss.org/licenses/apache-2.0.txt"}},
+	{"MIT", []string{"MIT License", "The MIT License", "MIT/X11", "Expat", "Bouncy Castle Licence"},
+		[]string{"opensource.org/licenses/MIT", "opensource.org/licenses/mit-license.php", "mit-license.org", "bouncycastle.org/licence.html"}},
+	{"MIT-0", []string{"MIT No Attribution"}, nil},
+	{"BSD-2-Clause", []string{"BSD 2-Clause License", "Simplified BSD License", "FreeBSD License", "The BSD 2-Clause License"},
+		[]string{"opensource.org/licenses/BSD-2-Clause", "opensource.org/licenses/bsd-license.php"}},
+	{"BSD-3-Clause", []string{"BSD 3-Clause License", "New BSD License", "Modified BSD License", "Revised BSD License", "The BSD 3-Clause License", "Eclipse Distribution License - v 1.0", "EDL 1.0", "Go License"},
+		[]string{"opensource.org/licenses/BSD-3-Clause", "eclipse.org/org/documents/edl-v10.php", "eclipse.org/org/documents/edl-v10.html", "golang.org/LICENSE"}},
+	{"EPL-1.0", []string{"Eclipse Public License 1.0", "Eclipse Public License - v 1.0", "Eclipse Public License v1.0"},
+		[]string{"eclipse.org/legal/epl-v10.html", "opensource.org/licenses/EPL-1.0"}},
+	{"EPL-2.0", []string{"Eclipse Public License 2.0", "Eclipse Public License - v 2.0", "Eclipse Public License v2.0"},
+		[]string{"eclipse.org/legal/epl-2.0", "eclipse.org/legal/epl-v20.html", "opensource.org/licenses/EPL-2.0"}},
+	{"CPL-1.0", []string{"Common Public License Version 1.0", "Common Public License 1.0"},
+		[]string{"opensource.org/licenses/cpl1.0.php"}},
+	{"LGPL-2.1-only", []string{"GNU Lesser General Public License, Version 2.1", "GNU Lesser General Public License v2.1", "LGPL 2.1", "LGPLv2.1", "GNU LGPL 2.1"},
+		[]string{"gnu.org/licenses/old-licenses/lgpl-2.1.html", "gnu.org/licenses/old-licenses/lgpl-2.1.txt", "opensource.org/licenses/LGPL-2.1"}},
+	{"LGPL-2.1-or-later", []string{"GNU Lesser General Public License v2.1 or later", "LGPL 2.1 or later", "LGPLv2.1+"}, nil},
+	{"LGPL-3.0-only", []string{"GNU Lesser General Public License, Version 3", "GNU Lesser General Public License v3.0", "LGPL 3.0", "LGPLv3", "GNU LGPL 3"},
+		[]string{"gnu.org/licenses/lgpl-3.0.html", "gnu.org/licenses/lgpl-3.0.txt", "gnu.org/licenses/lgpl.html", "opensource.org/licenses/LGPL-3.0"}},
+	{"GPL-2.0-only", []string{"GNU General Public License, Version 2", "GNU General Public License v2.0", "GPL 2", "GPLv2", "GNU GPL v2"},
+		[]string{"gnu.org/licenses/old-licenses/gpl-2.0.html", "gnu.org/licenses/old-licenses/gpl-2.0.txt", "opensource.org/licenses/GPL-2.0"}},
+	{"GPL-2.0-only WITH Classpath-exception-2.0", []string{"GPL2 w/ CPE", "GPLv2 with Classpath Exception", "GNU General Public License, version 2, with the Classpath Exception", "GPLv2+CE", "GPL-2.0-with-classpath-exception"},
+		[]string{"openjdk.java.net/legal/gplv2+ce.html", "openjdk.org/legal/gplv2+ce.html"}},
+	{"GPL-2.0-or-later", []string{"GNU General Public License v2.0 or later", "GPL 2 or later", "GPLv2+"}, nil},
+	{"GPL-3.0-only", []string{"GNU General Public License, Version 3", "GNU General Public License v3.0", "GPL 3", "GPLv3", "GNU GPL v3"},
+		[]string{"gnu.org/licenses/gpl-3.0.html", "gnu.org/licenses/gpl-3.0.txt", "gnu.org/licenses/gpl.html", "opensource.org/licenses/GPL-3.0"}},
+	{"GPL-3.0-or-later", []string{"GNU General Public License v3.0 or later", "GPL 3 or later", "GPLv3+"}, nil},
+	{"AGPL-3.0-only", []string{"GNU Affero General Public License v3.0", "GNU Affero General Public License, Version 3", "AGPLv3", "AGPL 3"},
+		[]string{"gnu.org/licenses/agpl-3.0.html", "gnu.org/licenses/agpl-3.0.txt", "opensource.org/licenses/AGPL-3.0"}},
+	{"MPL-1.1", []string{"Mozilla Public License 1.1", "Mozilla Public License Version 1.1", "MPL 1.1"},
+		[]string{"mozilla.org/MPL/MPL-1.1.html", "mozilla.org/MPL/1.1", "opensource.org/licenses/MPL-1.1"}},
+	{"MPL-2.0", []string{"Mozilla Public License 2.0", "Mozilla Public License Version 2.0", "MPL 2.0"},
+		[]string{"mozilla.org/MPL/2.0", "mozilla.org/en-US/MPL/2.0", "opensource.org/licenses/MPL-2.0"}},
+	{"CDDL-1.0", []string{"Common Development and Distribution License 1.0", "Common Development and Distribution License (CDDL) v1.0", "CDDL 1.0", "CDDL"},
+		[]string{"opensource.org/licenses/CDDL-1.0", "glassfish.dev.java.net/public/CDDLv1.0.html"}},
+	{"CDDL-1.1", []string{"Common Development and Distribution License 1.1", "CDDL 1.1"}, nil},
+	{"CDDL-1.1 OR GPL-2.0-only WITH Classpath-exception-2.0", []string{"CDDL + GPLv2 with classpath exception", "CDDL+GPL License", "CDDL+GPL 1.1"},
+		[]string{"glassfish.java.net/public/CDDL+GPL_1_1.html", "oss.oracle.com/licenses/CDDL+GPL-1.1", "glassfish.dev.java.net/nonav/public/CDDL+GPL.html"}},
+	{"EPL-2.0 OR GPL-2.0-only WITH Classpath-exception-2.0", []string{"EPL 2.0 OR GPL 2.0 with classpath exception"},
+		[]string{"projects.eclipse.org/license/epl-2.0-or-gpl-2.0-with-classpath-exception"}},
+	{"CC0-1.0", []string{"Creative Commons Zero v1.0 Universal", "CC0 1.0 Universal", "CC0", "Public Domain, per Creative Commons CC0"},
+		[]string{"creativecommons.org/publicdomain/zero/1.0", "creativecommons.org/publicdomain/zero/1.0/legalcode"}},
+	{"CC-BY-4.0", []string{"Creative Commons Attribution 4.0 International"},
+		[]string{"creativecommons.org/licenses/by/4.0"}},
+	{"Unlicense", []string{"The Unlicense"}, []string{"unlicense.org"}},
+	{"ISC", []string{"ISC License"}, []string{"opensource.org/licenses/ISC"}},
+	{"Zlib", []string{"zlib License", "zlib/libpng License"}, []string{"opensource.org/licenses/Zlib"}},
+	{"BSL-1.0", []string{"Boost Software License 1.0", "Boost Software License - Version 1.0"},
+		[]string{"boost.org/LICENSE_1_0.txt", "opensource.org/licenses/BSL-1.0"}},
+	{"UPL-1.0", []string{"Universal Permissive License 1.0", "Universal Permissive License, Version 1.0"},
+		[]string{"oss.oracle.com/licenses/upl", "opensource.org/licenses/UPL"}},
+	{"ICU", []string{"ICU License", "Unicode/ICU License"}, []string{"source.icu-project.org/repos/icu/icu/trunk/license.html"}},
+	{"JSON", []string{"The JSON License"}, []string{"json.org/license.html"}},
+	{"WTFPL", []string{"Do What The F*ck You Want To Public License"}, []string{"wtfpl.net", "sam.zoy.org/wtfpl"}},
+	{"PostgreSQL", []string{"PostgreSQL License"}, []string{"postgresql.org/about/licence", "opensource.org/licenses/PostgreSQL"}},
+	{"Artistic-2.0", []string{"Artistic License 2.0"}, []string{"opensource.org/licenses/Artistic-2.0"}},
+	{"Python-2.0", []string{"Python Software Foundation License", "PSF License"}, []string{"python.org/psf/license"}},
+}
+
+// licenseFamilies guess the SPDX identifier from the license family a name
+// mentions and its version. Families are tried in order, so more specific
+// keywords come first.
+var licenseFamilies = []struct {
+	keywords []string
+	versions map[string]string
+}{
+	{[]string{"affero", "agpl"}, map[string]string{"3": "AGPL-3.0-only"}},
+	{[]string{"lesser general public", "library general public", "lgpl"}, map[string]string{"2.1": "LGPL-2.1-only", "3": "LGPL-3.0-only"}},
+	{[]string{"general public", "gpl"}, map[string]string{"2": "GPL-2.0-only", "3": "GPL-3.0-only"}},
+	{[]string{"apache"}, map[string]string{"1.1": "Apache-1.1", "2": "Apache-2.0"}},
+	{[]string{"eclipse public", "epl"}, map[string]string{"1": "EPL-1.0", "2": "EPL-2.0"}},
+	{[]string{"mozilla", "mpl"}, map[string]string{"1.1": "MPL-1.1", "2": "MPL-2.0"}},
+	{[]string{"common development and distribution", "cddl"}, map[string]string{"1": "CDDL-1.0", "1.1": "CDDL-1.1"}},
+	{[]string{"mit"}, map[string]string{"": "MIT"}},
+	{[]string{"bsd"}, map[string]string{"2": "BSD-2-Clause", "3": "BSD-3-Clause"}},
+}
+
+var (
+	licenseIDs     = map[string]string{}
+	licenseNameIDs = map[string]string{}
+	licenseURLIDs  = map[string]string{}
+)
+
+func init() {
+	for _, l := range spdxLicenses {
+		if !strings.Contains(l.id, " ") {
+			licenseIDs[strings.ToLower(l.id)] = l.id
+			licenseNameIDs[licenseNameKey(l.id)] = l.id
+		}
+		for _, name := range l.names {
+			licenseNameIDs[licenseNameKey(name)] = l.id
+		}
+		for _, url := range l.urls {
+			licenseURLIDs[licenseURLKey(url)] = l.id
+		}
+	}
+}
+
+var (
+	licenseNameNoise     = regexp.MustCompile(`\b(the|licen[cs]e[sd]?|version|software)\b`)
+	licenseNameVersionV  = regexp.MustCompile(`([a-z])v(\d)`)
+	licenseNameZero      = regexp.MustCompile(`(\d)\.0\b`)
+	licenseNameJunk      = regexp.MustCompile(`[^a-z0-9.+]+`)
+	licenseVersion       = regexp.MustCompile(`(\d+(?:\.\d+)?)`)
+	licenseWordSeparator = regexp.MustCompile(`[^a-z0-9]+`)
+	licenseSeparator     = regexp.MustCompile(`(?i)\s+or\s+|\s+and\s+|\s*/\s*|\s+&\s+`)
+	licenseConjunction   = regexp.MustCompile(`(?i)^\s*(and|&)\s*$`)
+)
+
+// licenseNameKey reduces a license name to a key that ignores case,
+// punctuation and filler words, so that "The Apache Software License,
+// Version 2.0" and "Apache License 2" share a key.
+func licenseNameKey(name string) string {
+	s := strings.ToLower(name)
+	s = licenseNameNoise.ReplaceAllString(s, " ")
+	s = licenseNameZero.ReplaceAllString(s, "$1 ")
+	s = licenseNameJunk.ReplaceAllString(s, "")
+	return licenseNameVersionV.ReplaceAllString(s, "$1$2")
+}
+
+// licenseURLKey reduces a URL to its host and path, ignoring the scheme,
+// www and a trailing slash.
+func licenseURLKey(url string) string {
+	s := strings.ToLower(strings.TrimSpace(url))
+	for _, prefix := range []string{"https://", "http://", "www."} {
+		s = strings.TrimPrefix(s, prefix)
+	}
+	return strings.TrimSuffix(s, "/")
+}
+
+// NormalizeLicense recognizes the SPDX license of a POM license entry from
+// an offline mapping of common names and URLs.
+func NormalizeLicense(l License) LicenseMatch {
+	name := strings.TrimSpace(deref(l.Name))
+	match := LicenseMatch{License: l}
+	if id, ok := licenseIDs[strings.ToLower(name)]; ok {
+		match.ID, match.Confidence = id, ConfidenceExact
+	} else if id, ok := licenseNameIDs[licenseNameKey(name)]; ok && name != "" {
+		match.ID, match.Confidence = id, ConfidenceName
+	} else if id, ok := licenseURLIDs[licenseURLKey(deref(l.URL))]; ok {
+		match.ID, match.Confidence = id, ConfidenceURL
+	} else if id, confidence := compoundLicense(name); id != "" {
+		match.ID, match.Confidence = id, confidence
+	} else if id := guessLicense(name); id != "" {
+		match.ID, match.Confidence = id, ConfidenceGuess
+	}
+	return match
+}
+
+// compoundLicense recognizes names that list several licenses, such as
+// "CDDL/GPLv2+CE" or "Apache 2.0 and MIT". Licenses separated by "or" or a
+// slash are alternatives, those separated by "and" or "&" all apply. It
+// only succeeds when every part is recognized, and as the meaning of such
+// names is a guess, its confidence is at most ConfidenceGuess.
+func compoundLicense(name string) (string, float64) {
+	separators := licenseSeparator.FindAllStringIndex(name, -1)
+	if len(separators) == 0 {
+		return "", 0
+	}
+	var expression strings.Builder
+	confidence := ConfidenceGuess
+	start := 0
+	for i := 0; i <= len(separators); i++ {
+		end := len(name)
+		if i < len(separators) {
+			end = separators[i][0]
+		}
+		part := name[start:end]
+		m := NormalizeLicense(License{Name: &part})
+		if !m.Known() {
+			return "", 0
+		}
+		expression.WriteString(licenseOperand(m.ID))
+		if m.Confidence < confidence {
+			confidence = m.Confidence
+		}
+		if i < len(separators) {
+			if licenseConjunction.MatchString(name[end:separators[i][1]]) {
+				expression.WriteString(" AND ")
+			} else {
+				expression.WriteString(" OR ")
+			}
+			start = separators[i][1]
+		}
+	}
+	return expression.String(), confidence
+}
+
+// guessLicense guesses the license from the family and version a name mentions.
+func guessLicense(name string) string {
+	s := strings.ToLower(name)
+	version := ""
+	if v := licenseVersion.FindString(s); v != "" {
+		version = strings.TrimSuffix(v, ".0")
+	}
+	words := " " + licenseWordSeparator.ReplaceAllString(s, " ") + " "
+	for _, family := range licenseFamilies {
+		for _, keyword := range family.keywords {
+			if !strings.Contains(words, " "+keyword+" ") {
+				continue
+			}
+			if id, ok := family.versions[version]; ok {
+				return id
+			}
+			return family.versions[""]
+		}
+	}
+	return ""
+}
+
+// licenseOperand parenthesizes compound expressions before combining them.
+func licenseOperand(id string) string {
+	if strings.Contains(id, " OR ") || strings.Contains(id, " AND ") {
+		return "(" + id + ")"
+	}
+	return id
+}
+
+// licenseRef returns the LicenseRef identifier of a license that has no SPDX identifier.
+func licenseRef(l License) string {
+	label := strings.TrimSpace(deref(l.Name))
+	if label == "" {
+		label = strings.TrimSpace(deref(l.URL))
+	}
+	return "LicenseRef-" + spdxIDString(label)
+}
+
+// NormalizeLicenses recognizes the SPDX licenses of all license entries of a POM.
+func NormalizeLicenses(licenses []License) NormalizedLicenses {
+	var n NormalizedLicenses
+	var operands []string
+	for _, l := range licenses {
+		m := NormalizeLicense(l)
+		n.Matches = append(n.Matches, m)
+		if m.Known() {
+			operands = append(operands, m.ID)
+		} else {
+			n.Unknown = append(n.Unknown, l)
+			operands = append(operands, licenseRef(l))
+		}
+	}
+	if len(operands) == 1 {
+		n.Expression = operands[0]
+	} else {
+		for i, o := range operands {
+			operands[i] = licenseOperand(o)
+		}
+		n.Expression = strings.Join(operands, " OR ")
+	}
+	return n
+}
+
+// NormalizedLicenses recognizes the SPDX licenses the project declares.
+func (p *Project) NormalizedLicenses() NormalizedLicenses {
+	if p.Licenses == nil {
+		return NormalizedLicenses{}
+	}
+	return NormalizeLicenses(*p.Licenses)
+}
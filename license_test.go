@@ -0,0 +1,66 @@
+package gopom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func license(name, url string) License {
+	l := License{}
+	if name != "" {
+		l.Name = &name
+	}
+	if url != "" {
+		l.URL = &url
+	}
+	return l
+}
+
+func Test_NormalizeLicense(t *testing.T) {
+	tests := []struct {
+		name, url  string
+		id         string
+		confidence float64
+	}{
+		{"Apache-2.0", "", "Apache-2.0", ConfidenceExact},
+		{"mit", "", "MIT", ConfidenceExact},
+		{"The Apache Software License, Version 2.0", "", "Apache-2.0", ConfidenceName},
+		{"Apache License, Version 2.0", "", "Apache-2.0", ConfidenceName},
+		{"Apache License V2.0", "", "Apache-2.0", ConfidenceName},
+		{"GNU Lesser General Public Licence, Version 2.1", "", "LGPL-2.1-only", ConfidenceName},
+		{"Eclipse Public License - v 2.0", "", "EPL-2.0", ConfidenceName},
+		{"", "http://www.apache.org/licenses/LICENSE-2.0.txt", "Apache-2.0", ConfidenceURL},
+		{"Proprietary-ish", "https://opensource.org/licenses/MIT/", "MIT", ConfidenceURL},
+		{"CDDL/GPLv2+CE", "", "CDDL-1.0 OR GPL-2.0-only WITH Classpath-exception-2.0", ConfidenceGuess},
+		{"EPL 1.0 or LGPL 2.1", "", "EPL-1.0 OR LGPL-2.1-only", ConfidenceGuess},
+		{"Apache-2.0 AND MIT", "", "Apache-2.0 AND MIT", ConfidenceGuess},
+		{"GPL 3 & MIT License", "", "GPL-3.0-only AND MIT", ConfidenceGuess},
+		{"MIT and Apache 2 or EPL 1.0", "", "MIT AND Apache-2.0 OR EPL-1.0", ConfidenceGuess},
+		{"Apache Software Foundation License 2", "", "Apache-2.0", ConfidenceGuess},
+		{"GNU General Public License (GPL), 3rd edition 3", "", "GPL-3.0-only", ConfidenceGuess},
+		{"BSD style", "", "", 0},
+		{"Acme Commercial License", "https://acme.org/license", "", 0},
+		{"", "", "", 0},
+	}
+	for _, test := range tests {
+		m := NormalizeLicense(license(test.name, test.url))
+		assert.Equal(t, test.id, m.ID, test.name)
+		assert.Equal(t, test.confidence, m.Confidence, test.name)
+		assert.Equal(t, test.id != "", m.Known(), test.name)
+	}
+}
+
+func Test_NormalizeLicenses(t *testing.T) {
+	n := NormalizeLicenses([]License{
+		license("CDDL + GPLv2 with classpath exception", ""),
+		license("MIT License", ""),
+		license("Acme Commercial License", ""),
+	})
+	assert.Equal(t, "(CDDL-1.1 OR GPL-2.0-only WITH Classpath-exception-2.0) OR MIT OR LicenseRef-Acme-Commercial-License", n.Expression)
+	assert.Len(t, n.Matches, 3)
+	assert.Equal(t, []License{license("Acme Commercial License", "")}, n.Unknown)
+
+	assert.Equal(t, "Apache-2.0", NormalizeLicenses([]License{license("ASL 2.0", "")}).Expression)
+	assert.Equal(t, NormalizedLicenses{}, (&Project{}).NormalizedLicenses())
+}
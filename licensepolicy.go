@@ -0,0 +1,209 @@
+package gopom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// LicensePolicy decides which licenses the dependencies of a project may have.
+type LicensePolicy struct {
+	// Allow lists the SPDX identifiers that are allowed. When it is empty,
+	// every license that is not denied is allowed.
+	Allow []string `json:"allow,omitempty"`
+	// Deny lists the SPDX identifiers that are never allowed.
+	Deny []string `json:"deny,omitempty"`
+	// Exceptions accept licenses for particular artifacts.
+	Exceptions []LicenseException `json:"exceptions,omitempty"`
+	// Scopes restricts the check to dependencies in these scopes; empty
+	// checks every scope.
+	Scopes []Scope `json:"scopes,omitempty"`
+	// MinConfidence treats licenses recognized with a lower confidence as unknown.
+	MinConfidence float64 `json:"minConfidence,omitempty"`
+	// FailOnUnknown makes dependencies with unknown licenses fail the check.
+	FailOnUnknown bool `json:"failOnUnknown,omitempty"`
+}
+
+// LicenseException accepts licenses for the artifacts matching a pattern in
+// the notation of Coordinates.Matches.
+type LicenseException struct {
+	Artifact string `json:"artifact"`
+	// Licenses are allowed for the artifact in addition to the allowed
+	// licenses. When empty, the artifact is accepted whatever its license.
+	Licenses []string `json:"licenses,omitempty"`
+	Reason   string   `json:"reason,omitempty"`
+}
+
+// LicenseFinding is a dependency whose licenses the policy rejects or cannot decide on.
+type LicenseFinding struct {
+	Coordinates Coordinates `json:"coordinates"`
+	// Licenses is the license expression of the dependency, empty when it declares none.
+	Licenses string `json:"licenses,omitempty"`
+	Reason   string `json:"reason"`
+	// Path leads from the project to the dependency.
+	Path []Coordinates `json:"path"`
+}
+
+func (f LicenseFinding) String() string {
+	path := make([]string, len(f.Path))
+	for i, c := range f.Path {
+		path[i] = c.String()
+	}
+	return fmt.Sprintf("%s: %s (%s)", f.Coordinates, f.Reason, strings.Join(path, " > "))
+}
+
+// LicenseReport is the result of checking a project against a LicensePolicy.
+type LicenseReport struct {
+	Violations []LicenseFinding `json:"violations,omitempty"`
+	Unknown    []LicenseFinding `json:"unknown,omitempty"`
+	// Failed is set when there are violations, or unknown licenses and the
+	// policy fails on them.
+	Failed bool `json:"failed"`
+}
+
+// LoadLicensePolicy reads a policy in JSON.
+func LoadLicensePolicy(r io.Reader) (*LicensePolicy, error) {
+	var p LicensePolicy
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// Check evaluates the licenses of the dependencies in the resolved graph.
+// The licenses of each dependency are read from its effective pom through
+// the loader, so licenses declared by parents apply. A dependency passes when
+// one of its licenses is allowed, as Maven treats them as alternatives.
+func (p *LicensePolicy) Check(graph *DependencyNode, loader PomLoader) (*LicenseReport, error) {
+	if graph == nil {
+		return nil, fmt.Errorf("no dependency graph")
+	}
+	scopes := map[Scope]bool{}
+	for _, s := range p.Scopes {
+		scopes[s] = true
+	}
+
+	report := &LicenseReport{}
+	seen := map[string]bool{}
+	for _, d := range graph.Flatten() {
+		if len(scopes) > 0 && !scopes[d.Scope] || seen[d.Coordinates.String()] {
+			continue
+		}
+		seen[d.Coordinates.String()] = true
+
+		c := sbomComponent{coordinates: d.Coordinates}
+		if err := c.load(SBOMOptions{Loader: loader}); err != nil {
+			return nil, err
+		}
+		licenses := NormalizeLicenses(c.licenses)
+		finding := LicenseFinding{Coordinates: d.Coordinates, Licenses: licenses.Expression, Path: d.Path}
+		verdict, reason := p.evaluate(d.Coordinates, licenses)
+		finding.Reason = reason
+		switch verdict {
+		case licenseDenied:
+			report.Violations = append(report.Violations, finding)
+		case licenseUnknown:
+			report.Unknown = append(report.Unknown, finding)
+		}
+	}
+	report.Failed = len(report.Violations) > 0 || p.FailOnUnknown && len(report.Unknown) > 0
+	return report, nil
+}
+
+type licenseVerdict int
+
+const (
+	licenseAllowed licenseVerdict = iota
+	licenseUnknown
+	licenseDenied
+)
+
+// evaluate decides on the licenses of an artifact and explains the verdict.
+func (p *LicensePolicy) evaluate(c Coordinates, licenses NormalizedLicenses) (licenseVerdict, string) {
+	allow := map[string]bool{}
+	for _, id := range p.Allow {
+		allow[id] = true
+	}
+	for _, e := range p.Exceptions {
+		if !c.Matches(e.Artifact) {
+			continue
+		}
+		if len(e.Licenses) == 0 {
+			return licenseAllowed, ""
+		}
+		for _, id := range e.Licenses {
+			allow[id] = true
+		}
+	}
+	deny := map[string]bool{}
+	for _, id := range p.Deny {
+		deny[id] = true
+	}
+
+	if len(licenses.Matches) == 0 {
+		return licenseUnknown, "no license declared"
+	}
+	var unknown, rejected []string
+	for _, m := range licenses.Matches {
+		if !m.Known() || m.Confidence < p.MinConfidence {
+			unknown = append(unknown, licenseLabel(m.License))
+			continue
+		}
+		for _, alternative := range licenseAlternatives(m.ID) {
+			var failed []string
+			for _, term := range alternative {
+				base := strings.SplitN(term, " WITH ", 2)[0]
+				if deny[term] || deny[base] {
+					failed = append(failed, term+" is denied")
+				} else if len(allow) > 0 && !allow[term] && !allow[base] {
+					failed = append(failed, term+" is not allowed")
+				}
+			}
+			if len(failed) == 0 {
+				return licenseAllowed, ""
+			}
+			rejected = append(rejected, failed...)
+		}
+	}
+	if len(unknown) > 0 {
+		return licenseUnknown, "unknown license " + strings.Join(unknown, ", ")
+	}
+	sort.Strings(rejected)
+	return licenseDenied, strings.Join(rejected, ", ")
+}
+
+// licenseAlternatives splits an SPDX expression into alternatives, each of
+// which is a list of licenses that all apply.
+func licenseAlternatives(expression string) [][]string {
+	expression = strings.NewReplacer("(", "", ")", "").Replace(expression)
+	var alternatives [][]string
+	for _, alternative := range strings.Split(expression, " OR ") {
+		alternatives = append(alternatives, strings.Split(alternative, " AND "))
+	}
+	return alternatives
+}
+
+// licenseLabel returns the name of the license, or its URL when it has no name.
+func licenseLabel(l License) string {
+	if name := strings.TrimSpace(deref(l.Name)); name != "" {
+		return fmt.Sprintf("%q", name)
+	}
+	return fmt.Sprintf("%q", strings.TrimSpace(deref(l.URL)))
+}
+
+// WriteText writes the findings one per line, violations first, for use in CI logs.
+func (r *LicenseReport) WriteText(w io.Writer) error {
+	var b strings.Builder
+	for _, f := range r.Violations {
+		fmt.Fprintf(&b, "violation %s\n", f)
+	}
+	for _, f := range r.Unknown {
+		fmt.Fprintf(&b, "unknown %s\n", f)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,126 @@
+package gopom
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func licensePolicyGraph(t *testing.T) (*DependencyNode, PomLoader) {
+	poms := map[string]string{
+		"org/acme/gpl/1/gpl-1.pom": `<project>
+  <groupId>org.acme</groupId><artifactId>gpl</artifactId><version>1</version>
+  <licenses><license><name>GNU General Public License v3.0</name></license></licenses>
+</project>`,
+		"org/acme/dual/1/dual-1.pom": `<project>
+  <groupId>org.acme</groupId><artifactId>dual</artifactId><version>1</version>
+  <licenses><license><name>GPLv3</name></license><license><name>MIT License</name></license></licenses>
+  <dependencies><dependency><groupId>org.acme</groupId><artifactId>gpl</artifactId><version>1</version></dependency></dependencies>
+</project>`,
+		"org/acme/custom/1/custom-1.pom": `<project>
+  <groupId>org.acme</groupId><artifactId>custom</artifactId><version>1</version>
+  <licenses><license><name>Acme Commercial License</name></license></licenses>
+</project>`,
+	}
+	for path, pom := range sbomPoms {
+		poms[path] = pom
+	}
+	repo := LocalRepository(writeRepository(t, poms))
+	p, err := ParseFromReader(strings.NewReader(`<project>
+  <groupId>org.acme</groupId><artifactId>app</artifactId><version>1</version>
+  <dependencies>
+    <dependency><groupId>org.acme</groupId><artifactId>core</artifactId><version>1.0</version></dependency>
+    <dependency><groupId>org.acme</groupId><artifactId>dual</artifactId><version>1</version></dependency>
+    <dependency><groupId>org.acme</groupId><artifactId>custom</artifactId><version>1</version><scope>test</scope></dependency>
+  </dependencies>
+</project>`))
+	assert.Nil(t, err)
+	graph, err := RepositoryResolver{Loader: repo}.Resolve(p)
+	assert.Nil(t, err)
+	return graph, repo
+}
+
+func Test_LicensePolicyCheck(t *testing.T) {
+	graph, repo := licensePolicyGraph(t)
+	policy := &LicensePolicy{Deny: []string{"GPL-3.0-only"}}
+	report, err := policy.Check(graph, repo)
+	assert.Nil(t, err)
+
+	assert.Equal(t, []LicenseFinding{{
+		Coordinates: Coordinates{GroupID: "org.acme", ArtifactID: "gpl", Version: "1"},
+		Licenses:    "GPL-3.0-only",
+		Reason:      "GPL-3.0-only is denied",
+		Path: []Coordinates{
+			{GroupID: "org.acme", ArtifactID: "app", Version: "1"},
+			{GroupID: "org.acme", ArtifactID: "dual", Version: "1"},
+			{GroupID: "org.acme", ArtifactID: "gpl", Version: "1"},
+		},
+	}}, report.Violations)
+
+	var unknown []string
+	for _, f := range report.Unknown {
+		unknown = append(unknown, f.Coordinates.String()+": "+f.Reason)
+	}
+	assert.Equal(t, []string{
+		"org.acme:util:2: no license declared",
+		"org.acme:custom:1: unknown license \"Acme Commercial License\"",
+	}, unknown)
+	assert.True(t, report.Failed)
+
+	var out bytes.Buffer
+	assert.Nil(t, report.WriteText(&out))
+	assert.Equal(t, `violation org.acme:gpl:1: GPL-3.0-only is denied (org.acme:app:1 > org.acme:dual:1 > org.acme:gpl:1)
+unknown org.acme:util:2: no license declared (org.acme:app:1 > org.acme:core:1.0 > org.acme:util:2)
+unknown org.acme:custom:1: unknown license "Acme Commercial License" (org.acme:app:1 > org.acme:custom:1)
+`, out.String())
+}
+
+func Test_LicensePolicyAllowAndExceptions(t *testing.T) {
+	graph, repo := licensePolicyGraph(t)
+	policy, err := LoadLicensePolicy(strings.NewReader(`{
+  "allow": ["Apache-2.0", "MIT"],
+  "scopes": ["compile", "runtime"],
+  "exceptions": [
+    {"artifact": "org.acme:gpl", "licenses": ["GPL-3.0-only"], "reason": "used by the build only"},
+    {"artifact": "org.acme:util"}
+  ],
+  "failOnUnknown": true
+}`))
+	assert.Nil(t, err)
+	report, err := policy.Check(graph, repo)
+	assert.Nil(t, err)
+	assert.Empty(t, report.Violations)
+	assert.Empty(t, report.Unknown)
+	assert.False(t, report.Failed)
+
+	policy.Exceptions = nil
+	report, err = policy.Check(graph, repo)
+	assert.Nil(t, err)
+	assert.Len(t, report.Violations, 1)
+	assert.Equal(t, "GPL-3.0-only is not allowed", report.Violations[0].Reason)
+	assert.Len(t, report.Unknown, 1)
+
+	policy.MinConfidence = 0.99
+	report, err = policy.Check(graph, repo)
+	assert.Nil(t, err)
+	assert.Len(t, report.Unknown, 4)
+}
+
+func Test_LoadLicensePolicyUnknownField(t *testing.T) {
+	_, err := LoadLicensePolicy(strings.NewReader(`{"alow": ["MIT"]}`))
+	assert.EqualError(t, err, `json: unknown field "alow"`)
+}
+
+func Test_LicensePolicyConjunctiveLicenses(t *testing.T) {
+	policy := &LicensePolicy{Allow: []string{"MIT"}}
+	c := Coordinates{GroupID: "org.acme", ArtifactID: "lib", Version: "1"}
+
+	verdict, reason := policy.evaluate(c, NormalizeLicenses([]License{license("GPL 3 and MIT", "")}))
+	assert.Equal(t, licenseDenied, verdict)
+	assert.Equal(t, "GPL-3.0-only is not allowed", reason)
+
+	verdict, _ = policy.evaluate(c, NormalizeLicenses([]License{license("GPL 3 or MIT", "")}))
+	assert.Equal(t, licenseAllowed, verdict)
+}
@@ -0,0 +1,522 @@
+package gopom
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// LintSeverity is how serious a lint finding is.
+type LintSeverity string
+
+const (
+	// LintOff disables a rule.
+	LintOff     LintSeverity = "off"
+	LintNote    LintSeverity = "note"
+	LintWarning LintSeverity = "warning"
+	LintError   LintSeverity = "error"
+)
+
+func (s LintSeverity) valid() bool {
+	switch s {
+	case LintOff, LintNote, LintWarning, LintError:
+		return true
+	}
+	return false
+}
+
+// The lint rules.
+const (
+	RuleHardcodedVersion     = "hardcoded-version"
+	RuleUnusedProperty       = "unused-property"
+	RuleDuplicateDependency  = "duplicate-dependency"
+	RuleUnpinnedPlugin       = "unpinned-plugin"
+	RuleSnapshotDependency   = "snapshot-dependency"
+	RuleInsecureRepository   = "insecure-repository"
+	RuleDeprecatedExpression = "deprecated-expression"
+	RuleCentralMetadata      = "central-metadata"
+)
+
+// LintRule is a check the linter runs on a pom.
+type LintRule struct {
+	ID          string
+	Description string
+	// Severity is the severity of the findings unless configured otherwise.
+	Severity LintSeverity
+	check    func(l *linter)
+}
+
+var lintRules = []LintRule{
+	{RuleHardcodedVersion, "Dependencies of child modules should get their versions from dependencyManagement.", LintWarning, lintHardcodedVersions},
+	{RuleUnusedProperty, "Properties should be referenced by the pom that declares them or its parents.", LintWarning, lintUnusedProperties},
+	{RuleDuplicateDependency, "A dependency should be declared once.", LintError, lintDuplicateDependencies},
+	{RuleUnpinnedPlugin, "Build plugins should have a version.", LintWarning, lintUnpinnedPlugins},
+	{RuleSnapshotDependency, "Releases should not depend on SNAPSHOT versions.", LintError, lintSnapshotDependencies},
+	{RuleInsecureRepository, "Repositories should be accessed over https.", LintError, lintInsecureRepositories},
+	{RuleDeprecatedExpression, "${pom.*} expressions are deprecated in favor of ${project.*}.", LintWarning, lintDeprecatedExpressions},
+	{RuleCentralMetadata, "Maven Central requires a name, description, url, licenses, developers and scm.", LintNote, lintCentralMetadata},
+}
+
+// LintRules returns every rule with its default severity.
+func LintRules() []LintRule {
+	return append([]LintRule(nil), lintRules...)
+}
+
+// LintConfig configures the linter.
+type LintConfig struct {
+	// Rules sets the severity of rules by ID, LintOff disabling them. Rules
+	// that are not listed keep their default severity.
+	Rules map[string]LintSeverity `json:"rules,omitempty"`
+}
+
+// LoadLintConfig reads a configuration in JSON, e.g.
+// {"rules": {"unused-property": "off", "central-metadata": "error"}}.
+func LoadLintConfig(r io.Reader) (*LintConfig, error) {
+	var c LintConfig
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&c); err != nil {
+		return nil, err
+	}
+	known := map[string]bool{}
+	for _, rule := range lintRules {
+		known[rule.ID] = true
+	}
+	for id, severity := range c.Rules {
+		if !known[id] {
+			return nil, fmt.Errorf("unknown lint rule %q", id)
+		}
+		if !severity.valid() {
+			return nil, fmt.Errorf("invalid severity %q for lint rule %s", severity, id)
+		}
+	}
+	return &c, nil
+}
+
+// LintFinding is a problem a rule found in a pom.
+type LintFinding struct {
+	Rule     string       `json:"rule"`
+	Severity LintSeverity `json:"severity"`
+	Message  string       `json:"message"`
+	// Path is the model path of the offending element, e.g.
+	// "project.dependencies.dependency[1].version".
+	Path     string         `json:"path"`
+	Location *InputLocation `json:"location,omitempty"`
+}
+
+func (f LintFinding) String() string {
+	if f.Location != nil {
+		return fmt.Sprintf("%s: %s: %s (%s)", f.Location, f.Severity, f.Message, f.Rule)
+	}
+	return fmt.Sprintf("%s: %s: %s (%s)", f.Path, f.Severity, f.Message, f.Rule)
+}
+
+// LintFindings are the findings of linting one or more poms.
+type LintFindings []LintFinding
+
+// Failed reports whether any finding has severity LintError.
+func (f LintFindings) Failed() bool {
+	for _, finding := range f {
+		if finding.Severity == LintError {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteText writes the findings one per line.
+func (f LintFindings) WriteText(w io.Writer) error {
+	var b strings.Builder
+	for _, finding := range f {
+		fmt.Fprintln(&b, finding)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Lint runs the enabled rules on the project and returns their findings in
+// document order when it was parsed WithLocations. Inherited values are taken
+// into account through the loader, which loads the parents; without one,
+// versions managed by a parent are not seen. A nil config runs every rule
+// with its default severity.
+func (c *LintConfig) Lint(p *Project, loader PomLoader) (LintFindings, error) {
+	if loader == nil {
+		loader = emptyParentLoader{}
+	}
+	effective, err := EffectiveProject(p, loader)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &linter{project: p, effective: effective}
+	for parent := p.Parent; parent != nil; parent = l.parents[len(l.parents)-1].Parent {
+		pom, err := loader.Load(parent.Coordinates())
+		if err != nil {
+			return nil, err
+		}
+		l.parents = append(l.parents, pom)
+	}
+	for _, rule := range lintRules {
+		l.rule, l.severity = rule.ID, rule.Severity
+		if c != nil {
+			if severity, ok := c.Rules[rule.ID]; ok {
+				l.severity = severity
+			}
+		}
+		if l.severity != LintOff {
+			rule.check(l)
+		}
+	}
+	sort.SliceStable(l.findings, func(i, j int) bool {
+		a, b := l.findings[i].Location, l.findings[j].Location
+		if a == nil || b == nil {
+			return a != nil
+		}
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return l.findings, nil
+}
+
+// emptyParentLoader stands in for the parents when no loader is given.
+type emptyParentLoader struct{}
+
+func (emptyParentLoader) Load(c Coordinates) (*Project, error) {
+	return &Project{GroupID: &c.GroupID, ArtifactID: &c.ArtifactID, Version: &c.Version}, nil
+}
+
+type linter struct {
+	project, effective *Project
+	// parents is the chain of parents as loaded, closest first.
+	parents  []*Project
+	rule     string
+	severity LintSeverity
+	findings LintFindings
+}
+
+func (l *linter) report(path string, format string, args ...interface{}) {
+	f := LintFinding{Rule: l.rule, Severity: l.severity, Message: fmt.Sprintf(format, args...), Path: path}
+	if loc, ok := l.project.Locations.Lookup(path); ok {
+		f.Location = &loc
+	}
+	l.findings = append(l.findings, f)
+}
+
+// dependencies calls fn with the path of every dependency and managed
+// dependency of the project, including those of its profiles.
+func (l *linter) dependencies(fn func(path string, d Dependency)) {
+	each := func(path string, dependencies *[]Dependency) {
+		if dependencies == nil {
+			return
+		}
+		for i, d := range *dependencies {
+			fn(fmt.Sprintf("%s.dependency[%d]", path, i), d)
+		}
+	}
+	each("project.dependencies", l.project.Dependencies)
+	if l.project.DependencyManagement != nil {
+		each("project.dependencyManagement.dependencies", l.project.DependencyManagement.Dependencies)
+	}
+	if l.project.Profiles != nil {
+		for i, profile := range *l.project.Profiles {
+			path := fmt.Sprintf("project.profiles.profile[%d]", i)
+			each(path+".dependencies", profile.Dependencies)
+			if profile.DependencyManagement != nil {
+				each(path+".dependencyManagement.dependencies", profile.DependencyManagement.Dependencies)
+			}
+		}
+	}
+}
+
+// key returns the management key of the dependency with properties interpolated.
+func (l *linter) key(d Dependency) string {
+	c := d.Coordinates()
+	c.GroupID = l.project.Interpolate(c.GroupID)
+	c.ArtifactID = l.project.Interpolate(c.ArtifactID)
+	return c.ManagementKey()
+}
+
+func lintHardcodedVersions(l *linter) {
+	if l.project.Parent == nil || l.project.Dependencies == nil {
+		return
+	}
+	for i, d := range *l.project.Dependencies {
+		version := strings.TrimSpace(deref(d.Version))
+		if version == "" || strings.Contains(version, "${") {
+			continue
+		}
+		l.report(fmt.Sprintf("project.dependencies.dependency[%d].version", i),
+			"dependency %s:%s has the hard-coded version %s, manage it in the dependencyManagement of the parent",
+			deref(d.GroupID), deref(d.ArtifactID), version)
+	}
+}
+
+// implicitProperties are prefixes of properties that plugins read without
+// the pom referencing them.
+var implicitProperties = []string{"maven.", "project.", "sonar.", "surefire.", "failsafe.", "gpg.", "argLine", "skipTests"}
+
+func lintUnusedProperties(l *linter) {
+	p := l.project
+	// Modules may reference the properties of an aggregator or parent pom.
+	if p.Properties == nil || derefOr(p.Packaging, string(PackagingJar)) == string(PackagingPom) {
+		return
+	}
+	// Properties the parents declare or reference are overridden, not unused.
+	used := map[string]bool{}
+	for _, parent := range l.parents {
+		if parent.Properties != nil {
+			for name := range parent.Properties.Entries {
+				used[name] = true
+			}
+		}
+		referencedProperties(parent, used)
+	}
+	referencedProperties(p, used)
+
+	names := make([]string, 0, len(p.Properties.Entries))
+	for name := range p.Properties.Entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		implicit := false
+		for _, prefix := range implicitProperties {
+			implicit = implicit || strings.HasPrefix(name, prefix)
+		}
+		if !used[name] && !implicit {
+			l.report("project.properties."+name, "property %s is never used", name)
+		}
+	}
+}
+
+// referencedProperties adds the properties p references in ${...} or @{...}
+// expressions to used, leaving out properties that only reference themselves.
+func referencedProperties(p *Project, used map[string]bool) {
+	walkStrings(reflect.ValueOf(p), "project", func(path, value string) {
+		for _, open := range []string{"${", "@{"} {
+			for s := value; ; {
+				start := strings.Index(s, open)
+				if start < 0 {
+					break
+				}
+				end := strings.IndexByte(s[start:], '}')
+				if end < 0 {
+					break
+				}
+				if name := s[start+2 : start+end]; path != "project.properties."+name {
+					used[name] = true
+				}
+				s = s[start+end+1:]
+			}
+		}
+	})
+}
+
+func lintDuplicateDependencies(l *linter) {
+	first := map[string]string{}
+	l.dependencies(func(path string, d Dependency) {
+		// Keys are scoped by the list the dependency is in.
+		list := path[:strings.LastIndex(path, ".")]
+		key := list + " " + l.key(d)
+		if previous, ok := first[key]; ok {
+			l.report(path, "dependency %s is already declared at %s", l.key(d), previous)
+			return
+		}
+		first[key] = path
+	})
+}
+
+func lintUnpinnedPlugins(l *linter) {
+	build := l.project.Build
+	if build == nil {
+		return
+	}
+	versions := map[ArtifactKey]string{}
+	if l.effective.Build != nil && l.effective.Build.Plugins != nil {
+		for _, plugin := range *l.effective.Build.Plugins {
+			versions[plugin.Coordinates().Key()] = deref(plugin.Version)
+		}
+	}
+	if build.Plugins != nil {
+		for i, plugin := range *build.Plugins {
+			if isEmpty(plugin.Version) && strings.TrimSpace(versions[plugin.Coordinates().Key()]) == "" {
+				l.report(fmt.Sprintf("project.build.plugins.plugin[%d]", i), "plugin %s has no version", plugin.Coordinates().Key())
+			}
+		}
+	}
+	if build.PluginManagement != nil && build.PluginManagement.Plugins != nil {
+		for i, plugin := range *build.PluginManagement.Plugins {
+			if isEmpty(plugin.Version) {
+				l.report(fmt.Sprintf("project.build.pluginManagement.plugins.plugin[%d]", i), "managed plugin %s has no version", plugin.Coordinates().Key())
+			}
+		}
+	}
+}
+
+func lintSnapshotDependencies(l *linter) {
+	version := l.effective.Coordinates().Version
+	if version == "" || strings.HasSuffix(version, "-SNAPSHOT") {
+		return
+	}
+	snapshot := func(path, kind string, c Coordinates) {
+		c.GroupID = l.project.Interpolate(c.GroupID)
+		c.ArtifactID = l.project.Interpolate(c.ArtifactID)
+		c.Version = l.project.Interpolate(c.Version)
+		if strings.HasSuffix(c.Version, "SNAPSHOT") {
+			l.report(path, "release %s depends on the %s %s:%s:%s", version, kind, c.GroupID, c.ArtifactID, c.Version)
+		}
+	}
+	if l.project.Parent != nil {
+		snapshot("project.parent.version", "parent", l.project.Parent.Coordinates())
+	}
+	l.dependencies(func(path string, d Dependency) {
+		snapshot(path+".version", "dependency", d.Coordinates())
+	})
+	if l.project.Build != nil && l.project.Build.Plugins != nil {
+		for i, plugin := range *l.project.Build.Plugins {
+			snapshot(fmt.Sprintf("project.build.plugins.plugin[%d].version", i), "plugin", plugin.Coordinates())
+		}
+	}
+}
+
+func lintInsecureRepositories(l *linter) {
+	check := func(path string, url *string) {
+		u := strings.TrimSpace(l.project.Interpolate(deref(url)))
+		if strings.HasPrefix(strings.ToLower(u), "http://") {
+			l.report(path+".url", "repository %s is accessed over http, use https", u)
+		}
+	}
+	repositories := func(path string, repositories *[]Repository, pluginRepositories *[]PluginRepository, dm *DistributionManagement) {
+		if repositories != nil {
+			for i, r := range *repositories {
+				check(fmt.Sprintf("%s.repositories.repository[%d]", path, i), r.URL)
+			}
+		}
+		if pluginRepositories != nil {
+			for i, r := range *pluginRepositories {
+				check(fmt.Sprintf("%s.pluginRepositories.pluginRepository[%d]", path, i), r.URL)
+			}
+		}
+		if dm != nil && dm.Repository != nil {
+			check(path+".distributionManagement.repository", dm.Repository.URL)
+		}
+		if dm != nil && dm.SnapshotRepository != nil {
+			check(path+".distributionManagement.snapshotRepository", dm.SnapshotRepository.URL)
+		}
+	}
+	p := l.project
+	repositories("project", p.Repositories, p.PluginRepositories, p.DistributionManagement)
+	if p.Profiles != nil {
+		for i, profile := range *p.Profiles {
+			repositories(fmt.Sprintf("project.profiles.profile[%d]", i), profile.Repositories, profile.PluginRepositories, profile.DistributionManagement)
+		}
+	}
+}
+
+func lintDeprecatedExpressions(l *linter) {
+	walkStrings(reflect.ValueOf(l.project), "project", func(path, value string) {
+		for s := value; ; {
+			start := strings.Index(s, "${pom.")
+			if start < 0 {
+				return
+			}
+			end := strings.IndexByte(s[start:], '}')
+			if end < 0 {
+				return
+			}
+			expr := s[start : start+end+1]
+			l.report(path, "%s is deprecated, use ${project.%s}", expr, expr[len("${pom."):len(expr)-1])
+			s = s[start+end+1:]
+		}
+	})
+}
+
+func lintCentralMetadata(l *linter) {
+	p := l.effective
+	missing := func(path, element string) {
+		l.report(path, "%s is required by Maven Central", element)
+	}
+	if isEmpty(p.Name) {
+		missing("project.name", "name")
+	}
+	if isEmpty(p.Description) {
+		missing("project.description", "description")
+	}
+	if isEmpty(p.URL) {
+		missing("project.url", "url")
+	}
+	if p.Licenses == nil || len(*p.Licenses) == 0 {
+		missing("project.licenses", "licenses")
+	}
+	if p.Developers == nil || len(*p.Developers) == 0 {
+		missing("project.developers", "developers")
+	}
+	switch {
+	case p.SCM == nil:
+		missing("project.scm", "scm")
+	case isEmpty(p.SCM.URL):
+		missing("project.scm.url", "scm url")
+	case isEmpty(p.SCM.Connection):
+		missing("project.scm.connection", "scm connection")
+	}
+}
+
+// walkStrings calls fn with the model path and value of every element text
+// in v, including property and configuration entries.
+func walkStrings(v reflect.Value, path string, fn func(path, value string)) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		if props, ok := v.Interface().(*Properties); ok {
+			keys := make([]string, 0, len(props.Entries))
+			for k := range props.Entries {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				value := props.Entries[k]
+				if raw, ok := props.RawXML[k]; ok {
+					value = raw
+				}
+				fn(path+"."+k, value)
+			}
+			return
+		}
+		walkStrings(v.Elem(), path, fn)
+	case reflect.String:
+		fn(path, v.String())
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("xml")
+			if f.Anonymous && tag == "" {
+				walkStrings(v.Field(i), path, fn)
+				continue
+			}
+			opts := strings.Split(tag, ",")
+			if opts[0] == "" || opts[0] == "-" || !isElementTag(opts[1:]) {
+				continue
+			}
+			fieldPath := path + "." + strings.Replace(opts[0], ">", ".", -1)
+			field := v.Field(i)
+			if field.Kind() == reflect.Ptr && !field.IsNil() && field.Elem().Kind() == reflect.Slice {
+				field = field.Elem()
+			}
+			if field.Kind() == reflect.Slice {
+				for j := 0; j < field.Len(); j++ {
+					walkStrings(field.Index(j), fmt.Sprintf("%s[%d]", fieldPath, j), fn)
+				}
+				continue
+			}
+			walkStrings(field, fieldPath, fn)
+		}
+	}
+}
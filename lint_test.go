@@ -0,0 +1,238 @@
+package gopom
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const lintPom = `<project>
+  <parent>
+    <groupId>org.acme</groupId>
+    <artifactId>parent</artifactId>
+    <version>2.0-SNAPSHOT</version>
+  </parent>
+  <artifactId>app</artifactId>
+  <version>1.0</version>
+  <name>${pom.artifactId}</name>
+  <properties>
+    <guava.version>32.1.3-jre</guava.version>
+    <unused>x</unused>
+    <maven.compiler.release>11</maven.compiler.release>
+  </properties>
+  <dependencies>
+    <dependency>
+      <groupId>com.google.guava</groupId>
+      <artifactId>guava</artifactId>
+      <version>${guava.version}</version>
+    </dependency>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+      <version>4.13.2</version>
+    </dependency>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+    </dependency>
+  </dependencies>
+  <repositories>
+    <repository>
+      <id>acme</id>
+      <url>http://repo.acme.org/maven2</url>
+    </repository>
+  </repositories>
+  <build>
+    <plugins>
+      <plugin>
+        <artifactId>maven-compiler-plugin</artifactId>
+      </plugin>
+      <plugin>
+        <artifactId>maven-surefire-plugin</artifactId>
+      </plugin>
+    </plugins>
+  </build>
+</project>
+`
+
+const lintParentPom = `<project>
+  <groupId>org.acme</groupId><artifactId>parent</artifactId><version>2.0-SNAPSHOT</version><packaging>pom</packaging>
+  <description>Acme</description>
+  <build><pluginManagement><plugins>
+    <plugin><artifactId>maven-surefire-plugin</artifactId><version>3.2.2</version></plugin>
+  </plugins></pluginManagement></build>
+</project>`
+
+func parseLintPom(t *testing.T) *Project {
+	p, err := ParseFromReader(strings.NewReader(lintPom), WithSource("pom.xml"), WithLocations())
+	assert.Nil(t, err)
+	return p
+}
+
+func lintText(t *testing.T, findings LintFindings) string {
+	var b bytes.Buffer
+	assert.Nil(t, findings.WriteText(&b))
+	return b.String()
+}
+
+func Test_Lint(t *testing.T) {
+	findings, err := (*LintConfig)(nil).Lint(parseLintPom(t), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, `pom.xml:1:1: note: description is required by Maven Central (central-metadata)
+pom.xml:1:1: note: url is required by Maven Central (central-metadata)
+pom.xml:1:1: note: licenses is required by Maven Central (central-metadata)
+pom.xml:1:1: note: developers is required by Maven Central (central-metadata)
+pom.xml:1:1: note: scm is required by Maven Central (central-metadata)
+pom.xml:5:5: error: release 1.0 depends on the parent org.acme:parent:2.0-SNAPSHOT (snapshot-dependency)
+pom.xml:9:3: warning: ${pom.artifactId} is deprecated, use ${project.artifactId} (deprecated-expression)
+pom.xml:12:5: warning: property unused is never used (unused-property)
+pom.xml:24:7: warning: dependency junit:junit has the hard-coded version 4.13.2, manage it in the dependencyManagement of the parent (hardcoded-version)
+pom.xml:26:5: error: dependency junit:junit:jar is already declared at project.dependencies.dependency[1] (duplicate-dependency)
+pom.xml:34:7: error: repository http://repo.acme.org/maven2 is accessed over http, use https (insecure-repository)
+pom.xml:39:7: warning: plugin org.apache.maven.plugins:maven-compiler-plugin has no version (unpinned-plugin)
+pom.xml:42:7: warning: plugin org.apache.maven.plugins:maven-surefire-plugin has no version (unpinned-plugin)
+`, lintText(t, findings))
+	assert.True(t, findings.Failed())
+}
+
+func Test_LintWithParent(t *testing.T) {
+	repo := LocalRepository(writeRepository(t, map[string]string{
+		"org/acme/parent/2.0-SNAPSHOT/parent-2.0-SNAPSHOT.pom": lintParentPom,
+	}))
+	config := &LintConfig{Rules: map[string]LintSeverity{
+		RuleCentralMetadata:     LintOff,
+		RuleDuplicateDependency: LintOff,
+		RuleSnapshotDependency:  LintWarning,
+	}}
+	findings, err := config.Lint(parseLintPom(t), repo)
+	assert.Nil(t, err)
+
+	var unpinned []string
+	for _, f := range findings {
+		assert.NotEqual(t, RuleCentralMetadata, f.Rule)
+		assert.NotEqual(t, RuleDuplicateDependency, f.Rule)
+		switch f.Rule {
+		case RuleUnpinnedPlugin:
+			unpinned = append(unpinned, f.Message)
+		case RuleSnapshotDependency:
+			assert.Equal(t, LintWarning, f.Severity)
+		}
+	}
+	assert.Equal(t, []string{"plugin org.apache.maven.plugins:maven-compiler-plugin has no version"}, unpinned)
+	assert.True(t, findings.Failed())
+}
+
+func Test_LintOverriddenParentProperties(t *testing.T) {
+	repo := LocalRepository(writeRepository(t, map[string]string{
+		"org/acme/base/1/base-1.pom": `<project>
+  <groupId>org.acme</groupId><artifactId>base</artifactId><version>1</version><packaging>pom</packaging>
+  <properties><java.release>11</java.release></properties>
+</project>`,
+		"org/acme/parent/1/parent-1.pom": `<project>
+  <parent><groupId>org.acme</groupId><artifactId>base</artifactId><version>1</version></parent>
+  <artifactId>parent</artifactId><packaging>pom</packaging>
+  <dependencyManagement><dependencies>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><version>${junit.version}</version></dependency>
+  </dependencies></dependencyManagement>
+</project>`,
+	}))
+	p, err := ParseFromReader(strings.NewReader(`<project>
+  <parent><groupId>org.acme</groupId><artifactId>parent</artifactId><version>1</version></parent>
+  <artifactId>app</artifactId>
+  <properties><junit.version>4.12</junit.version><java.release>17</java.release><unused>x</unused></properties>
+</project>`))
+	assert.Nil(t, err)
+	config := &LintConfig{Rules: map[string]LintSeverity{RuleCentralMetadata: LintOff}}
+	findings, err := config.Lint(p, repo)
+	assert.Nil(t, err)
+	assert.Equal(t, "project.properties.unused: warning: property unused is never used (unused-property)\n", lintText(t, findings))
+}
+
+func Test_LintCleanPom(t *testing.T) {
+	p, err := ParseFromReader(strings.NewReader(`<project>
+  <groupId>org.acme</groupId><artifactId>lib</artifactId><version>1.0-SNAPSHOT</version><packaging>pom</packaging>
+  <name>lib</name><description>A library</description><url>https://acme.org</url>
+  <licenses><license><name>MIT</name></license></licenses>
+  <developers><developer><name>Jane</name></developer></developers>
+  <scm><url>https://github.com/acme/lib</url><connection>scm:git:https://github.com/acme/lib.git</connection></scm>
+  <properties><shared>x</shared></properties>
+  <dependencies><dependency><groupId>org.acme</groupId><artifactId>core</artifactId><version>1.1-SNAPSHOT</version></dependency></dependencies>
+</project>`))
+	assert.Nil(t, err)
+	findings, err := (*LintConfig)(nil).Lint(p, nil)
+	assert.Nil(t, err)
+	assert.Empty(t, findings)
+}
+
+func Test_LoadLintConfig(t *testing.T) {
+	c, err := LoadLintConfig(strings.NewReader(`{"rules": {"unused-property": "off", "central-metadata": "error"}}`))
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]LintSeverity{RuleUnusedProperty: LintOff, RuleCentralMetadata: LintError}, c.Rules)
+
+	_, err = LoadLintConfig(strings.NewReader(`{"rules": {"no-such-rule": "off"}}`))
+	assert.EqualError(t, err, `unknown lint rule "no-such-rule"`)
+
+	_, err = LoadLintConfig(strings.NewReader(`{"rules": {"unused-property": "fatal"}}`))
+	assert.EqualError(t, err, `invalid severity "fatal" for lint rule unused-property`)
+}
+
+func Test_LintFindingsWriteSARIF(t *testing.T) {
+	findings := LintFindings{
+		{Rule: RuleInsecureRepository, Severity: LintError, Message: "insecure", Path: "project.repositories.repository[0].url",
+			Location: &InputLocation{Source: "app/pom.xml", Line: 3, Column: 7}},
+		{Rule: RuleCentralMetadata, Severity: LintNote, Message: "missing", Path: "project.name"},
+	}
+	var b bytes.Buffer
+	assert.Nil(t, findings.WriteSARIF(&b))
+	out := b.String()
+	assert.True(t, strings.HasPrefix(out, `{
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "version": "2.1.0",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "gopom",
+          "informationUri": "https://github.com/vifraa/gopom",
+          "rules": [
+            {
+              "id": "hardcoded-version",`), out)
+	assert.Contains(t, out, `      "results": [
+        {
+          "ruleId": "insecure-repository",
+          "ruleIndex": 5,
+          "level": "error",
+          "message": {
+            "text": "insecure"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "app/pom.xml"
+                },
+                "region": {
+                  "startLine": 3,
+                  "startColumn": 7
+                }
+              },
+              "logicalLocations": [
+                {
+                  "fullyQualifiedName": "project.repositories.repository[0].url"
+                }
+              ]
+            }
+          ]
+        },
+        {
+          "ruleId": "central-metadata",
+          "ruleIndex": 7,
+          "level": "note",
+          "message": {
+            "text": "missing"
+          }
+        }
+      ]`)
+}
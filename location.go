@@ -0,0 +1,238 @@
+package gopom
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// InputLocation is the position in the parsed input where an element starts.
+// Line and Column are 1-based, Column counts bytes from the start of the line.
+type InputLocation struct {
+	Source string
+	Line   int
+	Column int
+}
+
+func (l InputLocation) String() string {
+	if l.Source == "" {
+		return fmt.Sprintf("%d:%d", l.Line, l.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", l.Source, l.Line, l.Column)
+}
+
+// Locations maps model paths to the location of the element in the input.
+// Paths are the element names from the root joined by dots, with list entries
+// indexed from zero, e.g. "project.dependencies.dependency[1].version".
+type Locations map[string]InputLocation
+
+// Lookup returns the location of path, or of its closest recorded ancestor
+// when the element itself is not present in the input.
+func (l Locations) Lookup(path string) (InputLocation, bool) {
+	for path != "" {
+		if loc, ok := l[path]; ok {
+			return loc, true
+		}
+		i := strings.LastIndex(path, ".")
+		if i < 0 {
+			break
+		}
+		path = path[:i]
+	}
+	return InputLocation{}, false
+}
+
+// lineReader records where lines start while the input is being read so that
+// byte offsets reported by the decoder can be turned into lines and columns.
+type lineReader struct {
+	r      io.Reader
+	offset int64
+	starts []int64
+}
+
+func (l *lineReader) Read(b []byte) (int, error) {
+	n, err := l.r.Read(b)
+	for i, c := range b[:n] {
+		if c == '\n' {
+			l.starts = append(l.starts, l.offset+int64(i)+1)
+		}
+	}
+	l.offset += int64(n)
+	return n, err
+}
+
+func (l *lineReader) position(offset int64) (line, column int) {
+	i := sort.Search(len(l.starts), func(i int) bool { return l.starts[i] > offset })
+	var start int64
+	if i > 0 {
+		start = l.starts[i-1]
+	}
+	return i + 1, int(offset-start) + 1
+}
+
+// modelNode describes which child elements the model knows for an element.
+type modelNode struct {
+	children map[string]modelChild
+	freeform bool
+}
+
+type modelChild struct {
+	node *modelNode
+	list bool
+}
+
+var (
+	freeformNode = &modelNode{freeform: true}
+	leafNode     = &modelNode{}
+
+	propertiesType = reflect.TypeOf(Properties{})
+	projectModel   = modelOf(reflect.TypeOf(Project{}))
+)
+
+// modelOf builds the element tree of t from its xml struct tags.
+func modelOf(t reflect.Type) *modelNode {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t == propertiesType {
+		return freeformNode
+	}
+	if t.Kind() != reflect.Struct {
+		return leafNode
+	}
+	n := &modelNode{children: map[string]modelChild{}}
+	addModelFields(n, t)
+	return n
+}
+
+func addModelFields(n *modelNode, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("xml")
+		if f.Anonymous && tag == "" {
+			addModelFields(n, f.Type)
+			continue
+		}
+		opts := strings.Split(tag, ",")
+		name := opts[0]
+		if f.Name == "XMLName" || name == "" || name == "-" || !isElementTag(opts[1:]) {
+			continue
+		}
+
+		parts := strings.Split(name, ">")
+		cur := n
+		for _, part := range parts[:len(parts)-1] {
+			c, ok := cur.children[part]
+			if !ok {
+				c = modelChild{node: &modelNode{children: map[string]modelChild{}}}
+				cur.children[part] = c
+			}
+			cur = c.node
+		}
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		cur.children[parts[len(parts)-1]] = modelChild{node: modelOf(f.Type), list: ft.Kind() == reflect.Slice}
+	}
+}
+
+func isElementTag(opts []string) bool {
+	for _, o := range opts {
+		switch o {
+		case "attr", "chardata", "cdata", "innerxml", "comment", "any":
+			return false
+		}
+	}
+	return true
+}
+
+// trackFrame is an open element seen by the tracker.
+type trackFrame struct {
+	path   string
+	node   *modelNode
+	counts map[string]int
+}
+
+// tracker is an xml.TokenReader that follows the position of every element
+// in the model while the tokens are passed on to the decoder.
+type tracker struct {
+	d         *xml.Decoder
+	lines     *lineReader
+	source    string
+	stack     []*trackFrame
+	locations Locations
+	unknown   func(UnknownElement)
+}
+
+func newTracker(r io.Reader, source string) *tracker {
+	lines := &lineReader{r: r}
+	return &tracker{
+		d:      xml.NewDecoder(lines),
+		lines:  lines,
+		source: source,
+	}
+}
+
+func (t *tracker) Token() (xml.Token, error) {
+	offset := t.d.InputOffset()
+	tok, err := t.d.Token()
+	if err != nil {
+		return tok, err
+	}
+	switch el := tok.(type) {
+	case xml.StartElement:
+		t.start(el.Name.Local, offset)
+	case xml.EndElement:
+		if len(t.stack) > 0 {
+			t.stack = t.stack[:len(t.stack)-1]
+		}
+	}
+	return tok, nil
+}
+
+func (t *tracker) start(name string, offset int64) {
+	f := &trackFrame{path: name, counts: map[string]int{}}
+	if len(t.stack) == 0 {
+		if name == "project" {
+			f.node = projectModel
+		}
+	} else {
+		parent := t.stack[len(t.stack)-1]
+		segment := name
+		switch {
+		case parent.node == nil:
+		case parent.node.freeform:
+			f.node = freeformNode
+		default:
+			if c, ok := parent.node.children[name]; ok {
+				f.node = c.node
+				if c.list {
+					segment = fmt.Sprintf("%s[%d]", name, parent.counts[name])
+					parent.counts[name]++
+				}
+			} else if t.unknown != nil {
+				t.unknown(UnknownElement{
+					Path:       parent.path + "." + name,
+					Name:       name,
+					Location:   t.location(offset),
+					Suggestion: suggest(name, parent.node),
+				})
+			}
+		}
+		f.path = parent.path + "." + segment
+	}
+	t.stack = append(t.stack, f)
+
+	if t.locations != nil {
+		t.locations[f.path] = t.location(offset)
+	}
+}
+
+func (t *tracker) location(offset int64) InputLocation {
+	line, column := t.lines.position(offset)
+	return InputLocation{Source: t.source, Line: line, Column: column}
+}
@@ -0,0 +1,56 @@
+package gopom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var locationPom = `<project>
+  <modelVersion>4.0.0</modelVersion>
+  <artifactId>app</artifactId>
+  <dependencies>
+    <dependency>
+      <groupId>junit</groupId>
+    </dependency>
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <version>1.7.30</version>
+    </dependency>
+  </dependencies>
+  <properties>
+    <java.version>11</java.version>
+  </properties>
+</project>`
+
+func Test_ParseWithLocations(t *testing.T) {
+	project, err := ParseFromReader(strings.NewReader(locationPom), WithLocations(), WithSource("pom.xml"))
+	assert.Nil(t, err)
+
+	assert.Equal(t, InputLocation{Source: "pom.xml", Line: 1, Column: 1}, project.Locations["project"])
+	assert.Equal(t, InputLocation{Source: "pom.xml", Line: 3, Column: 3}, project.Locations["project.artifactId"])
+	assert.Equal(t, InputLocation{Source: "pom.xml", Line: 5, Column: 5}, project.Locations["project.dependencies.dependency[0]"])
+	assert.Equal(t, InputLocation{Source: "pom.xml", Line: 8, Column: 5}, project.Locations["project.dependencies.dependency[1]"])
+	assert.Equal(t, InputLocation{Source: "pom.xml", Line: 10, Column: 7}, project.Locations["project.dependencies.dependency[1].version"])
+	assert.Equal(t, InputLocation{Source: "pom.xml", Line: 14, Column: 5}, project.Locations["project.properties.java.version"])
+	assert.Equal(t, "org.slf4j", *(*project.Dependencies)[1].GroupID)
+}
+
+func Test_ParseWithoutLocations(t *testing.T) {
+	project, err := ParseFromReader(strings.NewReader(locationPom))
+	assert.Nil(t, err)
+	assert.Nil(t, project.Locations)
+}
+
+func Test_LocationsLookupFallsBackToAncestor(t *testing.T) {
+	project, err := ParseFromReader(strings.NewReader(locationPom), WithLocations())
+	assert.Nil(t, err)
+
+	loc, ok := project.Locations.Lookup("project.dependencies.dependency[0].version")
+	assert.True(t, ok)
+	assert.Equal(t, "5:5", loc.String())
+
+	_, ok = Locations{}.Lookup("project.version")
+	assert.False(t, ok)
+}
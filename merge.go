@@ -0,0 +1,764 @@
+package gopom
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// MergeConflict is an entry that both sides of a three-way merge changed in
+// different ways. Absent values are empty.
+type MergeConflict struct {
+	// Section is the part of the pom, e.g. "dependencies" or
+	// "build.plugins[org.apache.maven.plugins:maven-compiler-plugin].executions".
+	Section string `json:"section"`
+	Key     string `json:"key,omitempty"`
+	Field   string `json:"field,omitempty"`
+	Base    string `json:"base,omitempty"`
+	Ours    string `json:"ours,omitempty"`
+	Theirs  string `json:"theirs,omitempty"`
+}
+
+func (c MergeConflict) String() string {
+	subject := c.Section
+	for _, s := range []string{c.Key, c.Field} {
+		if s != "" {
+			subject += " " + s
+		}
+	}
+	return fmt.Sprintf("%s: base %s, ours %s, theirs %s", subject, quoteEmpty(c.Base), quoteEmpty(c.Ours), quoteEmpty(c.Theirs))
+}
+
+// MergeResult is the outcome of a three-way merge.
+type MergeResult struct {
+	// Merged is the text of ours with the changes of theirs applied.
+	// Conflicting entries keep the content of ours.
+	Merged    []byte
+	Conflicts []MergeConflict
+}
+
+// Clean reports whether the merge had no conflicts.
+func (r *MergeResult) Clean() bool {
+	return len(r.Conflicts) == 0
+}
+
+// Merge merges the changes made between base and theirs into ours. The
+// coordinates, parent, modules and properties are merged by name,
+// dependencies by management key, plugins by groupId:artifactId and their
+// executions by id, so that independent edits of the same list combine.
+// Repositories and profiles are matched by id and merged as a whole. The
+// edits are applied to the text of ours, which keeps its formatting and
+// comments.
+func Merge(base, ours, theirs []byte) (*MergeResult, error) {
+	var projects [3]*Project
+	for i, src := range [][]byte{base, ours, theirs} {
+		p, err := ParseFromReader(bytes.NewReader(src))
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %v", []string{"base", "ours", "theirs"}[i], err)
+		}
+		projects[i] = p
+	}
+	doc, err := ParseDocument(ours)
+	if err != nil {
+		return nil, err
+	}
+	m := &merger{doc: doc}
+	if err := m.merge(projects[0], projects[1], projects[2]); err != nil {
+		return nil, err
+	}
+	return &MergeResult{Merged: doc.Bytes(), Conflicts: m.conflicts}, nil
+}
+
+type merger struct {
+	doc       *Document
+	conflicts []MergeConflict
+}
+
+// mergeValue is one side of a merged value. ok is false when it is absent.
+type mergeValue struct {
+	text    string
+	summary string
+	ok      bool
+}
+
+func stringValue(s *string) mergeValue {
+	if s == nil {
+		return mergeValue{}
+	}
+	return mergeValue{text: *s, summary: *s, ok: true}
+}
+
+// elementValue compares v by its xml encoding and summarizes it as summary.
+func elementValue(v interface{}, summary string) mergeValue {
+	b, err := xml.Marshal(v)
+	if err != nil {
+		return mergeValue{text: err.Error(), summary: summary, ok: true}
+	}
+	return mergeValue{text: string(b), summary: summary, ok: true}
+}
+
+// merge3 reports whether the value of theirs should replace the one of ours
+// and whether both changed it differently.
+func merge3(b, o, t mergeValue) (apply, conflict bool) {
+	same := func(x, y mergeValue) bool { return x.ok == y.ok && x.text == y.text }
+	switch {
+	case same(o, t), same(b, t):
+		return false, false
+	case same(b, o):
+		return true, false
+	}
+	return false, true
+}
+
+func (m *merger) conflict(section, key, field string, b, o, t mergeValue) {
+	m.conflicts = append(m.conflicts, MergeConflict{
+		Section: section, Key: key, Field: field,
+		Base: b.summary, Ours: o.summary, Theirs: t.summary,
+	})
+}
+
+// field merges a text value stored at path.
+func (m *merger) field(section, key, field string, b, o, t mergeValue, path ...string) error {
+	apply, conflict := merge3(b, o, t)
+	if conflict {
+		m.conflict(section, key, field, b, o, t)
+	}
+	if !apply {
+		return nil
+	}
+	if t.ok {
+		return m.doc.SetText(t.text, path...)
+	}
+	return m.doc.Remove(path...)
+}
+
+// entry merges the presence of a keyed entry, adding or removing it in ours.
+// It returns true when the entry exists on both sides with different content,
+// in which case the caller merges its fields.
+func (m *merger) entry(section, key string, b, o, t mergeValue, add, remove func() error) (bool, error) {
+	if o.ok && t.ok {
+		return o.text != t.text, nil
+	}
+	apply, conflict := merge3(b, o, t)
+	if conflict {
+		m.conflict(section, key, "", b, o, t)
+	}
+	if !apply {
+		return false, nil
+	}
+	if t.ok {
+		return false, add()
+	}
+	return false, remove()
+}
+
+func (m *merger) merge(base, ours, theirs *Project) error {
+	for _, f := range []struct {
+		name    string
+		b, o, t *string
+	}{
+		{"modelVersion", base.ModelVersion, ours.ModelVersion, theirs.ModelVersion},
+		{"groupId", base.GroupID, ours.GroupID, theirs.GroupID},
+		{"artifactId", base.ArtifactID, ours.ArtifactID, theirs.ArtifactID},
+		{"version", base.Version, ours.Version, theirs.Version},
+		{"packaging", base.Packaging, ours.Packaging, theirs.Packaging},
+		{"name", base.Name, ours.Name, theirs.Name},
+		{"description", base.Description, ours.Description, theirs.Description},
+		{"url", base.URL, ours.URL, theirs.URL},
+		{"inceptionYear", base.InceptionYear, ours.InceptionYear, theirs.InceptionYear},
+	} {
+		if err := m.field("project", "", f.name, stringValue(f.b), stringValue(f.o), stringValue(f.t), f.name); err != nil {
+			return err
+		}
+	}
+
+	if err := m.parent(base.Parent, ours.Parent, theirs.Parent); err != nil {
+		return err
+	}
+	if err := m.strings("modules", "module", stringsOf(base.Modules), stringsOf(ours.Modules), stringsOf(theirs.Modules)); err != nil {
+		return err
+	}
+	if err := m.strings("subprojects", "subproject", stringsOf(base.Subprojects), stringsOf(ours.Subprojects), stringsOf(theirs.Subprojects)); err != nil {
+		return err
+	}
+	if err := m.properties(base.Properties, ours.Properties, theirs.Properties); err != nil {
+		return err
+	}
+	if err := m.dependencies("dependencies", []string{"dependencies"}, base.Dependencies, ours.Dependencies, theirs.Dependencies); err != nil {
+		return err
+	}
+	if err := m.dependencies("dependencyManagement", []string{"dependencyManagement", "dependencies"},
+		managedDependencies(base.DependencyManagement), managedDependencies(ours.DependencyManagement), managedDependencies(theirs.DependencyManagement)); err != nil {
+		return err
+	}
+	buildOf := func(p *Project) *BuildBase {
+		if p.Build == nil {
+			return nil
+		}
+		return &p.Build.BuildBase
+	}
+	if err := m.plugins("build.plugins", []string{"build", "plugins"},
+		plugins(buildOf(base)), plugins(buildOf(ours)), plugins(buildOf(theirs))); err != nil {
+		return err
+	}
+	if err := m.plugins("build.pluginManagement", []string{"build", "pluginManagement", "plugins"},
+		managedPlugins(buildOf(base)), managedPlugins(buildOf(ours)), managedPlugins(buildOf(theirs))); err != nil {
+		return err
+	}
+
+	return m.unmerged(base, ours, theirs)
+}
+
+func (m *merger) parent(b, o, t *Parent) error {
+	value := func(p *Parent) mergeValue {
+		if p == nil {
+			return mergeValue{}
+		}
+		return elementValue(p, p.Coordinates().GAV())
+	}
+	both, err := m.entry("parent", "", value(b), value(o), value(t),
+		func() error { return m.doc.Append(*t, "parent") },
+		func() error { return m.doc.Remove("parent") })
+	if err != nil || !both {
+		return err
+	}
+	if b == nil {
+		b = &Parent{}
+	}
+	for _, f := range []struct {
+		name    string
+		b, o, t *string
+	}{
+		{"groupId", b.GroupID, o.GroupID, t.GroupID},
+		{"artifactId", b.ArtifactID, o.ArtifactID, t.ArtifactID},
+		{"version", b.Version, o.Version, t.Version},
+		{"relativePath", b.RelativePath, o.RelativePath, t.RelativePath},
+	} {
+		if err := m.field("parent", "", f.name, stringValue(f.b), stringValue(f.o), stringValue(f.t), "parent", f.name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func stringsOf(values *[]string) map[string]bool {
+	set := map[string]bool{}
+	if values != nil {
+		for _, v := range *values {
+			set[v] = true
+		}
+	}
+	return set
+}
+
+// strings merges a list of names such as modules as a set.
+func (m *merger) strings(section, name string, b, o, t map[string]bool) error {
+	for _, key := range sortedSet(b, o, t) {
+		value := func(set map[string]bool) mergeValue {
+			return mergeValue{text: key, summary: key, ok: set[key]}
+		}
+		_, err := m.entry(section, key, value(b), value(o), value(t),
+			func() error { return m.doc.Append(key, name, section) },
+			func() error {
+				path := m.find([]string{section}, name, key, func(elem []string) string { return m.text(elem...) })
+				if path == nil {
+					return nil
+				}
+				return m.doc.Remove(path...)
+			})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func sortedSet(sets ...map[string]bool) []string {
+	var keys []string
+	seen := map[string]bool{}
+	for _, set := range sets {
+		for k := range set {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (m *merger) properties(b, o, t *Properties) error {
+	return m.propertyMap("properties", "", []string{"properties"}, b, o, t)
+}
+
+// rawElement marshals as an element with the given inner XML.
+type rawElement struct {
+	Inner string `xml:",innerxml"`
+}
+
+// innerXML returns the content of an entry as XML.
+func innerXML(p *Properties, k string) string {
+	if raw, ok := p.RawXML[k]; ok {
+		return raw
+	}
+	var escaped strings.Builder
+	xml.EscapeText(&escaped, []byte(p.Entries[k]))
+	return escaped.String()
+}
+
+// propertyMap merges free-form entries such as properties or plugin
+// configuration. Entries with nested elements are merged as a whole.
+func (m *merger) propertyMap(section, key string, path []string, b, o, t *Properties) error {
+	keys := map[string]bool{}
+	for _, p := range []*Properties{b, o, t} {
+		if p != nil {
+			for k := range p.Entries {
+				keys[k] = true
+			}
+		}
+	}
+	value := func(p *Properties, k string) mergeValue {
+		if p == nil {
+			return mergeValue{}
+		}
+		v, ok := p.Entries[k]
+		if raw, nested := p.RawXML[k]; nested && ok {
+			return mergeValue{text: raw, summary: raw, ok: true}
+		}
+		return mergeValue{text: v, summary: v, ok: ok}
+	}
+	for _, k := range sortedSet(keys) {
+		entryKey, field := k, ""
+		if key != "" {
+			entryKey, field = key, "configuration."+k
+		}
+		bv, ov, tv := value(b, k), value(o, k), value(t, k)
+		apply, conflict := merge3(bv, ov, tv)
+		if conflict {
+			m.conflict(section, entryKey, field, bv, ov, tv)
+		}
+		if !apply {
+			continue
+		}
+		elem := append(path[:len(path):len(path)], k)
+		var err error
+		switch {
+		case !tv.ok:
+			err = m.doc.Remove(elem...)
+		case t.RawXML[k] == "" && (o == nil || o.RawXML[k] == ""):
+			err = m.doc.SetText(tv.text, elem...)
+		case m.doc.Has(elem...):
+			err = m.doc.Replace(rawElement{Inner: innerXML(t, k)}, elem...)
+		default:
+			err = m.doc.Append(rawElement{Inner: innerXML(t, k)}, k, path...)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *merger) dependencies(section string, path []string, b, o, t *[]Dependency) error {
+	byKey := func(deps *[]Dependency) (map[string]*Dependency, []string) {
+		index := map[string]*Dependency{}
+		var keys []string
+		if deps != nil {
+			for i := range *deps {
+				key := (*deps)[i].Coordinates().ManagementKey()
+				index[key] = &(*deps)[i]
+				keys = append(keys, key)
+			}
+		}
+		return index, keys
+	}
+	bd, baseKeys := byKey(b)
+	od, oursKeys := byKey(o)
+	td, theirsKeys := byKey(t)
+	value := func(d *Dependency) mergeValue {
+		if d == nil {
+			return mergeValue{}
+		}
+		return elementValue(d, deref(d.Version))
+	}
+	keyOf := func(elem []string) string {
+		return Coordinates{
+			GroupID:    m.text(append(elem, "groupId")...),
+			ArtifactID: m.text(append(elem, "artifactId")...),
+			Type:       m.text(append(elem, "type")...),
+			Classifier: m.text(append(elem, "classifier")...),
+		}.ManagementKey()
+	}
+
+	for _, key := range orderedKeys(theirsKeys, oursKeys, baseKeys) {
+		bdep, odep, tdep := bd[key], od[key], td[key]
+		elem := func() []string { return m.find(path, "dependency", key, keyOf) }
+		both, err := m.entry(section, key, value(bdep), value(odep), value(tdep),
+			func() error { return m.doc.Append(*tdep, "dependency", path...) },
+			func() error { return m.removeAt(elem()) })
+		if err != nil {
+			return err
+		}
+		if !both {
+			continue
+		}
+		if bdep == nil {
+			bdep = &Dependency{}
+		}
+		for _, f := range []struct {
+			name    string
+			b, o, t *string
+		}{
+			{"version", bdep.Version, odep.Version, tdep.Version},
+			{"scope", bdep.Scope, odep.Scope, tdep.Scope},
+			{"optional", bdep.Optional, odep.Optional, tdep.Optional},
+			{"systemPath", bdep.SystemPath, odep.SystemPath, tdep.SystemPath},
+		} {
+			if err := m.field(section, key, f.name, stringValue(f.b), stringValue(f.o), stringValue(f.t), append(elem(), f.name)...); err != nil {
+				return err
+			}
+		}
+
+		exclusions := func(d *Dependency) mergeValue {
+			if d.Exclusions == nil {
+				return mergeValue{}
+			}
+			var names []string
+			for _, e := range *d.Exclusions {
+				names = append(names, deref(e.GroupID)+":"+deref(e.ArtifactID))
+			}
+			return mergeValue{text: strings.Join(names, ","), summary: strings.Join(names, ","), ok: true}
+		}
+		apply, conflict := merge3(exclusions(bdep), exclusions(odep), exclusions(tdep))
+		if conflict {
+			m.conflict(section, key, "exclusions", exclusions(bdep), exclusions(odep), exclusions(tdep))
+		}
+		if apply {
+			if err := m.doc.Remove(append(elem(), "exclusions")...); err != nil {
+				return err
+			}
+			if tdep.Exclusions != nil {
+				for _, e := range *tdep.Exclusions {
+					if err := m.doc.Append(e, "exclusion", append(elem(), "exclusions")...); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func (m *merger) plugins(section string, path []string, b, o, t *[]Plugin) error {
+	byKey := func(plugins *[]Plugin) (map[string]*Plugin, []string) {
+		index := map[string]*Plugin{}
+		var keys []string
+		if plugins != nil {
+			for i := range *plugins {
+				key := (*plugins)[i].Coordinates().Key().String()
+				index[key] = &(*plugins)[i]
+				keys = append(keys, key)
+			}
+		}
+		return index, keys
+	}
+	bp, baseKeys := byKey(b)
+	op, oursKeys := byKey(o)
+	tp, theirsKeys := byKey(t)
+	value := func(p *Plugin) mergeValue {
+		if p == nil {
+			return mergeValue{}
+		}
+		return elementValue(p, deref(p.Version))
+	}
+	keyOf := func(elem []string) string {
+		return Plugin{GroupID: m.textPtr(append(elem, "groupId")...), ArtifactID: m.textPtr(append(elem, "artifactId")...)}.Coordinates().Key().String()
+	}
+
+	for _, key := range orderedKeys(theirsKeys, oursKeys, baseKeys) {
+		bplugin, oplugin, tplugin := bp[key], op[key], tp[key]
+		elem := func() []string { return m.find(path, "plugin", key, keyOf) }
+		both, err := m.entry(section, key, value(bplugin), value(oplugin), value(tplugin),
+			func() error { return m.doc.Append(*tplugin, "plugin", path...) },
+			func() error { return m.removeAt(elem()) })
+		if err != nil {
+			return err
+		}
+		if !both {
+			continue
+		}
+		if bplugin == nil {
+			bplugin = &Plugin{}
+		}
+		for _, f := range []struct {
+			name    string
+			b, o, t *string
+		}{
+			{"version", bplugin.Version, oplugin.Version, tplugin.Version},
+			{"extensions", bplugin.Extensions, oplugin.Extensions, tplugin.Extensions},
+			{"inherited", bplugin.Inherited, oplugin.Inherited, tplugin.Inherited},
+		} {
+			if err := m.field(section, key, f.name, stringValue(f.b), stringValue(f.o), stringValue(f.t), append(elem(), f.name)...); err != nil {
+				return err
+			}
+		}
+
+		if err := m.propertyMap(section, key, append(elem(), "configuration"), bplugin.Configuration, oplugin.Configuration, tplugin.Configuration); err != nil {
+			return err
+		}
+		nested := section + "[" + key + "]."
+		if err := m.executions(nested+"executions", bplugin.Executions, oplugin.Executions, tplugin.Executions, elem); err != nil {
+			return err
+		}
+		if err := m.dependencies(nested+"dependencies", append(elem(), "dependencies"), bplugin.Dependencies, oplugin.Dependencies, tplugin.Dependencies); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *merger) executions(section string, b, o, t *[]PluginExecution, plugin func() []string) error {
+	byID := func(executions *[]PluginExecution) (map[string]*PluginExecution, []string) {
+		index := map[string]*PluginExecution{}
+		var ids []string
+		if executions != nil {
+			for i := range *executions {
+				id := derefOr((*executions)[i].ID, "default")
+				index[id] = &(*executions)[i]
+				ids = append(ids, id)
+			}
+		}
+		return index, ids
+	}
+	be, baseIDs := byID(b)
+	oe, oursIDs := byID(o)
+	te, theirsIDs := byID(t)
+	value := func(e *PluginExecution) mergeValue {
+		if e == nil {
+			return mergeValue{}
+		}
+		return elementValue(e, deref(e.Phase))
+	}
+	idOf := func(elem []string) string {
+		return derefOr(m.textPtr(append(elem, "id")...), "default")
+	}
+
+	for _, id := range orderedKeys(theirsIDs, oursIDs, baseIDs) {
+		bexec, oexec, texec := be[id], oe[id], te[id]
+		path := func() []string { return append(plugin(), "executions") }
+		elem := func() []string { return m.find(path(), "execution", id, idOf) }
+		both, err := m.entry(section, id, value(bexec), value(oexec), value(texec),
+			func() error { return m.doc.Append(*texec, "execution", path()...) },
+			func() error { return m.removeAt(elem()) })
+		if err != nil {
+			return err
+		}
+		if !both {
+			continue
+		}
+		var bv mergeValue
+		if bexec != nil {
+			bv = value(bexec)
+		}
+		apply, conflict := merge3(bv, value(oexec), value(texec))
+		if conflict {
+			m.conflict(section, id, "", bv, value(oexec), value(texec))
+		}
+		if apply {
+			if err := m.doc.Replace(*texec, elem()...); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// wholeEntry is an entry of a list that is merged as a whole, such as a
+// repository or a profile.
+type wholeEntry struct {
+	value   interface{}
+	summary string
+}
+
+// wholeEntries indexes the entries of a list by id.
+func wholeEntries(n int, entry func(i int) (id string, e wholeEntry)) (map[string]wholeEntry, []string) {
+	index := map[string]wholeEntry{}
+	var keys []string
+	for i := 0; i < n; i++ {
+		id, e := entry(i)
+		index[id] = e
+		keys = append(keys, id)
+	}
+	return index, keys
+}
+
+func repositoryEntries(repos *[]Repository) (map[string]wholeEntry, []string) {
+	if repos == nil {
+		return wholeEntries(0, nil)
+	}
+	return wholeEntries(len(*repos), func(i int) (string, wholeEntry) {
+		r := (*repos)[i]
+		return deref(r.ID), wholeEntry{value: r, summary: deref(r.URL)}
+	})
+}
+
+func pluginRepositoryEntries(repos *[]PluginRepository) (map[string]wholeEntry, []string) {
+	if repos == nil {
+		return wholeEntries(0, nil)
+	}
+	return wholeEntries(len(*repos), func(i int) (string, wholeEntry) {
+		r := (*repos)[i]
+		return deref(r.ID), wholeEntry{value: r, summary: deref(r.URL)}
+	})
+}
+
+func profileEntries(profiles *[]Profile) (map[string]wholeEntry, []string) {
+	if profiles == nil {
+		return wholeEntries(0, nil)
+	}
+	return wholeEntries(len(*profiles), func(i int) (string, wholeEntry) {
+		p := (*profiles)[i]
+		return deref(p.ID), wholeEntry{value: p, summary: deref(p.ID)}
+	})
+}
+
+// unmerged merges the sections whose entries are taken as a whole: an entry
+// theirs changed is applied when ours left it as in base. When both changed it
+// differently, the fields Diff finds changed are reported as conflicts.
+func (m *merger) unmerged(base, ours, theirs *Project) error {
+	oursChanges, theirsChanges := Diff(base, ours).Changes, Diff(base, theirs).Changes
+	for _, section := range []struct {
+		name, item string
+		entries    func(p *Project) (map[string]wholeEntry, []string)
+	}{
+		{"repositories", "repository", func(p *Project) (map[string]wholeEntry, []string) { return repositoryEntries(p.Repositories) }},
+		{"pluginRepositories", "pluginRepository", func(p *Project) (map[string]wholeEntry, []string) {
+			return pluginRepositoryEntries(p.PluginRepositories)
+		}},
+		{"profiles", "profile", func(p *Project) (map[string]wholeEntry, []string) { return profileEntries(p.Profiles) }},
+	} {
+		be, baseKeys := section.entries(base)
+		oe, oursKeys := section.entries(ours)
+		te, theirsKeys := section.entries(theirs)
+		value := func(e wholeEntry, ok bool) mergeValue {
+			if !ok {
+				return mergeValue{}
+			}
+			return elementValue(e.value, e.summary)
+		}
+		keyOf := func(elem []string) string { return m.text(append(elem, "id")...) }
+		for _, key := range orderedKeys(theirsKeys, oursKeys, baseKeys) {
+			bentry, bok := be[key]
+			oentry, ook := oe[key]
+			tentry, tok := te[key]
+			bv, ov, tv := value(bentry, bok), value(oentry, ook), value(tentry, tok)
+			apply, conflict := merge3(bv, ov, tv)
+			if conflict {
+				m.entryConflicts(section.name, key, bv, ov, tv, oursChanges, theirsChanges)
+			}
+			if !apply {
+				continue
+			}
+			elem := m.find([]string{section.name}, section.item, key, keyOf)
+			var err error
+			switch {
+			case !tok:
+				err = m.removeAt(elem)
+			case elem == nil:
+				err = m.doc.Append(tentry.value, section.item, section.name)
+			default:
+				err = m.doc.Replace(tentry.value, elem...)
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// entryConflicts reports an entry both sides changed differently, detailed by
+// the changes Diff finds in it when there are any.
+func (m *merger) entryConflicts(section, key string, b, o, t mergeValue, oursChanges, theirsChanges []Change) {
+	within := func(c Change) bool {
+		return c.Section == section && c.Key == key || strings.HasPrefix(c.Section, section+"["+key+"].")
+	}
+	reported := false
+	for _, tc := range theirsChanges {
+		if !within(tc) {
+			continue
+		}
+		oursValue := tc.Old
+		same := false
+		for _, oc := range oursChanges {
+			if oc.Section == tc.Section && oc.Key == tc.Key && oc.Field == tc.Field {
+				oursValue, same = oc.New, oc == tc
+			}
+		}
+		if same {
+			continue
+		}
+		m.conflicts = append(m.conflicts, MergeConflict{
+			Section: tc.Section, Key: tc.Key, Field: tc.Field,
+			Base: tc.Old, Ours: oursValue, Theirs: tc.New,
+		})
+		reported = true
+	}
+	if !reported {
+		m.conflict(section, key, "", b, o, t)
+	}
+}
+
+// orderedKeys lists the keys of all sides once, in the order of the first
+// side that has them.
+func orderedKeys(sides ...[]string) []string {
+	var keys []string
+	seen := map[string]bool{}
+	for _, side := range sides {
+		for _, k := range side {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	return keys
+}
+
+// find returns the path of the child named name of the element at path whose key is key.
+func (m *merger) find(path []string, name, key string, keyOf func(elem []string) string) []string {
+	for i := 0; ; i++ {
+		elem := append(path[:len(path):len(path)], fmt.Sprintf("%s[%d]", name, i))
+		if !m.doc.Has(elem...) {
+			return nil
+		}
+		if keyOf(elem) == key {
+			return elem
+		}
+	}
+}
+
+func (m *merger) removeAt(path []string) error {
+	if path == nil {
+		return nil
+	}
+	return m.doc.Remove(path...)
+}
+
+func (m *merger) text(path ...string) string {
+	return deref(m.textPtr(path...))
+}
+
+func (m *merger) textPtr(path ...string) *string {
+	if !m.doc.Has(path...) {
+		return nil
+	}
+	text, err := m.doc.Text(path...)
+	if err != nil {
+		return nil
+	}
+	return &text
+}
@@ -0,0 +1,246 @@
+package gopom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const mergeBase = `<project>
+  <groupId>com.example</groupId>
+  <artifactId>app</artifactId>
+  <version>1.0</version>
+  <properties>
+    <jackson.version>2.11.0</jackson.version>
+    <java.version>11</java.version>
+  </properties>
+  <dependencies>
+    <!-- logging -->
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+      <version>1.7.30</version>
+    </dependency>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+      <version>4.12</version>
+      <scope>test</scope>
+    </dependency>
+  </dependencies>
+  <build>
+    <plugins>
+      <plugin>
+        <artifactId>maven-surefire-plugin</artifactId>
+        <version>2.22.0</version>
+        <executions>
+          <execution>
+            <id>it</id>
+            <phase>integration-test</phase>
+          </execution>
+        </executions>
+      </plugin>
+    </plugins>
+  </build>
+</project>
+`
+
+func mergeEdit(replacements ...string) []byte {
+	return []byte(strings.NewReplacer(replacements...).Replace(mergeBase))
+}
+
+func Test_MergeClean(t *testing.T) {
+	ours := mergeEdit(
+		"<version>1.7.30</version>", "<version>1.7.32</version>",
+		"<java.version>11</java.version>", "<java.version>17</java.version>",
+	)
+	theirs := mergeEdit(
+		"<version>4.12</version>", "<version>4.13.2</version>",
+		"<jackson.version>2.11.0</jackson.version>", "<jackson.version>2.12.1</jackson.version>",
+		"<version>2.22.0</version>", "<version>3.0.0-M5</version>",
+		`      <scope>test</scope>
+    </dependency>`, `      <scope>test</scope>
+    </dependency>
+    <dependency>
+      <groupId>org.assertj</groupId>
+      <artifactId>assertj-core</artifactId>
+      <version>3.19.0</version>
+    </dependency>`,
+		`            <phase>integration-test</phase>
+          </execution>`, `            <phase>integration-test</phase>
+          </execution>
+          <execution>
+            <id>smoke</id>
+            <phase>verify</phase>
+          </execution>`,
+	)
+
+	result, err := Merge([]byte(mergeBase), ours, theirs)
+	assert.Nil(t, err)
+	assert.True(t, result.Clean(), "%v", result.Conflicts)
+
+	expected := `<project>
+  <groupId>com.example</groupId>
+  <artifactId>app</artifactId>
+  <version>1.0</version>
+  <properties>
+    <jackson.version>2.12.1</jackson.version>
+    <java.version>17</java.version>
+  </properties>
+  <dependencies>
+    <!-- logging -->
+    <dependency>
+      <groupId>org.slf4j</groupId>
+      <artifactId>slf4j-api</artifactId>
+      <version>1.7.32</version>
+    </dependency>
+    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+      <version>4.13.2</version>
+      <scope>test</scope>
+    </dependency>
+    <dependency>
+      <groupId>org.assertj</groupId>
+      <artifactId>assertj-core</artifactId>
+      <version>3.19.0</version>
+    </dependency>
+  </dependencies>
+  <build>
+    <plugins>
+      <plugin>
+        <artifactId>maven-surefire-plugin</artifactId>
+        <version>3.0.0-M5</version>
+        <executions>
+          <execution>
+            <id>it</id>
+            <phase>integration-test</phase>
+          </execution>
+          <execution>
+            <id>smoke</id>
+            <phase>verify</phase>
+          </execution>
+        </executions>
+      </plugin>
+    </plugins>
+  </build>
+</project>
+`
+	assert.Equal(t, expected, string(result.Merged))
+}
+
+func Test_MergeRemovals(t *testing.T) {
+	ours := mergeEdit("<version>1.0</version>", "<version>1.1</version>")
+	theirs := mergeEdit(
+		`    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+      <version>4.12</version>
+      <scope>test</scope>
+    </dependency>
+`, "",
+		`    <java.version>11</java.version>
+`, "",
+	)
+
+	result, err := Merge([]byte(mergeBase), ours, theirs)
+	assert.Nil(t, err)
+	assert.True(t, result.Clean())
+
+	merged, err := ParseFromReader(strings.NewReader(string(result.Merged)))
+	assert.Nil(t, err)
+	assert.Equal(t, "1.1", *merged.Version)
+	assert.Len(t, *merged.Dependencies, 1)
+	assert.Equal(t, map[string]string{"jackson.version": "2.11.0"}, merged.Properties.Entries)
+}
+
+func Test_MergeConflicts(t *testing.T) {
+	ours := mergeEdit(
+		"<version>4.12</version>", "<version>4.13</version>",
+		"<phase>integration-test</phase>", "<phase>verify</phase>",
+		"<build>", "<repositories><repository><id>internal</id><url>https://repo.example.org</url></repository></repositories>\n  <build>",
+	)
+	theirs := mergeEdit(
+		"<version>4.12</version>", "<version>4.13.2</version>",
+		"<phase>integration-test</phase>", "<phase>post-integration-test</phase>",
+		"<build>", "<repositories><repository><id>internal</id><url>https://repo.example.com</url></repository></repositories>\n  <build>",
+	)
+
+	result, err := Merge([]byte(mergeBase), ours, theirs)
+	assert.Nil(t, err)
+	assert.False(t, result.Clean())
+
+	var conflicts []string
+	for _, c := range result.Conflicts {
+		conflicts = append(conflicts, c.String())
+	}
+	assert.Equal(t, []string{
+		"dependencies junit:junit:jar version: base 4.12, ours 4.13, theirs 4.13.2",
+		"build.plugins[org.apache.maven.plugins:maven-surefire-plugin].executions it: base integration-test, ours verify, theirs post-integration-test",
+		`repositories internal: base "", ours https://repo.example.org, theirs https://repo.example.com`,
+	}, conflicts)
+
+	// Conflicting entries keep the content of ours.
+	assert.Equal(t, string(ours), string(result.Merged))
+}
+
+func Test_MergeTheirsOnlyRepositoryChange(t *testing.T) {
+	withRepositories := func(repositories string, replacements ...string) []byte {
+		return []byte(strings.NewReplacer(replacements...).Replace(
+			strings.Replace(mergeBase, "<build>", "<repositories>"+repositories+"</repositories>\n  <build>", 1)))
+	}
+	internal := "<repository><id>internal</id><url>http://repo.example.com</url></repository>"
+	base := withRepositories(internal)
+	ours := withRepositories(internal, "<version>4.12</version>", "<version>4.13</version>")
+	theirs := withRepositories(strings.Replace(internal, "http:", "https:", 1) +
+		"<repository><id>snapshots</id><url>https://snapshots.example.com</url></repository>")
+
+	result, err := Merge(base, ours, theirs)
+	assert.Nil(t, err)
+	assert.True(t, result.Clean(), "%v", result.Conflicts)
+	merged, err := ParseFromReader(strings.NewReader(string(result.Merged)))
+	assert.Nil(t, err)
+	assert.Equal(t, "4.13", *(*merged.Dependencies)[1].Version)
+	var urls []string
+	for _, r := range *merged.Repositories {
+		urls = append(urls, deref(r.ID)+" "+deref(r.URL))
+	}
+	assert.Equal(t, []string{"internal https://repo.example.com", "snapshots https://snapshots.example.com"}, urls)
+}
+
+func Test_MergeModifiedAndDeleted(t *testing.T) {
+	ours := mergeEdit("<version>4.12</version>", "<version>4.13</version>")
+	theirs := mergeEdit(`    <dependency>
+      <groupId>junit</groupId>
+      <artifactId>junit</artifactId>
+      <version>4.12</version>
+      <scope>test</scope>
+    </dependency>
+`, "")
+
+	result, err := Merge([]byte(mergeBase), ours, theirs)
+	assert.Nil(t, err)
+	assert.Equal(t, []MergeConflict{{Section: "dependencies", Key: "junit:junit:jar", Base: "4.12", Ours: "4.13"}}, result.Conflicts)
+}
+
+func Test_MergeInvalidInput(t *testing.T) {
+	_, err := Merge([]byte(mergeBase), []byte("<project>"), []byte(mergeBase))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "parsing ours")
+}
+
+func Test_MergeNestedConfiguration(t *testing.T) {
+	withConfiguration := func(configuration string) []byte {
+		return mergeEdit("<version>2.22.0</version>", "<version>2.22.0</version>\n        <configuration>"+configuration+"</configuration>")
+	}
+	base := withConfiguration("<includes><include>**/*Test.java</include></includes><forkCount>1</forkCount>")
+	ours := withConfiguration("<includes><include>**/*Test.java</include></includes><forkCount>2</forkCount>")
+	theirs := withConfiguration("<includes><include>**/*Test.java</include><include>**/*IT.java</include></includes><forkCount>1</forkCount>")
+
+	result, err := Merge(base, ours, theirs)
+	assert.Nil(t, err)
+	assert.True(t, result.Clean(), "%v", result.Conflicts)
+	assert.Contains(t, string(result.Merged),
+		"<configuration><includes><include>**/*Test.java</include><include>**/*IT.java</include></includes><forkCount>2</forkCount></configuration>")
+}
@@ -0,0 +1,114 @@
+package gopom
+
+import "fmt"
+
+// Supported pom model versions.
+const (
+	ModelVersion40 = "4.0.0"
+	ModelVersion41 = "4.1.0"
+)
+
+// PomNamespace41 is the XML namespace of the Maven 4.1.0 pom.
+const PomNamespace41 = "http://maven.apache.org/POM/4.1.0"
+
+// Namespace returns the XML namespace of poms with the given model version.
+func Namespace(modelVersion string) (string, error) {
+	switch modelVersion {
+	case ModelVersion40:
+		return PomNamespace, nil
+	case ModelVersion41:
+		return PomNamespace41, nil
+	}
+	return "", fmt.Errorf("unsupported model version %s", modelVersion)
+}
+
+// ChildProjects returns the subprojects aggregated by the project, read from
+// <subprojects> and the <modules> element it replaces in 4.1.0.
+func (p *Project) ChildProjects() []string {
+	return joinModules(p.Modules, p.Subprojects)
+}
+
+// ChildProjects returns the subprojects aggregated when the profile is active.
+func (p *Profile) ChildProjects() []string {
+	return joinModules(p.Modules, p.Subprojects)
+}
+
+// Normalize converts the project to the given model version so that code
+// working with the model only has to handle one shape of it: subprojects are
+// moved to <modules> for 4.0.0 and to <subprojects> for 4.1.0, and the model
+// version and namespace are set so the project marshals as a pom of that
+// version. Converting to 4.0.0 fails without modifying the project if it uses
+// elements that 4.0.0 cannot express.
+func (p *Project) Normalize(modelVersion string) error {
+	ns, err := Namespace(modelVersion)
+	if err != nil {
+		return err
+	}
+	if modelVersion == ModelVersion40 {
+		if path := p.only41(); path != "" {
+			return fmt.Errorf("%s is not supported in model version %s", path, modelVersion)
+		}
+	}
+
+	p.ModelVersion = &modelVersion
+	p.Xmlns = &ns
+	p.Modules, p.Subprojects = normalizeModules(modelVersion, p.Modules, p.Subprojects)
+	if p.Profiles != nil {
+		for i := range *p.Profiles {
+			profile := &(*p.Profiles)[i]
+			profile.Modules, profile.Subprojects = normalizeModules(modelVersion, profile.Modules, profile.Subprojects)
+		}
+	}
+	if modelVersion == ModelVersion40 {
+		p.Root = nil
+		if p.Packaging != nil && *p.Packaging == "bom" {
+			pom := "pom"
+			p.Packaging = &pom
+		}
+	}
+	return nil
+}
+
+// only41 returns the path of the first element used by the project that only
+// exists in model version 4.1.0, or "" if there is none.
+func (p *Project) only41() string {
+	if p.Build != nil && p.Build.Sources != nil {
+		return "project.build.sources"
+	}
+	if p.Profiles != nil {
+		for i, profile := range *p.Profiles {
+			if profile.Activation != nil && profile.Activation.Condition != nil {
+				return fmt.Sprintf("project.profiles.profile[%d].activation.condition", i)
+			}
+		}
+	}
+	return ""
+}
+
+func normalizeModules(modelVersion string, modules, subprojects *[]string) (*[]string, *[]string) {
+	all := joinModules(modules, subprojects)
+	if all == nil {
+		return nil, nil
+	}
+	if modelVersion == ModelVersion40 {
+		return &all, nil
+	}
+	return nil, &all
+}
+
+func joinModules(modules, subprojects *[]string) []string {
+	var all []string
+	seen := map[string]bool{}
+	for _, list := range []*[]string{subprojects, modules} {
+		if list == nil {
+			continue
+		}
+		for _, m := range *list {
+			if !seen[m] {
+				seen[m] = true
+				all = append(all, m)
+			}
+		}
+	}
+	return all
+}
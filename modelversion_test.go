@@ -0,0 +1,143 @@
+package gopom
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var pom41 = `<project xmlns="http://maven.apache.org/POM/4.1.0" root="true">
+  <modelVersion>4.1.0</modelVersion>
+  <parent>
+    <relativePath>../parent</relativePath>
+  </parent>
+  <artifactId>app-bom</artifactId>
+  <packaging>bom</packaging>
+  <subprojects>
+    <subproject>core</subproject>
+    <subproject>cli</subproject>
+  </subprojects>
+  <build>
+    <sources>
+      <source>
+        <scope>test</scope>
+        <lang>java</lang>
+        <directory>src/it/java</directory>
+        <includes><include>**/*IT.java</include></includes>
+        <targetVersion>17</targetVersion>
+        <stringFiltering>true</stringFiltering>
+      </source>
+    </sources>
+  </build>
+  <profiles>
+    <profile>
+      <id>linux</id>
+      <activation>
+        <condition>${os.name} == 'linux'</condition>
+      </activation>
+      <subprojects>
+        <subproject>native</subproject>
+      </subprojects>
+    </profile>
+  </profiles>
+</project>`
+
+func Test_Parsing41(t *testing.T) {
+	project, err := ParseFromReader(strings.NewReader(pom41), WithStrict())
+	if !assert.Nil(t, err) {
+		return
+	}
+
+	assert.Equal(t, PomNamespace41, *project.Xmlns)
+	assert.Equal(t, "true", *project.Root)
+	assert.Equal(t, "bom", *project.Packaging)
+	assert.Nil(t, project.Parent.Version)
+	assert.Equal(t, []string{"core", "cli"}, *project.Subprojects)
+	assert.Equal(t, []string{"core", "cli"}, project.ChildProjects())
+
+	source := (*project.Build.Sources)[0]
+	assert.Equal(t, "test", *source.Scope)
+	assert.Equal(t, "java", *source.Lang)
+	assert.Equal(t, "src/it/java", *source.Directory)
+	assert.Equal(t, []string{"**/*IT.java"}, *source.Includes)
+	assert.Equal(t, "17", *source.TargetVersion)
+	assert.Equal(t, "true", *source.StringFiltering)
+
+	profile := (*project.Profiles)[0]
+	assert.Equal(t, "${os.name} == 'linux'", *profile.Activation.Condition)
+	assert.Equal(t, []string{"native"}, profile.ChildProjects())
+
+	assert.Nil(t, project.Validate())
+}
+
+func Test_Marshaling41RoundTrip(t *testing.T) {
+	project, err := ParseFromReader(strings.NewReader(pom41))
+	assert.Nil(t, err)
+
+	b, err := xml.Marshal(project)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(string(b), `<project xmlns="http://maven.apache.org/POM/4.1.0" root="true">`))
+
+	var parsed Project
+	assert.Nil(t, xml.Unmarshal(b, &parsed))
+	assert.Equal(t, *project, parsed)
+}
+
+func Test_NormalizeTo41(t *testing.T) {
+	project, err := ParseFromReader(strings.NewReader(examplePom))
+	assert.Nil(t, err)
+
+	assert.Nil(t, project.Normalize(ModelVersion41))
+	assert.Equal(t, "4.1.0", *project.ModelVersion)
+	assert.Nil(t, project.Modules)
+	assert.Equal(t, []string{"module1", "module2"}, *project.Subprojects)
+
+	b, err := xml.Marshal(project)
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(string(b), `<project xmlns="http://maven.apache.org/POM/4.1.0">`))
+	assert.Contains(t, string(b), "<subprojects><subproject>module1</subproject><subproject>module2</subproject></subprojects>")
+}
+
+func Test_NormalizeTo40(t *testing.T) {
+	project, err := ParseFromReader(strings.NewReader(pom41))
+	assert.Nil(t, err)
+
+	err = project.Normalize(ModelVersion40)
+	assert.EqualError(t, err, "project.build.sources is not supported in model version 4.0.0")
+	assert.Equal(t, "4.1.0", *project.ModelVersion)
+
+	project.Build.Sources = nil
+	(*project.Profiles)[0].Activation.Condition = nil
+	assert.Nil(t, project.Normalize(ModelVersion40))
+	assert.Equal(t, PomNamespace, *project.Xmlns)
+	assert.Nil(t, project.Root)
+	assert.Equal(t, "pom", *project.Packaging)
+	assert.Nil(t, project.Subprojects)
+	assert.Equal(t, []string{"core", "cli"}, *project.Modules)
+	assert.Equal(t, []string{"native"}, *(*project.Profiles)[0].Modules)
+
+	assert.EqualError(t, project.Normalize("5.0.0"), "unsupported model version 5.0.0")
+}
+
+func Test_Validate41ElementsIn40(t *testing.T) {
+	project, err := ParseFromReader(strings.NewReader(strings.Replace(pom41, "<modelVersion>4.1.0", "<modelVersion>4.0.0", 1)))
+	assert.Nil(t, err)
+
+	errs := project.Validate().(ValidationErrors)
+	var paths []string
+	for _, e := range errs {
+		paths = append(paths, e.Path)
+	}
+	assert.Equal(t, []string{
+		"project.groupId",
+		"project.version",
+		"project.parent.groupId",
+		"project.parent.artifactId",
+		"project.parent.version",
+		"project.root",
+		"project.subprojects",
+		"project.build.sources",
+	}, paths)
+}
@@ -0,0 +1,319 @@
+package gopom
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// OSVEcosystem is the name of the Maven ecosystem in OSV advisories.
+const OSVEcosystem = "Maven"
+
+// OSVAdvisory is a vulnerability in the OSV format, as published by osv.dev.
+type OSVAdvisory struct {
+	ID        string        `json:"id"`
+	Modified  string        `json:"modified,omitempty"`
+	Withdrawn string        `json:"withdrawn,omitempty"`
+	Aliases   []string      `json:"aliases,omitempty"`
+	Summary   string        `json:"summary,omitempty"`
+	Details   string        `json:"details,omitempty"`
+	Severity  []OSVSeverity `json:"severity,omitempty"`
+	Affected  []OSVAffected `json:"affected,omitempty"`
+}
+
+type OSVSeverity struct {
+	Type  string `json:"type"`
+	Score string `json:"score"`
+}
+
+type OSVAffected struct {
+	Package  OSVPackage `json:"package"`
+	Ranges   []OSVRange `json:"ranges,omitempty"`
+	Versions []string   `json:"versions,omitempty"`
+}
+
+type OSVPackage struct {
+	Ecosystem string `json:"ecosystem"`
+	// Name is groupId:artifactId for Maven packages.
+	Name string `json:"name"`
+	Purl string `json:"purl,omitempty"`
+}
+
+type OSVRange struct {
+	Type   string     `json:"type"`
+	Events []OSVEvent `json:"events"`
+}
+
+// OSVEvent is one event of a range; exactly one of its fields is set.
+type OSVEvent struct {
+	Introduced   string `json:"introduced,omitempty"`
+	Fixed        string `json:"fixed,omitempty"`
+	LastAffected string `json:"last_affected,omitempty"`
+	Limit        string `json:"limit,omitempty"`
+}
+
+func (e OSVEvent) version() string {
+	switch {
+	case e.Introduced != "":
+		return e.Introduced
+	case e.Fixed != "":
+		return e.Fixed
+	case e.LastAffected != "":
+		return e.LastAffected
+	}
+	return e.Limit
+}
+
+// Affects reports whether the range contains version. Only ECOSYSTEM ranges
+// are evaluated, using Maven's version ordering.
+func (r OSVRange) Affects(version string) bool {
+	if r.Type != "ECOSYSTEM" {
+		return false
+	}
+	events := append([]OSVEvent(nil), r.Events...)
+	sort.SliceStable(events, func(i, j int) bool {
+		return compareOSVVersions(events[i].version(), events[j].version()) < 0
+	})
+	affected := false
+	for _, e := range events {
+		switch {
+		case e.Introduced != "":
+			if compareOSVVersions(version, e.Introduced) >= 0 {
+				affected = true
+			}
+		case e.Fixed != "":
+			if compareOSVVersions(version, e.Fixed) >= 0 {
+				affected = false
+			}
+		case e.LastAffected != "":
+			if compareOSVVersions(version, e.LastAffected) > 0 {
+				affected = false
+			}
+		}
+	}
+	return affected
+}
+
+// compareOSVVersions compares versions like CompareVersions, except that the
+// version 0 OSV uses for "since the first release" precedes every version.
+func compareOSVVersions(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "0":
+		return -1
+	case b == "0":
+		return 1
+	}
+	return CompareVersions(a, b)
+}
+
+// Affects reports whether the advisory applies to the artifact, either by
+// listing its version or by a range containing it. Withdrawn advisories
+// affect nothing.
+func (a *OSVAdvisory) Affects(c Coordinates) bool {
+	if a.Withdrawn != "" {
+		return false
+	}
+	for _, affected := range a.Affected {
+		if affected.affects(c) {
+			return true
+		}
+	}
+	return false
+}
+
+func (a OSVAffected) affects(c Coordinates) bool {
+	if a.Package.Ecosystem != OSVEcosystem || a.Package.Name != c.Key().String() {
+		return false
+	}
+	for _, v := range a.Versions {
+		if CompareVersions(v, c.Version) == 0 {
+			return true
+		}
+	}
+	for _, r := range a.Ranges {
+		if r.Affects(c.Version) {
+			return true
+		}
+	}
+	return false
+}
+
+// OSVDatabase is an offline collection of Maven advisories.
+type OSVDatabase struct {
+	byPackage map[string][]*OSVAdvisory
+}
+
+// NewOSVDatabase indexes the Maven advisories by package.
+func NewOSVDatabase(advisories []OSVAdvisory) *OSVDatabase {
+	db := &OSVDatabase{byPackage: map[string][]*OSVAdvisory{}}
+	for i := range advisories {
+		db.add(&advisories[i])
+	}
+	return db
+}
+
+func (db *OSVDatabase) add(a *OSVAdvisory) {
+	seen := map[string]bool{}
+	for _, affected := range a.Affected {
+		name := affected.Package.Name
+		if affected.Package.Ecosystem == OSVEcosystem && !seen[name] {
+			seen[name] = true
+			db.byPackage[name] = append(db.byPackage[name], a)
+		}
+	}
+}
+
+// LoadOSVDatabase reads a snapshot of the OSV Maven ecosystem, either a
+// directory of advisory JSON files or a zip archive such as the all.zip
+// export of osv.dev.
+func LoadOSVDatabase(path string) (*OSVDatabase, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	db := &OSVDatabase{byPackage: map[string][]*OSVAdvisory{}}
+	read := func(name string, r io.Reader) error {
+		var a OSVAdvisory
+		if err := json.NewDecoder(r).Decode(&a); err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+		db.add(&a)
+		return nil
+	}
+
+	if !info.IsDir() {
+		z, err := zip.OpenReader(path)
+		if err != nil {
+			return nil, err
+		}
+		defer z.Close()
+		for _, f := range z.File {
+			if !strings.HasSuffix(f.Name, ".json") {
+				continue
+			}
+			r, err := f.Open()
+			if err != nil {
+				return nil, err
+			}
+			err = read(f.Name, r)
+			r.Close()
+			if err != nil {
+				return nil, err
+			}
+		}
+		return db, nil
+	}
+
+	err = filepath.Walk(path, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !strings.HasSuffix(p, ".json") {
+			return err
+		}
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		return read(p, f)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// Advisories returns the advisories affecting the artifact, sorted by id.
+func (db *OSVDatabase) Advisories(c Coordinates) []*OSVAdvisory {
+	var matches []*OSVAdvisory
+	for _, a := range db.byPackage[c.Key().String()] {
+		if a.Affects(c) {
+			matches = append(matches, a)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].ID < matches[j].ID })
+	return matches
+}
+
+// FixedVersion returns the lowest version above the version of c that the
+// advisories of its package name as fixed and that none of them affects. It
+// is empty when no such version is known.
+func (db *OSVDatabase) FixedVersion(c Coordinates) string {
+	var candidates []string
+	for _, a := range db.byPackage[c.Key().String()] {
+		for _, affected := range a.Affected {
+			if affected.Package.Ecosystem != OSVEcosystem || affected.Package.Name != c.Key().String() {
+				continue
+			}
+			for _, r := range affected.Ranges {
+				for _, e := range r.Events {
+					if e.Fixed != "" && CompareVersions(e.Fixed, c.Version) > 0 {
+						candidates = append(candidates, e.Fixed)
+					}
+				}
+			}
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return CompareVersions(candidates[i], candidates[j]) < 0 })
+	for _, v := range candidates {
+		fixed := c
+		fixed.Version = v
+		if len(db.Advisories(fixed)) == 0 {
+			return v
+		}
+	}
+	return ""
+}
+
+// Vulnerability is a dependency affected by advisories.
+type Vulnerability struct {
+	Coordinates Coordinates
+	// Path leads from the project to the dependency.
+	Path       []Coordinates
+	Advisories []*OSVAdvisory
+	// FixedVersion is the lowest version not affected by any known
+	// advisory, empty when there is none.
+	FixedVersion string
+}
+
+func (v Vulnerability) String() string {
+	ids := make([]string, len(v.Advisories))
+	for i, a := range v.Advisories {
+		ids[i] = a.ID
+	}
+	s := fmt.Sprintf("%s: %s", v.Coordinates, strings.Join(ids, ", "))
+	if v.FixedVersion != "" {
+		s += ", fixed in " + v.FixedVersion
+	}
+	return s
+}
+
+// Check matches every dependency of the resolved graph against the database
+// and returns the affected ones in the order they appear in the graph.
+func (db *OSVDatabase) Check(graph *DependencyNode) ([]Vulnerability, error) {
+	if graph == nil {
+		return nil, fmt.Errorf("no dependency graph")
+	}
+	var vulnerabilities []Vulnerability
+	seen := map[string]bool{}
+	for _, d := range graph.Flatten() {
+		if seen[d.Coordinates.String()] {
+			continue
+		}
+		seen[d.Coordinates.String()] = true
+		if advisories := db.Advisories(d.Coordinates); len(advisories) > 0 {
+			vulnerabilities = append(vulnerabilities, Vulnerability{
+				Coordinates:  d.Coordinates,
+				Path:         d.Path,
+				Advisories:   advisories,
+				FixedVersion: db.FixedVersion(d.Coordinates),
+			})
+		}
+	}
+	return vulnerabilities, nil
+}
@@ -0,0 +1,148 @@
+package gopom
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var osvAdvisories = map[string]string{
+	"GHSA-jfh8-c2jp-5v3q.json": `{
+  "id": "GHSA-jfh8-c2jp-5v3q",
+  "aliases": ["CVE-2021-44228"],
+  "summary": "Remote code injection in Log4j",
+  "affected": [{
+    "package": {"ecosystem": "Maven", "name": "org.apache.logging.log4j:log4j-core"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [
+      {"introduced": "2.13.0"}, {"fixed": "2.15.0"},
+      {"introduced": "2.0-beta9"}, {"fixed": "2.3.1"},
+      {"introduced": "2.4"}, {"fixed": "2.12.2"}
+    ]}]
+  }]
+}`,
+	"GHSA-7rjr-3q55-vv33.json": `{
+  "id": "GHSA-7rjr-3q55-vv33",
+  "aliases": ["CVE-2021-45046"],
+  "affected": [{
+    "package": {"ecosystem": "Maven", "name": "org.apache.logging.log4j:log4j-core"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "2.13.0"}, {"fixed": "2.16.0"}]}]
+  }]
+}`,
+	"nested/GHSA-old.json": `{
+  "id": "GHSA-old",
+  "affected": [{
+    "package": {"ecosystem": "Maven", "name": "org.acme:util"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}, {"last_affected": "2"}]}],
+    "versions": ["7"]
+  }]
+}`,
+	"GHSA-withdrawn.json": `{
+  "id": "GHSA-withdrawn",
+  "withdrawn": "2022-01-01T00:00:00Z",
+  "affected": [{
+    "package": {"ecosystem": "Maven", "name": "org.acme:core"},
+    "ranges": [{"type": "ECOSYSTEM", "events": [{"introduced": "0"}]}]
+  }]
+}`,
+	"README.md": "not an advisory",
+}
+
+func Test_OSVRangeAffects(t *testing.T) {
+	r := OSVRange{Type: "ECOSYSTEM", Events: []OSVEvent{{Fixed: "2.15.0"}, {Introduced: "2.13.0"}, {Introduced: "2.0-beta9"}, {Fixed: "2.3.1"}}}
+	for version, affected := range map[string]bool{
+		"1.2.17":    false,
+		"2.0-beta8": false,
+		"2.0-beta9": true,
+		"2.0":       true,
+		"2.3.1":     false,
+		"2.12.1":    false,
+		"2.14.1":    true,
+		"2.15.0":    false,
+	} {
+		assert.Equal(t, affected, r.Affects(version), version)
+	}
+
+	last := OSVRange{Type: "ECOSYSTEM", Events: []OSVEvent{{Introduced: "0"}, {LastAffected: "1.5"}}}
+	assert.True(t, last.Affects("1.0-alpha"))
+	assert.True(t, last.Affects("1.5"))
+	assert.False(t, last.Affects("1.5.1"))
+	assert.False(t, OSVRange{Type: "GIT", Events: []OSVEvent{{Introduced: "0"}}}.Affects("1"))
+}
+
+func Test_LoadOSVDatabase(t *testing.T) {
+	dir := writeRepository(t, osvAdvisories)
+	db, err := LoadOSVDatabase(dir)
+	assert.Nil(t, err)
+
+	log4j := Coordinates{GroupID: "org.apache.logging.log4j", ArtifactID: "log4j-core", Version: "2.14.1"}
+	var ids []string
+	for _, a := range db.Advisories(log4j) {
+		ids = append(ids, a.ID)
+	}
+	assert.Equal(t, []string{"GHSA-7rjr-3q55-vv33", "GHSA-jfh8-c2jp-5v3q"}, ids)
+	assert.Equal(t, "2.16.0", db.FixedVersion(log4j))
+
+	log4j.Version = "2.12.1"
+	assert.Len(t, db.Advisories(log4j), 1)
+	assert.Equal(t, "2.12.2", db.FixedVersion(log4j))
+	log4j.Version = "2.17.1"
+	assert.Empty(t, db.Advisories(log4j))
+
+	assert.Empty(t, db.Advisories(Coordinates{GroupID: "org.acme", ArtifactID: "core", Version: "1.0"}))
+	assert.Len(t, db.Advisories(Coordinates{GroupID: "org.acme", ArtifactID: "util", Version: "7"}), 1)
+	assert.Equal(t, "", db.FixedVersion(Coordinates{GroupID: "org.acme", ArtifactID: "util", Version: "2"}))
+}
+
+func Test_LoadOSVDatabaseZip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "all.zip")
+	f, err := os.Create(path)
+	assert.Nil(t, err)
+	z := zip.NewWriter(f)
+	for name, content := range osvAdvisories {
+		w, err := z.Create(name)
+		assert.Nil(t, err)
+		_, err = w.Write([]byte(content))
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, z.Close())
+	assert.Nil(t, f.Close())
+
+	db, err := LoadOSVDatabase(path)
+	assert.Nil(t, err)
+	assert.Len(t, db.Advisories(Coordinates{GroupID: "org.apache.logging.log4j", ArtifactID: "log4j-core", Version: "2.14.0"}), 2)
+}
+
+func Test_LoadOSVDatabaseInvalid(t *testing.T) {
+	dir := writeRepository(t, map[string]string{"broken.json": "{"})
+	_, err := LoadOSVDatabase(dir)
+	assert.EqualError(t, err, filepath.Join(dir, "broken.json")+": unexpected EOF")
+}
+
+func Test_OSVDatabaseCheck(t *testing.T) {
+	db := NewOSVDatabase([]OSVAdvisory{{
+		ID: "GHSA-util",
+		Affected: []OSVAffected{{
+			Package: OSVPackage{Ecosystem: "Maven", Name: "org.acme:util"},
+			Ranges:  []OSVRange{{Type: "ECOSYSTEM", Events: []OSVEvent{{Introduced: "1.0"}, {Fixed: "1.6"}}}},
+		}},
+	}})
+	root, err := resolveString(t, resolveApp)
+	assert.Nil(t, err)
+
+	vulnerabilities, err := db.Check(root)
+	assert.Nil(t, err)
+	assert.Len(t, vulnerabilities, 1)
+	v := vulnerabilities[0]
+	assert.Equal(t, "org.acme:util:1.5: GHSA-util, fixed in 1.6", v.String())
+	assert.Equal(t, []Coordinates{
+		{GroupID: "org.acme", ArtifactID: "app", Version: "1"},
+		{GroupID: "org.acme", ArtifactID: "lib", Version: "2.0"},
+		{GroupID: "org.acme", ArtifactID: "util", Version: "1.5"},
+	}, v.Path)
+
+	_, err = db.Check(nil)
+	assert.EqualError(t, err, "no dependency graph")
+}
@@ -0,0 +1,172 @@
+package gopom
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// ParseOption configures how Parse and ParseFromReader decode a pom.
+type ParseOption func(*parseConfig)
+
+type parseConfig struct {
+	source    string
+	locations bool
+	strict    bool
+	unknown   []func(UnknownElement)
+	sections  map[string]bool
+}
+
+// WithSource sets the source name reported in input locations.
+// Parse uses the path of the file by default.
+func WithSource(name string) ParseOption {
+	return func(c *parseConfig) {
+		c.source = name
+	}
+}
+
+// WithLocations records the input location of every element into Project.Locations.
+func WithLocations() ParseOption {
+	return func(c *parseConfig) {
+		c.locations = true
+	}
+}
+
+// Section is a part of the pom that can be decoded selectively. Besides the
+// predefined sections any top-level element name, such as "scm", can be used.
+type Section string
+
+const (
+	// SectionCoordinates holds modelVersion, groupId, artifactId, version and packaging.
+	SectionCoordinates          Section = "coordinates"
+	SectionParent               Section = "parent"
+	SectionProperties           Section = "properties"
+	SectionDependencies         Section = "dependencies"
+	SectionDependencyManagement Section = "dependencyManagement"
+	SectionLicenses             Section = "licenses"
+	SectionRepositories         Section = "repositories"
+	SectionBuild                Section = "build"
+	SectionProfiles             Section = "profiles"
+	// SectionModules holds modules as well as the 4.1.0 subprojects.
+	SectionModules Section = "modules"
+)
+
+var sectionElements = map[Section][]string{
+	SectionCoordinates: {"modelVersion", "groupId", "artifactId", "version", "packaging"},
+	SectionModules:     {"modules", "subprojects"},
+}
+
+// WithSections only decodes the given sections of the pom and skips all other
+// elements, which makes scanning many poms for a few facts considerably cheaper.
+func WithSections(sections ...Section) ParseOption {
+	return func(c *parseConfig) {
+		if c.sections == nil {
+			c.sections = map[string]bool{}
+		}
+		for _, s := range sections {
+			elements, ok := sectionElements[s]
+			if !ok {
+				elements = []string{string(s)}
+			}
+			for _, e := range elements {
+				c.sections[e] = true
+			}
+		}
+	}
+}
+
+func parse(reader io.Reader, opts []ParseOption) (*Project, error) {
+	var cfg parseConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	var project Project
+	var tokens xml.TokenReader
+	var t *tracker
+	var unknown []UnknownElement
+
+	if cfg.locations || cfg.strict || len(cfg.unknown) > 0 {
+		t = newTracker(reader, cfg.source)
+		if cfg.locations {
+			t.locations = Locations{}
+		}
+		t.unknown = func(u UnknownElement) {
+			unknown = append(unknown, u)
+			for _, fn := range cfg.unknown {
+				fn(u)
+			}
+		}
+		tokens = t
+	} else {
+		tokens = xml.NewDecoder(reader)
+	}
+	if cfg.sections != nil {
+		tokens = &sectionFilter{r: tokens, sections: cfg.sections}
+	}
+
+	err := xml.NewTokenDecoder(tokens).Decode(&project)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.strict && len(unknown) > 0 {
+		return nil, &UnknownElementsError{Elements: unknown}
+	}
+	if t != nil {
+		project.Locations = t.locations
+	}
+	return &project, nil
+}
+
+// sectionFilter is an xml.TokenReader that drops every top-level element of
+// the pom that is not part of the selected sections.
+type sectionFilter struct {
+	r        xml.TokenReader
+	sections map[string]bool
+	depth    int
+}
+
+func (f *sectionFilter) Token() (xml.Token, error) {
+	for {
+		tok, err := f.r.Token()
+		if err != nil {
+			return tok, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if f.depth == 1 && !f.sections[t.Name.Local] {
+				if err := f.skip(); err != nil {
+					return nil, err
+				}
+				continue
+			}
+			f.depth++
+		case xml.EndElement:
+			f.depth--
+		case xml.CharData:
+			if f.depth == 1 {
+				continue
+			}
+		}
+		return tok, nil
+	}
+}
+
+// skip consumes the tokens up to the end of the element just started.
+func (f *sectionFilter) skip() error {
+	for depth := 1; depth > 0; {
+		tok, err := f.r.Token()
+		if err != nil {
+			if err == io.EOF {
+				return io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		switch tok.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+	return nil
+}
@@ -0,0 +1,233 @@
+package gopom
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ParsePolyglotYAML parses a pom.yml in the format of polyglot Maven. It has
+// the structure of the YAML written by WriteYAML and accepts the polyglot
+// shorthands: "id" and "parent" as groupId:artifactId:version strings, and
+// dependencies, plugins and extensions as
+// groupId:artifactId[:extension[:classifier]][:version] strings.
+func ParsePolyglotYAML(r io.Reader) (*Project, error) {
+	var doc yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("pom.yml must contain a mapping")
+	}
+	root := doc.Content[0]
+	if err := expandShorthands(root, true); err != nil {
+		return nil, err
+	}
+	var p Project
+	if err := root.Decode(&p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// WritePolyglotYAML writes the pom in the polyglot Maven YAML format, using
+// the shorthand notation for the project id, the parent and dependencies
+// wherever it carries all their fields.
+func (p *Project) WritePolyglotYAML(w io.Writer) error {
+	b, err := yaml.Marshal(p)
+	if err != nil {
+		return err
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(b, &root); err != nil {
+		return err
+	}
+	compactShorthands(&root, true)
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&root); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// coordinateKeys are the fields a shorthand string expands to, in order.
+var coordinateKeys = []string{"groupId", "artifactId", "type", "classifier", "version"}
+
+func expandShorthands(node *yaml.Node, project bool) error {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		switch {
+		case key.Value == "configuration":
+			continue
+		case (key.Value == "id" && project || key.Value == "parent") && value.Kind == yaml.ScalarNode && strings.Contains(value.Value, ":"):
+			c, err := parseShorthand(value)
+			if err != nil {
+				return err
+			}
+			fields := coordinateNodes(c)
+			if key.Value == "parent" {
+				node.Content[i+1] = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: fields}
+				continue
+			}
+			node.Content = append(node.Content[:i], append(fields, node.Content[i+2:]...)...)
+			i += len(fields) - 2
+			continue
+		case (key.Value == "dependencies" || key.Value == "plugins" || key.Value == "extensions") && value.Kind == yaml.SequenceNode:
+			for j, item := range value.Content {
+				if item.Kind == yaml.ScalarNode {
+					c, err := parseShorthand(item)
+					if err != nil {
+						return err
+					}
+					value.Content[j] = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Content: coordinateNodes(c)}
+				} else if item.Kind == yaml.MappingNode {
+					if err := expandShorthands(item, true); err != nil {
+						return err
+					}
+				}
+			}
+			continue
+		}
+		if err := expandShorthands(value, false); err != nil {
+			return err
+		}
+	}
+	if node.Kind == yaml.SequenceNode {
+		for _, item := range node.Content {
+			if err := expandShorthands(item, false); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// parseShorthand parses groupId:artifactId with an optional extension,
+// classifier and version.
+func parseShorthand(node *yaml.Node) (Coordinates, error) {
+	parts := strings.Split(node.Value, ":")
+	if len(parts) == 2 && parts[0] != "" && parts[1] != "" {
+		return Coordinates{GroupID: parts[0], ArtifactID: parts[1]}, nil
+	}
+	c, err := ParseCoordinates(node.Value)
+	if err != nil {
+		return Coordinates{}, fmt.Errorf("line %d: %v", node.Line, err)
+	}
+	return c, nil
+}
+
+func coordinateNodes(c Coordinates) []*yaml.Node {
+	values := []string{c.GroupID, c.ArtifactID, c.Type, c.Classifier, c.Version}
+	var nodes []*yaml.Node
+	for i, v := range values {
+		if v != "" {
+			nodes = append(nodes,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: coordinateKeys[i]},
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v})
+		}
+	}
+	return nodes
+}
+
+func compactShorthands(node *yaml.Node, project bool) {
+	if node.Kind == yaml.DocumentNode {
+		for _, child := range node.Content {
+			compactShorthands(child, project)
+		}
+		return
+	}
+	if node.Kind == yaml.MappingNode && project {
+		compactProjectID(node)
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		switch {
+		case key.Value == "configuration":
+			continue
+		case key.Value == "parent" && project:
+			if c, ok := shorthandOf(value, "groupId", "artifactId", "version"); ok {
+				node.Content[i+1] = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: c}
+			}
+			continue
+		case key.Value == "dependencies" && value.Kind == yaml.SequenceNode:
+			for j, item := range value.Content {
+				if c, ok := shorthandOf(item, coordinateKeys...); ok {
+					value.Content[j] = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: c}
+				}
+			}
+			continue
+		}
+		compactShorthands(value, false)
+	}
+	if node.Kind == yaml.SequenceNode {
+		for _, item := range node.Content {
+			compactShorthands(item, false)
+		}
+	}
+}
+
+// compactProjectID replaces the groupId, artifactId and version of a project
+// by an id in place of the groupId.
+func compactProjectID(node *yaml.Node) {
+	fields := map[string]string{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if value := node.Content[i+1]; value.Kind == yaml.ScalarNode {
+			fields[node.Content[i].Value] = value.Value
+		}
+	}
+	c := Coordinates{GroupID: fields["groupId"], ArtifactID: fields["artifactId"], Version: fields["version"]}
+	if parsed, err := ParseCoordinates(c.String()); err != nil || parsed != c {
+		return
+	}
+	var content []*yaml.Node
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		switch node.Content[i].Value {
+		case "groupId":
+			content = append(content,
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "id"},
+				&yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: c.String()})
+		case "artifactId", "version":
+		default:
+			content = append(content, node.Content[i], node.Content[i+1])
+		}
+	}
+	node.Content = content
+}
+
+// shorthandOf returns the shorthand string for a mapping with a groupId,
+// artifactId and version, when it only has the allowed fields and the
+// shorthand parses back to the same fields.
+func shorthandOf(node *yaml.Node, allowed ...string) (string, bool) {
+	if node.Kind != yaml.MappingNode {
+		return "", false
+	}
+	fields := map[string]string{}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i].Value, node.Content[i+1]
+		if value.Kind != yaml.ScalarNode || strings.Contains(value.Value, ":") {
+			return "", false
+		}
+		fields[key] = value.Value
+	}
+	for key := range fields {
+		found := false
+		for _, a := range allowed {
+			found = found || a == key
+		}
+		if !found {
+			return "", false
+		}
+	}
+	c := Coordinates{GroupID: fields["groupId"], ArtifactID: fields["artifactId"], Type: fields["type"],
+		Classifier: fields["classifier"], Version: fields["version"]}
+	if c.GroupID == "" || c.ArtifactID == "" || c.Version == "" {
+		return "", false
+	}
+	if parsed, err := ParseCoordinates(c.String()); err != nil || parsed != c {
+		return "", false
+	}
+	return c.String(), true
+}
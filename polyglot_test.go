@@ -0,0 +1,127 @@
+package gopom
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const polyglotPom = `modelVersion: 4.0.0
+parent: com.example:parent:1.0
+id: com.example:app:1.1-SNAPSHOT
+packaging: jar
+name: App
+properties:
+  java.version: "11"
+dependencyManagement:
+  dependencies:
+    - groupId: org.junit
+      artifactId: junit-bom
+      version: 5.7.0
+      type: pom
+      scope: import
+dependencies:
+  - org.slf4j:slf4j-api:1.7.30
+  - com.example:model:test-jar:tests:1.1-SNAPSHOT
+  - org.junit.jupiter:junit-jupiter
+  - groupId: org.mockito
+    artifactId: mockito-core
+    version: 3.6.0
+    scope: test
+build:
+  plugins:
+    - org.apache.maven.plugins:maven-jar-plugin:3.2.0
+    - artifactId: maven-compiler-plugin
+      version: 3.8.1
+      configuration:
+        release: 11
+        id: not:a:shorthand
+      executions:
+        - id: default-compile
+          phase: compile
+profiles:
+  - id: release
+    dependencies:
+      - com.example:signer:2.0
+`
+
+const polyglotEquivalent = `<project>
+  <modelVersion>4.0.0</modelVersion>
+  <parent><groupId>com.example</groupId><artifactId>parent</artifactId><version>1.0</version></parent>
+  <groupId>com.example</groupId>
+  <artifactId>app</artifactId>
+  <version>1.1-SNAPSHOT</version>
+  <packaging>jar</packaging>
+  <name>App</name>
+  <properties><java.version>11</java.version></properties>
+  <dependencyManagement><dependencies>
+    <dependency><groupId>org.junit</groupId><artifactId>junit-bom</artifactId><version>5.7.0</version><type>pom</type><scope>import</scope></dependency>
+  </dependencies></dependencyManagement>
+  <dependencies>
+    <dependency><groupId>org.slf4j</groupId><artifactId>slf4j-api</artifactId><version>1.7.30</version></dependency>
+    <dependency><groupId>com.example</groupId><artifactId>model</artifactId><version>1.1-SNAPSHOT</version><type>test-jar</type><classifier>tests</classifier></dependency>
+    <dependency><groupId>org.junit.jupiter</groupId><artifactId>junit-jupiter</artifactId></dependency>
+    <dependency><groupId>org.mockito</groupId><artifactId>mockito-core</artifactId><version>3.6.0</version><scope>test</scope></dependency>
+  </dependencies>
+  <build><plugins>
+    <plugin><groupId>org.apache.maven.plugins</groupId><artifactId>maven-jar-plugin</artifactId><version>3.2.0</version></plugin>
+    <plugin>
+      <artifactId>maven-compiler-plugin</artifactId><version>3.8.1</version>
+      <configuration><release>11</release><id>not:a:shorthand</id></configuration>
+      <executions><execution><id>default-compile</id><phase>compile</phase></execution></executions>
+    </plugin>
+  </plugins></build>
+  <profiles><profile><id>release</id><dependencies>
+    <dependency><groupId>com.example</groupId><artifactId>signer</artifactId><version>2.0</version></dependency>
+  </dependencies></profile></profiles>
+</project>`
+
+func Test_ParsePolyglotYAML(t *testing.T) {
+	project, err := ParsePolyglotYAML(strings.NewReader(polyglotPom))
+	assert.Nil(t, err)
+	expected, err := ParseFromReader(strings.NewReader(polyglotEquivalent))
+	assert.Nil(t, err)
+	assert.Equal(t, expected, project)
+}
+
+func Test_WritePolyglotYAML(t *testing.T) {
+	project, err := ParseFromReader(strings.NewReader(polyglotEquivalent))
+	assert.Nil(t, err)
+
+	var buf bytes.Buffer
+	assert.Nil(t, project.WritePolyglotYAML(&buf))
+	yml := buf.String()
+	assert.True(t, strings.HasPrefix(yml, "modelVersion: 4.0.0\nparent: com.example:parent:1.0\nid: com.example:app:1.1-SNAPSHOT\npackaging: jar\n"), yml)
+	assert.Contains(t, yml, `dependencies:
+  - org.slf4j:slf4j-api:1.7.30
+  - com.example:model:test-jar:tests:1.1-SNAPSHOT
+  - groupId: org.junit.jupiter
+    artifactId: junit-jupiter
+  - groupId: org.mockito
+`)
+
+	decoded, err := ParsePolyglotYAML(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, project, decoded)
+}
+
+func Test_ParsePolyglotYAMLErrors(t *testing.T) {
+	_, err := ParsePolyglotYAML(strings.NewReader("dependencies:\n  - junit\n"))
+	assert.EqualError(t, err, `line 2: invalid coordinates "junit", expected groupId:artifactId[:extension[:classifier]]:version`)
+
+	_, err = ParsePolyglotYAML(strings.NewReader("- a\n"))
+	assert.EqualError(t, err, "pom.yml must contain a mapping")
+}
+
+func Test_ParseYAMLFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pom.yml")
+	assert.Nil(t, ioutil.WriteFile(path, []byte(polyglotPom), 0644))
+
+	project, err := Parse(path)
+	assert.Nil(t, err)
+	assert.Equal(t, "app", *project.ArtifactID)
+}
@@ -0,0 +1,232 @@
+package gopom
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// MavenCentralURL is the repository Maven uses when no other one is declared.
+const MavenCentralURL = "https://repo.maven.apache.org/maven2"
+
+// PackageURL is a pkg:maven package URL as defined by the purl specification.
+type PackageURL struct {
+	Coordinates
+	// RepositoryURL is the repository the artifact is downloaded from. It is
+	// empty for Maven Central, which package URLs imply.
+	RepositoryURL string
+}
+
+// String formats the package URL. The type qualifier is left out for jar,
+// and qualifiers are sorted by name as the specification requires.
+func (u PackageURL) String() string {
+	purl := "pkg:maven/" + purlEscape(u.GroupID, false) + "/" + purlEscape(u.ArtifactID, false)
+	if u.Version != "" {
+		purl += "@" + purlEscape(u.Version, false)
+	}
+	var qualifiers []string
+	if u.Classifier != "" {
+		qualifiers = append(qualifiers, "classifier="+purlEscape(u.Classifier, true))
+	}
+	if u.RepositoryURL != "" && !isMavenCentral(u.RepositoryURL) {
+		qualifiers = append(qualifiers, "repository_url="+purlEscape(u.RepositoryURL, true))
+	}
+	if u.Type != "" && u.Type != "jar" {
+		qualifiers = append(qualifiers, "type="+purlEscape(u.Type, true))
+	}
+	if len(qualifiers) > 0 {
+		purl += "?" + strings.Join(qualifiers, "&")
+	}
+	return purl
+}
+
+// ParsePackageURL parses a pkg:maven package URL.
+func ParsePackageURL(s string) (PackageURL, error) {
+	invalid := func(reason string) (PackageURL, error) {
+		return PackageURL{}, fmt.Errorf("invalid package URL %q: %s", s, reason)
+	}
+	rest := strings.TrimSpace(s)
+	if len(rest) < 4 || !strings.EqualFold(rest[:4], "pkg:") {
+		return invalid("scheme must be pkg")
+	}
+	rest = strings.TrimLeft(rest[4:], "/")
+	if i := strings.IndexByte(rest, '#'); i >= 0 {
+		rest = rest[:i]
+	}
+
+	var u PackageURL
+	if i := strings.IndexByte(rest, '?'); i >= 0 {
+		for _, q := range strings.Split(rest[i+1:], "&") {
+			if q == "" {
+				continue
+			}
+			kv := strings.SplitN(q, "=", 2)
+			if len(kv) != 2 {
+				return invalid(fmt.Sprintf("qualifier %q has no value", q))
+			}
+			value, err := url.PathUnescape(kv[1])
+			if err != nil {
+				return invalid(err.Error())
+			}
+			switch strings.ToLower(kv[0]) {
+			case "type":
+				u.Type = value
+			case "classifier":
+				u.Classifier = value
+			case "repository_url":
+				u.RepositoryURL = value
+			}
+		}
+		rest = rest[:i]
+	}
+	if i := strings.LastIndexByte(rest, '@'); i >= 0 {
+		version, err := url.PathUnescape(rest[i+1:])
+		if err != nil {
+			return invalid(err.Error())
+		}
+		u.Version = version
+		rest = rest[:i]
+	}
+
+	parts := strings.Split(strings.Trim(rest, "/"), "/")
+	if !strings.EqualFold(parts[0], "maven") {
+		return invalid("type must be maven")
+	}
+	if len(parts) != 3 || parts[1] == "" || parts[2] == "" {
+		return invalid("expected pkg:maven/groupId/artifactId")
+	}
+	var err error
+	if u.GroupID, err = url.PathUnescape(parts[1]); err != nil {
+		return invalid(err.Error())
+	}
+	if u.ArtifactID, err = url.PathUnescape(parts[2]); err != nil {
+		return invalid(err.Error())
+	}
+	if u.Type == "jar" {
+		u.Type = ""
+	}
+	if isMavenCentral(u.RepositoryURL) {
+		u.RepositoryURL = ""
+	}
+	return u, nil
+}
+
+// purlEscape percent-encodes everything but the unreserved characters of RFC
+// 3986 and the colon, which the specification leaves as is. Slashes are kept
+// in qualifier values.
+func purlEscape(s string, qualifier bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+			strings.IndexByte("-._~:", c) >= 0 || qualifier && c == '/' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func isMavenCentral(repositoryURL string) bool {
+	u := strings.TrimSuffix(strings.TrimSpace(repositoryURL), "/")
+	u = strings.TrimPrefix(strings.TrimPrefix(u, "https://"), "http://")
+	return u == "repo.maven.apache.org/maven2" || u == "repo1.maven.org/maven2"
+}
+
+// PackageURL returns the package URL of the coordinates.
+func (c Coordinates) PackageURL() PackageURL {
+	return PackageURL{Coordinates: c}
+}
+
+// PackageURL returns the package URL of the dependency.
+func (d Dependency) PackageURL() PackageURL {
+	return d.Coordinates().PackageURL()
+}
+
+// PackageURL returns the package URL of the plugin.
+func (p Plugin) PackageURL() PackageURL {
+	return p.Coordinates().PackageURL()
+}
+
+// PackageURL returns the package URL of the build extension.
+func (e Extension) PackageURL() PackageURL {
+	return e.Coordinates().PackageURL()
+}
+
+// PackageURL returns the package URL of the parent pom.
+func (p Parent) PackageURL() PackageURL {
+	return p.Coordinates().PackageURL()
+}
+
+// PackageURL returns the package URL of the project.
+func (p *Project) PackageURL() PackageURL {
+	return p.Coordinates().PackageURL()
+}
+
+// PackageURLs returns the package URLs of the parent, dependencies, managed
+// dependencies, build plugins and build extensions of the project, sorted and
+// with properties interpolated. The repository URL is the first repository
+// the project declares besides Maven Central, taken from the plugin
+// repositories for plugins and extensions.
+func (p *Project) PackageURLs() []PackageURL {
+	var repository, pluginRepository string
+	if p.Repositories != nil {
+		for _, r := range *p.Repositories {
+			if !isEmpty(r.URL) && !isMavenCentral(*r.URL) {
+				repository = p.Interpolate(*r.URL)
+				break
+			}
+		}
+	}
+	if p.PluginRepositories != nil {
+		for _, r := range *p.PluginRepositories {
+			if !isEmpty(r.URL) && !isMavenCentral(*r.URL) {
+				pluginRepository = p.Interpolate(*r.URL)
+				break
+			}
+		}
+	}
+
+	seen := map[string]bool{}
+	var purls []PackageURL
+	add := func(c Coordinates, repositoryURL string) {
+		c.GroupID = p.Interpolate(c.GroupID)
+		c.ArtifactID = p.Interpolate(c.ArtifactID)
+		c.Version = p.Interpolate(c.Version)
+		u := PackageURL{Coordinates: c, RepositoryURL: repositoryURL}
+		if !seen[u.String()] {
+			seen[u.String()] = true
+			purls = append(purls, u)
+		}
+	}
+
+	if p.Parent != nil {
+		add(p.Parent.Coordinates(), repository)
+	}
+	var dependencies []Dependency
+	if p.Dependencies != nil {
+		dependencies = append(dependencies, *p.Dependencies...)
+	}
+	if p.DependencyManagement != nil && p.DependencyManagement.Dependencies != nil {
+		dependencies = append(dependencies, *p.DependencyManagement.Dependencies...)
+	}
+	for _, d := range dependencies {
+		add(d.Coordinates(), repository)
+	}
+	if p.Build != nil {
+		if p.Build.Plugins != nil {
+			for _, plugin := range *p.Build.Plugins {
+				add(plugin.Coordinates(), pluginRepository)
+			}
+		}
+		if p.Build.Extensions != nil {
+			for _, e := range *p.Build.Extensions {
+				add(e.Coordinates(), pluginRepository)
+			}
+		}
+	}
+	sort.Slice(purls, func(i, j int) bool { return purls[i].String() < purls[j].String() })
+	return purls
+}
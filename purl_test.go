@@ -0,0 +1,109 @@
+package gopom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PackageURLString(t *testing.T) {
+	tests := map[string]PackageURL{
+		"pkg:maven/org.acme/lib@1.0": {Coordinates: Coordinates{GroupID: "org.acme", ArtifactID: "lib", Type: "jar", Version: "1.0"}},
+		"pkg:maven/org.acme/lib":     {Coordinates: Coordinates{GroupID: "org.acme", ArtifactID: "lib"}},
+		"pkg:maven/org.acme/lib@1.0?classifier=dist&repository_url=https://repo.acme.org/maven2&type=zip": {
+			Coordinates:   Coordinates{GroupID: "org.acme", ArtifactID: "lib", Type: "zip", Classifier: "dist", Version: "1.0"},
+			RepositoryURL: "https://repo.acme.org/maven2",
+		},
+		"pkg:maven/org.acme/lib@1.0%2Bbuild%201?type=pom": {
+			Coordinates:   Coordinates{GroupID: "org.acme", ArtifactID: "lib", Type: "pom", Version: "1.0+build 1"},
+			RepositoryURL: "https://repo1.maven.org/maven2/",
+		},
+	}
+	for expected, u := range tests {
+		assert.Equal(t, expected, u.String())
+	}
+}
+
+func Test_ParsePackageURL(t *testing.T) {
+	u, err := ParsePackageURL("pkg:maven/org.acme/lib@1.0%2Bbuild?type=zip&Classifier=dist&repository_url=repo.acme.org%2Fmaven2&foo=bar#sub/path")
+	assert.Nil(t, err)
+	assert.Equal(t, PackageURL{
+		Coordinates:   Coordinates{GroupID: "org.acme", ArtifactID: "lib", Type: "zip", Classifier: "dist", Version: "1.0+build"},
+		RepositoryURL: "repo.acme.org/maven2",
+	}, u)
+
+	u, err = ParsePackageURL("PKG://Maven/org.acme/lib@2?type=jar&repository_url=https://repo.maven.apache.org/maven2")
+	assert.Nil(t, err)
+	assert.Equal(t, PackageURL{Coordinates: Coordinates{GroupID: "org.acme", ArtifactID: "lib", Version: "2"}}, u)
+
+	round := "pkg:maven/org.acme/lib@1.0?classifier=tests&repository_url=https://repo.acme.org/maven2&type=test-jar"
+	u, err = ParsePackageURL(round)
+	assert.Nil(t, err)
+	assert.Equal(t, round, u.String())
+
+	for s, message := range map[string]string{
+		"maven/org.acme/lib@1":          "scheme must be pkg",
+		"pkg:npm/left-pad@1":            "type must be maven",
+		"pkg:maven/lib@1":               "expected pkg:maven/groupId/artifactId",
+		"pkg:maven/org/acme/lib@1":      "expected pkg:maven/groupId/artifactId",
+		"pkg:maven/org.acme/lib@1?type": `qualifier "type" has no value`,
+	} {
+		_, err := ParsePackageURL(s)
+		assert.EqualError(t, err, "invalid package URL \""+s+"\": "+message)
+	}
+}
+
+func Test_ModelPackageURLs(t *testing.T) {
+	p, err := ParseFromReader(strings.NewReader(`<project>
+  <parent><groupId>org.acme</groupId><artifactId>parent</artifactId><version>1</version></parent>
+  <artifactId>app</artifactId>
+  <packaging>pom</packaging>
+  <properties><lib.version>2.0</lib.version></properties>
+  <repositories>
+    <repository><id>central</id><url>https://repo.maven.apache.org/maven2</url></repository>
+    <repository><id>acme</id><url>https://repo.acme.org/releases</url></repository>
+  </repositories>
+  <pluginRepositories>
+    <pluginRepository><id>acme-plugins</id><url>https://repo.acme.org/plugins</url></pluginRepository>
+  </pluginRepositories>
+  <dependencyManagement><dependencies>
+    <dependency><groupId>org.acme</groupId><artifactId>lib</artifactId><version>${lib.version}</version><classifier>jdk8</classifier></dependency>
+  </dependencies></dependencyManagement>
+  <dependencies>
+    <dependency><groupId>org.acme</groupId><artifactId>lib</artifactId><version>${lib.version}</version><type>test-jar</type></dependency>
+  </dependencies>
+  <build>
+    <plugins><plugin><artifactId>maven-compiler-plugin</artifactId><version>3.11.0</version></plugin></plugins>
+    <extensions><extension><groupId>kr.motd.maven</groupId><artifactId>os-maven-plugin</artifactId><version>1.7.1</version></extension></extensions>
+  </build>
+</project>`))
+	assert.Nil(t, err)
+
+	assert.Equal(t, "pkg:maven/org.acme/app@1?type=pom", p.PackageURL().String())
+	for packaging, purl := range map[string]string{
+		"war":          "pkg:maven/org.acme/app@1?type=war",
+		"ear":          "pkg:maven/org.acme/app@1?type=ear",
+		"maven-plugin": "pkg:maven/org.acme/app@1",
+		"bundle":       "pkg:maven/org.acme/app@1",
+	} {
+		packaging := packaging
+		assert.Equal(t, purl, (&Project{Parent: p.Parent, ArtifactID: p.ArtifactID, Packaging: &packaging}).PackageURL().String(), packaging)
+	}
+	assert.Equal(t, "pkg:maven/org.acme/parent@1?type=pom", p.Parent.PackageURL().String())
+	assert.Equal(t, "pkg:maven/org.apache.maven.plugins/maven-compiler-plugin@3.11.0", (*p.Build.Plugins)[0].PackageURL().String())
+	assert.Equal(t, "pkg:maven/kr.motd.maven/os-maven-plugin@1.7.1", (*p.Build.Extensions)[0].PackageURL().String())
+	assert.Equal(t, "pkg:maven/org.acme/lib@%24%7Blib.version%7D?type=test-jar", (*p.Dependencies)[0].PackageURL().String())
+
+	var purls []string
+	for _, u := range p.PackageURLs() {
+		purls = append(purls, u.String())
+	}
+	assert.Equal(t, []string{
+		"pkg:maven/kr.motd.maven/os-maven-plugin@1.7.1?repository_url=https://repo.acme.org/plugins",
+		"pkg:maven/org.acme/lib@2.0?classifier=jdk8&repository_url=https://repo.acme.org/releases",
+		"pkg:maven/org.acme/lib@2.0?repository_url=https://repo.acme.org/releases&type=test-jar",
+		"pkg:maven/org.acme/parent@1?repository_url=https://repo.acme.org/releases&type=pom",
+		"pkg:maven/org.apache.maven.plugins/maven-compiler-plugin@3.11.0?repository_url=https://repo.acme.org/plugins",
+	}, purls)
+}
@@ -0,0 +1,318 @@
+package gopom
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// DependencyNode is a node of a resolved dependency graph. The root is the
+// project itself and every other artifact appears once, at the version and
+// scope that won mediation.
+type DependencyNode struct {
+	Coordinates Coordinates
+	Scope       Scope
+	Optional    bool
+	Children    []*DependencyNode
+}
+
+// ResolvedDependency is an artifact of a resolved graph together with the
+// path that brought it in, starting with the project and ending with the artifact.
+type ResolvedDependency struct {
+	Coordinates Coordinates
+	Scope       Scope
+	Optional    bool
+	Path        []Coordinates
+}
+
+// Flatten lists the artifacts below the node in depth first order.
+func (n *DependencyNode) Flatten() []ResolvedDependency {
+	var result []ResolvedDependency
+	var walk func(node *DependencyNode, path []Coordinates)
+	walk = func(node *DependencyNode, path []Coordinates) {
+		path = append(path[:len(path):len(path)], node.Coordinates)
+		if len(path) > 1 {
+			result = append(result, ResolvedDependency{
+				Coordinates: node.Coordinates,
+				Scope:       node.Scope,
+				Optional:    node.Optional,
+				Path:        path,
+			})
+		}
+		for _, child := range node.Children {
+			walk(child, path)
+		}
+	}
+	walk(n, nil)
+	return result
+}
+
+// Resolver computes the transitive dependency graph of a project.
+type Resolver interface {
+	Resolve(p *Project) (*DependencyNode, error)
+}
+
+// RepositoryResolver resolves dependencies the way Maven does, from the poms
+// returned by Loader: the nearest declaration of an artifact wins and the
+// first one wins among equally near ones, the dependencyManagement of the
+// project applies to transitive dependencies, transitive test, provided and
+// optional dependencies are left out and exclusions apply to the whole
+// subtree. Artifacts whose pom does not exist are kept without dependencies.
+type RepositoryResolver struct {
+	Loader PomLoader
+}
+
+type resolveStep struct {
+	node *DependencyNode
+	// project declares the dependencies and interpolates their values.
+	project      *Project
+	dependencies []Dependency
+	exclusions   []Exclusion
+}
+
+// Resolve resolves the dependencies of the effective model of p.
+func (r RepositoryResolver) Resolve(p *Project) (*DependencyNode, error) {
+	project, err := EffectiveProject(p, r.Loader)
+	if err != nil {
+		return nil, err
+	}
+	managed := map[string]Dependency{}
+	if project.DependencyManagement != nil && project.DependencyManagement.Dependencies != nil {
+		for _, d := range *project.DependencyManagement.Dependencies {
+			managed[d.Coordinates().ManagementKey()] = d
+		}
+	}
+
+	root := &DependencyNode{Coordinates: project.Coordinates()}
+	resolved := map[string]bool{root.Coordinates.ManagementKey(): true}
+	queue := []resolveStep{{node: root, project: project}}
+	if project.Dependencies != nil {
+		queue[0].dependencies = *project.Dependencies
+	}
+
+	for len(queue) > 0 {
+		step := queue[0]
+		queue = queue[1:]
+		transitive := step.node != root
+
+		for _, d := range step.dependencies {
+			c := d.Coordinates()
+			key := c.ManagementKey()
+			if resolved[key] || excluded(c, step.exclusions) {
+				continue
+			}
+			if transitive {
+				if m, ok := managed[key]; ok {
+					if !isEmpty(m.Version) {
+						d.Version = m.Version
+					}
+					if !isEmpty(m.Scope) {
+						d.Scope = m.Scope
+					}
+					if m.Exclusions != nil {
+						exclusions := append([]Exclusion{}, *m.Exclusions...)
+						if d.Exclusions != nil {
+							exclusions = append(exclusions, *d.Exclusions...)
+						}
+						d.Exclusions = &exclusions
+					}
+				}
+			}
+			optional, err := d.IsOptional(step.project)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", c, err)
+			}
+			scope, err := d.ScopeValue(step.project)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", c, err)
+			}
+			if transitive {
+				if optional || scope != ScopeCompile && scope != ScopeRuntime {
+					continue
+				}
+				if step.node.Scope != ScopeCompile {
+					scope = step.node.Scope
+				}
+			}
+			version, err := r.version(c.Key(), deref(d.Version))
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", c.Key(), err)
+			}
+			c.Version = version
+
+			resolved[key] = true
+			node := &DependencyNode{Coordinates: c, Scope: scope, Optional: optional}
+			step.node.Children = append(step.node.Children, node)
+			if scope == ScopeSystem {
+				continue
+			}
+
+			pom, err := r.Loader.Load(c)
+			if os.IsNotExist(err) {
+				continue
+			} else if err != nil {
+				return nil, fmt.Errorf("loading %s: %v", c, err)
+			}
+			effective, err := EffectiveProject(pom, r.Loader)
+			if err != nil {
+				return nil, err
+			}
+			next := resolveStep{node: node, project: effective, exclusions: step.exclusions}
+			if d.Exclusions != nil {
+				next.exclusions = append(next.exclusions[:len(next.exclusions):len(next.exclusions)], *d.Exclusions...)
+			}
+			if effective.Dependencies != nil {
+				next.dependencies = *effective.Dependencies
+			}
+			queue = append(queue, next)
+		}
+	}
+	return root, nil
+}
+
+// version returns the version to use for a declared version, picking the
+// highest available version for ranges.
+func (r RepositoryResolver) version(key ArtifactKey, spec string) (string, error) {
+	if spec == "" {
+		return "", fmt.Errorf("no version declared or managed")
+	}
+	if !strings.HasPrefix(spec, "[") && !strings.HasPrefix(spec, "(") {
+		return spec, nil
+	}
+	lister, ok := r.Loader.(VersionLister)
+	if !ok {
+		return "", fmt.Errorf("cannot resolve version range %s without a version listing", spec)
+	}
+	versionRange, err := ParseVersionRange(spec)
+	if err != nil {
+		return "", err
+	}
+	versions, err := lister.Versions(key)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	best := ""
+	for _, v := range versions {
+		if versionRange.Contains(v) && (best == "" || CompareVersions(v, best) > 0) {
+			best = v
+		}
+	}
+	if best == "" {
+		return "", fmt.Errorf("no version available in range %s", spec)
+	}
+	return best, nil
+}
+
+func excluded(c Coordinates, exclusions []Exclusion) bool {
+	for _, e := range exclusions {
+		if wildcardMatch(derefOr(e.GroupID, "*"), c.GroupID) && wildcardMatch(derefOr(e.ArtifactID, "*"), c.ArtifactID) {
+			return true
+		}
+	}
+	return false
+}
+
+// DependencyChangeType is how a resolved artifact changed between two revisions.
+type DependencyChangeType string
+
+const (
+	DependencyAdded        DependencyChangeType = "added"
+	DependencyRemoved      DependencyChangeType = "removed"
+	DependencyUpgraded     DependencyChangeType = "upgraded"
+	DependencyDowngraded   DependencyChangeType = "downgraded"
+	DependencyScopeChanged DependencyChangeType = "scope"
+)
+
+// DependencyChange is a difference between the resolved dependencies of two
+// revisions of a project. A change of both version and scope is reported as
+// two changes.
+type DependencyChange struct {
+	// Artifact is the management key of the artifact.
+	Artifact   string               `json:"artifact"`
+	Type       DependencyChangeType `json:"type"`
+	OldVersion string               `json:"oldVersion,omitempty"`
+	NewVersion string               `json:"newVersion,omitempty"`
+	OldScope   Scope                `json:"oldScope,omitempty"`
+	NewScope   Scope                `json:"newScope,omitempty"`
+	// Path is how the artifact is reached in the new revision, or in the old
+	// one when it was removed.
+	Path []Coordinates `json:"path,omitempty"`
+}
+
+func (c DependencyChange) String() string {
+	switch c.Type {
+	case DependencyAdded:
+		return fmt.Sprintf("+ %s %s (%s)", c.Artifact, c.NewVersion, c.NewScope)
+	case DependencyRemoved:
+		return fmt.Sprintf("- %s %s (%s)", c.Artifact, c.OldVersion, c.OldScope)
+	case DependencyScopeChanged:
+		return fmt.Sprintf("~ %s scope: %s -> %s", c.Artifact, c.OldScope, c.NewScope)
+	}
+	return fmt.Sprintf("~ %s %s: %s -> %s", c.Artifact, c.Type, c.OldVersion, c.NewVersion)
+}
+
+// CompareDependencies resolves the dependencies of two revisions of a
+// project and returns how the resolved artifacts changed.
+func CompareDependencies(before, after *Project, r Resolver) ([]DependencyChange, error) {
+	a, err := r.Resolve(before)
+	if err != nil {
+		return nil, fmt.Errorf("resolving old revision: %v", err)
+	}
+	b, err := r.Resolve(after)
+	if err != nil {
+		return nil, fmt.Errorf("resolving new revision: %v", err)
+	}
+	return DiffDependencyGraphs(a, b), nil
+}
+
+// DiffDependencyGraphs compares two resolved graphs, sorted by artifact.
+func DiffDependencyGraphs(before, after *DependencyNode) []DependencyChange {
+	oldDeps := map[string]ResolvedDependency{}
+	for _, d := range before.Flatten() {
+		oldDeps[d.Coordinates.ManagementKey()] = d
+	}
+	newDeps := map[string]ResolvedDependency{}
+	for _, d := range after.Flatten() {
+		newDeps[d.Coordinates.ManagementKey()] = d
+	}
+
+	var changes []DependencyChange
+	for key, a := range oldDeps {
+		if _, ok := newDeps[key]; !ok {
+			changes = append(changes, DependencyChange{Artifact: key, Type: DependencyRemoved,
+				OldVersion: a.Coordinates.Version, OldScope: a.Scope, Path: a.Path})
+		}
+	}
+	for key, b := range newDeps {
+		a, ok := oldDeps[key]
+		if !ok {
+			changes = append(changes, DependencyChange{Artifact: key, Type: DependencyAdded,
+				NewVersion: b.Coordinates.Version, NewScope: b.Scope, Path: b.Path})
+			continue
+		}
+		change := DependencyChange{Artifact: key, OldVersion: a.Coordinates.Version, NewVersion: b.Coordinates.Version,
+			OldScope: a.Scope, NewScope: b.Scope, Path: b.Path}
+		switch c := CompareVersions(a.Coordinates.Version, b.Coordinates.Version); {
+		case c < 0:
+			change.Type = DependencyUpgraded
+			changes = append(changes, change)
+		case c > 0:
+			change.Type = DependencyDowngraded
+			changes = append(changes, change)
+		}
+		if a.Scope != b.Scope {
+			change.Type = DependencyScopeChanged
+			changes = append(changes, change)
+		}
+	}
+
+	order := map[DependencyChangeType]int{DependencyRemoved: 0, DependencyAdded: 1, DependencyUpgraded: 2, DependencyDowngraded: 2, DependencyScopeChanged: 3}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Artifact != changes[j].Artifact {
+			return changes[i].Artifact < changes[j].Artifact
+		}
+		return order[changes[i].Type] < order[changes[j].Type]
+	})
+	return changes
+}
@@ -0,0 +1,141 @@
+package gopom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func resolveString(t *testing.T, pom string) (*DependencyNode, error) {
+	repo := LocalRepository(writeRepository(t, resolvePoms))
+	p, err := ParseFromReader(strings.NewReader(pom))
+	assert.Nil(t, err)
+	return RepositoryResolver{Loader: repo}.Resolve(p)
+}
+
+func Test_Resolve(t *testing.T) {
+	root, err := resolveString(t, resolveApp)
+	assert.Nil(t, err)
+	assert.Equal(t, "org.acme:app:1", root.Coordinates.GAV())
+
+	var resolved []string
+	for _, d := range root.Flatten() {
+		resolved = append(resolved, d.Coordinates.String()+" "+string(d.Scope))
+	}
+	assert.Equal(t, []string{
+		"org.acme:lib:2.0 compile",
+		"org.acme:util:1.5 compile",
+		"org.acme:rt:1 runtime",
+		"org.acme:missing:1 compile",
+	}, resolved)
+
+	rt := root.Flatten()[2]
+	assert.Equal(t, []Coordinates{
+		{GroupID: "org.acme", ArtifactID: "app", Version: "1"},
+		{GroupID: "org.acme", ArtifactID: "lib", Version: "2.0"},
+		{GroupID: "org.acme", ArtifactID: "rt", Version: "1"},
+	}, rt.Path)
+}
+
+func Test_ResolveVersionRange(t *testing.T) {
+	root, err := resolveString(t, `<project>
+  <groupId>org.acme</groupId><artifactId>app</artifactId><version>1</version>
+  <dependencies>
+    <dependency><groupId>org.acme</groupId><artifactId>lib</artifactId><version>[2.0,)</version><scope>test</scope></dependency>
+  </dependencies>
+</project>`)
+	assert.Nil(t, err)
+	lib := root.Children[0]
+	assert.Equal(t, "3.0", lib.Coordinates.Version)
+	assert.Equal(t, ScopeTest, lib.Children[0].Scope)
+
+	_, err = resolveString(t, `<project>
+  <groupId>org.acme</groupId><artifactId>app</artifactId><version>1</version>
+  <dependencies>
+    <dependency><groupId>org.acme</groupId><artifactId>lib</artifactId><version>[4.0,)</version></dependency>
+  </dependencies>
+</project>`)
+	assert.EqualError(t, err, "org.acme:lib: no version available in range [4.0,)")
+}
+
+func Test_ResolveManagedWithoutScope(t *testing.T) {
+	root, err := resolveString(t, `<project>
+  <groupId>org.acme</groupId><artifactId>app</artifactId><version>1</version>
+  <dependencyManagement><dependencies>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><version>4.13.2</version></dependency>
+    <dependency><groupId>org.acme</groupId><artifactId>rt</artifactId><scope>compile</scope></dependency>
+  </dependencies></dependencyManagement>
+  <dependencies>
+    <dependency><groupId>org.acme</groupId><artifactId>lib</artifactId><version>2.0</version></dependency>
+  </dependencies>
+</project>`)
+	assert.Nil(t, err)
+
+	var resolved []string
+	for _, d := range root.Flatten() {
+		resolved = append(resolved, d.Coordinates.String()+" "+string(d.Scope))
+	}
+	assert.Equal(t, []string{
+		"org.acme:lib:2.0 compile",
+		"org.acme:util:1.0 compile",
+		"org.acme:rt:1 compile",
+		"org.acme:unwanted:1 compile",
+	}, resolved)
+}
+
+func Test_ResolveUnmanagedVersion(t *testing.T) {
+	_, err := resolveString(t, `<project>
+  <groupId>org.acme</groupId><artifactId>app</artifactId><version>1</version>
+  <dependencies><dependency><groupId>org.acme</groupId><artifactId>lib</artifactId></dependency></dependencies>
+</project>`)
+	assert.EqualError(t, err, "org.acme:lib: no version declared or managed")
+}
+
+type staticResolver map[*Project]*DependencyNode
+
+func (r staticResolver) Resolve(p *Project) (*DependencyNode, error) {
+	return r[p], nil
+}
+
+func Test_CompareDependencies(t *testing.T) {
+	repo := LocalRepository(writeRepository(t, resolvePoms))
+	before, err := ParseFromReader(strings.NewReader(resolveApp))
+	assert.Nil(t, err)
+	after, err := ParseFromReader(strings.NewReader(strings.Replace(
+		strings.Replace(resolveApp, "<artifactId>lib</artifactId>", "<artifactId>lib</artifactId><version>3.0</version>", 1),
+		"<artifactId>missing</artifactId><version>1</version>", "<artifactId>missing</artifactId><version>1</version><scope>test</scope>", 1)))
+	assert.Nil(t, err)
+
+	changes, err := CompareDependencies(before, after, RepositoryResolver{Loader: repo})
+	assert.Nil(t, err)
+	var lines []string
+	for _, c := range changes {
+		lines = append(lines, c.String())
+	}
+	assert.Equal(t, []string{
+		"+ org.acme:fresh:jar 1 (compile)",
+		"~ org.acme:lib:jar upgraded: 2.0 -> 3.0",
+		"~ org.acme:missing:jar scope: compile -> test",
+		"- org.acme:rt:jar 1 (runtime)",
+	}, lines)
+}
+
+func Test_DiffDependencyGraphs(t *testing.T) {
+	app := Coordinates{GroupID: "g", ArtifactID: "app", Version: "1"}
+	before := &DependencyNode{Coordinates: app, Children: []*DependencyNode{
+		{Coordinates: Coordinates{GroupID: "g", ArtifactID: "a", Version: "2.0"}, Scope: ScopeCompile},
+	}}
+	after := &DependencyNode{Coordinates: app, Children: []*DependencyNode{
+		{Coordinates: Coordinates{GroupID: "g", ArtifactID: "a", Version: "1.5"}, Scope: ScopeRuntime},
+	}}
+	a, b := &Project{}, &Project{}
+	changes, err := CompareDependencies(a, b, staticResolver{a: before, b: after})
+	assert.Nil(t, err)
+	assert.Equal(t, []DependencyChange{
+		{Artifact: "g:a:jar", Type: DependencyDowngraded, OldVersion: "2.0", NewVersion: "1.5", OldScope: ScopeCompile, NewScope: ScopeRuntime,
+			Path: []Coordinates{app, {GroupID: "g", ArtifactID: "a", Version: "1.5"}}},
+		{Artifact: "g:a:jar", Type: DependencyScopeChanged, OldVersion: "2.0", NewVersion: "1.5", OldScope: ScopeCompile, NewScope: ScopeRuntime,
+			Path: []Coordinates{app, {GroupID: "g", ArtifactID: "a", Version: "1.5"}}},
+	}, changes)
+}
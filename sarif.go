@@ -0,0 +1,128 @@
+package gopom
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// SARIFSchema is the JSON schema of the SARIF 2.1.0 logs WriteSARIF produces.
+const SARIFSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string             `json:"id"`
+	ShortDescription     sarifMessage       `json:"shortDescription"`
+	DefaultConfiguration sarifConfiguration `json:"defaultConfiguration"`
+}
+
+type sarifConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	LogicalLocations []sarifLogical        `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+	Region           *sarifRegion  `json:"region,omitempty"`
+}
+
+type sarifArtifact struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+type sarifLogical struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// sarifLevel maps a severity to a SARIF level, in which disabled is "none".
+func sarifLevel(s LintSeverity) string {
+	if s == LintOff {
+		return "none"
+	}
+	return string(s)
+}
+
+// WriteSARIF writes the findings as a SARIF 2.1.0 log for code scanning
+// tools. The source of a finding's location becomes the artifact URI, so
+// poms should be parsed WithLocations and with relative paths.
+func (f LintFindings) WriteSARIF(w io.Writer) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "gopom",
+			InformationURI: "https://github.com/vifraa/gopom",
+		}},
+		Results: []sarifResult{},
+	}
+	index := map[string]int{}
+	for i, rule := range lintRules {
+		index[rule.ID] = i
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{
+			ID:                   rule.ID,
+			ShortDescription:     sarifMessage{rule.Description},
+			DefaultConfiguration: sarifConfiguration{sarifLevel(rule.Severity)},
+		})
+	}
+	for _, finding := range f {
+		result := sarifResult{
+			RuleID:    finding.Rule,
+			RuleIndex: index[finding.Rule],
+			Level:     sarifLevel(finding.Severity),
+			Message:   sarifMessage{finding.Message},
+		}
+		if loc := finding.Location; loc != nil && loc.Source != "" {
+			result.Locations = []sarifLocation{{
+				PhysicalLocation: sarifPhysicalLocation{
+					ArtifactLocation: sarifArtifact{URI: loc.Source},
+					Region:           &sarifRegion{StartLine: loc.Line, StartColumn: loc.Column},
+				},
+				LogicalLocations: []sarifLogical{{FullyQualifiedName: finding.Path}},
+			}}
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(sarifLog{Schema: SARIFSchema, Version: "2.1.0", Runs: []sarifRun{run}})
+}
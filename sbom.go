@@ -0,0 +1,168 @@
+package gopom
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"sort"
+	"time"
+)
+
+// SBOMOptions configures the generation of software bills of materials.
+type SBOMOptions struct {
+	// Loader loads the poms of dependencies to read their description,
+	// licenses and supplier, including what they inherit. Optional.
+	Loader PomLoader
+	// Repository is searched for the artifact files of dependencies to
+	// compute their hashes. Optional.
+	Repository LocalRepository
+	// Timestamp is recorded as the creation time when set. Leave it zero
+	// for reproducible output.
+	Timestamp time.Time
+}
+
+// sbomComponent is an artifact with the metadata reported in bills of materials.
+type sbomComponent struct {
+	coordinates  Coordinates
+	scope        Scope
+	optional     bool
+	description  string
+	licenses     []License
+	organization *Organization
+	developers   []Developer
+	// hashes maps algorithm names such as "SHA-256" to hex digests.
+	hashes    map[string]string
+	dependsOn []Coordinates
+}
+
+// sbomAlgorithms are the digests computed for artifact files.
+var sbomAlgorithms = []struct {
+	name string
+	new  func() hash.Hash
+}{
+	{"SHA-1", sha1.New},
+	{"SHA-256", sha256.New},
+	{"SHA-512", sha512.New},
+}
+
+// artifactExtensions maps dependency types to the extension of their files.
+var artifactExtensions = map[string]string{
+	"test-jar":     "jar",
+	"maven-plugin": "jar",
+	"ejb":          "jar",
+	"ejb-client":   "jar",
+	"java-source":  "jar",
+	"javadoc":      "jar",
+	"bundle":       "jar",
+}
+
+func artifactExtension(c Coordinates) string {
+	if ext, ok := artifactExtensions[c.typeOrDefault()]; ok {
+		return ext
+	}
+	return c.typeOrDefault()
+}
+
+// sbomComponents collects the project and the artifacts of its resolved
+// graph, sorted by coordinates.
+func sbomComponents(p *Project, graph *DependencyNode, opts SBOMOptions) (sbomComponent, []sbomComponent, error) {
+	if graph == nil {
+		return sbomComponent{}, nil, fmt.Errorf("no dependency graph")
+	}
+	root := projectComponent(p, graph.Coordinates)
+
+	byCoordinates := map[string]*sbomComponent{}
+	var order []string
+	var walk func(node *DependencyNode, component *sbomComponent)
+	walk = func(node *DependencyNode, component *sbomComponent) {
+		for _, child := range node.Children {
+			component.dependsOn = append(component.dependsOn, child.Coordinates)
+			key := child.Coordinates.String()
+			c, seen := byCoordinates[key]
+			if !seen {
+				c = &sbomComponent{coordinates: child.Coordinates, scope: child.Scope, optional: child.Optional}
+				byCoordinates[key] = c
+				order = append(order, key)
+				walk(child, c)
+			}
+		}
+	}
+	walk(graph, &root)
+
+	sort.Strings(order)
+	components := make([]sbomComponent, 0, len(order))
+	for _, key := range order {
+		c := byCoordinates[key]
+		if err := c.load(opts); err != nil {
+			return sbomComponent{}, nil, err
+		}
+		components = append(components, *c)
+	}
+	return root, components, nil
+}
+
+func projectComponent(p *Project, c Coordinates) sbomComponent {
+	root := sbomComponent{coordinates: c, description: deref(p.Description), organization: p.Organization}
+	if p.Licenses != nil {
+		root.licenses = *p.Licenses
+	}
+	if p.Developers != nil {
+		root.developers = *p.Developers
+	}
+	return root
+}
+
+// load reads the metadata of the component from its pom and hashes its file.
+func (c *sbomComponent) load(opts SBOMOptions) error {
+	if opts.Loader != nil {
+		pom, err := opts.Loader.Load(c.coordinates)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("loading %s: %v", c.coordinates, err)
+		}
+		if err == nil {
+			effective, err := EffectiveProject(pom, opts.Loader)
+			if err != nil {
+				return err
+			}
+			loaded := projectComponent(effective, c.coordinates)
+			c.description, c.licenses, c.organization, c.developers = loaded.description, loaded.licenses, loaded.organization, loaded.developers
+		}
+	}
+	if opts.Repository != "" {
+		hashes, err := hashFile(opts.Repository.Path(c.coordinates, artifactExtension(c.coordinates)))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		c.hashes = hashes
+	}
+	return nil
+}
+
+func hashFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var writers []io.Writer
+	var hashes []hash.Hash
+	for _, alg := range sbomAlgorithms {
+		h := alg.new()
+		hashes = append(hashes, h)
+		writers = append(writers, h)
+	}
+	if _, err := io.Copy(io.MultiWriter(writers...), f); err != nil {
+		return nil, err
+	}
+	digests := map[string]string{}
+	for i, alg := range sbomAlgorithms {
+		digests[alg.name] = hex.EncodeToString(hashes[i].Sum(nil))
+	}
+	return digests, nil
+}
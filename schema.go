@@ -0,0 +1,494 @@
+package gopom
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// PomNamespace is the XML namespace of the Maven 4.0.0 pom.
+const PomNamespace = "http://maven.apache.org/POM/4.0.0"
+
+// ValidateSchema validates the pom read from reader against the structure of
+// maven-4.0.0.xsd: every element must be allowed by its parent, appear no more
+// often than the schema permits, and hold a value of the declared type. Element
+// order is not enforced. The source name is used in the reported locations.
+//
+// All violations are returned as ValidationErrors; the error is only set when
+// the input cannot be read or is not well-formed XML.
+func ValidateSchema(reader io.Reader, source string) (ValidationErrors, error) {
+	lines := &lineReader{r: reader}
+	v := &schemaValidator{
+		d:      xml.NewDecoder(lines),
+		lines:  lines,
+		source: source,
+	}
+	for {
+		offset := v.d.InputOffset()
+		tok, err := v.d.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			v.start(t, offset)
+		case xml.EndElement:
+			v.end()
+		case xml.CharData:
+			v.text(t, offset)
+		}
+	}
+	return v.errs, nil
+}
+
+// schemaFrame is an open element seen by the schema validator.
+type schemaFrame struct {
+	path   string
+	typ    string
+	loc    InputLocation
+	counts map[string]int
+	text   strings.Builder
+}
+
+type schemaValidator struct {
+	d      *xml.Decoder
+	lines  *lineReader
+	source string
+	stack  []*schemaFrame
+	errs   ValidationErrors
+}
+
+func (v *schemaValidator) location(offset int64) InputLocation {
+	line, column := v.lines.position(offset)
+	return InputLocation{Source: v.source, Line: line, Column: column}
+}
+
+func (v *schemaValidator) errorf(path string, loc InputLocation, format string, args ...interface{}) {
+	v.errs = append(v.errs, &ValidationError{Path: path, Message: fmt.Sprintf(format, args...), Location: &loc})
+}
+
+func (v *schemaValidator) start(el xml.StartElement, offset int64) {
+	name := el.Name.Local
+	f := &schemaFrame{path: name, loc: v.location(offset), counts: map[string]int{}}
+
+	if len(v.stack) == 0 {
+		f.typ = "Model"
+		if name != "project" {
+			v.errorf(name, f.loc, "is not allowed as root element, expected <project>")
+			f.typ = ""
+		} else if el.Name.Space != "" && el.Name.Space != PomNamespace {
+			v.errorf(name, f.loc, "has namespace %q, expected %q", el.Name.Space, PomNamespace)
+		}
+	} else {
+		parent := v.stack[len(v.stack)-1]
+		f.path = parent.path + "." + name
+		ct, complex := pomSchema[parent.typ]
+		switch {
+		case parent.typ == "" || parent.typ == xsAny:
+			f.typ = parent.typ
+		case !complex:
+			v.errorf(parent.path, f.loc, "must not contain child elements but has <%s>", name)
+		default:
+			e, ok := ct.element(name)
+			if !ok {
+				v.errorf(f.path, f.loc, "is not allowed in <%s>", parent.path[strings.LastIndex(parent.path, ".")+1:])
+				break
+			}
+			f.typ = e.typ
+			n := parent.counts[name]
+			parent.counts[name]++
+			if e.unbounded {
+				f.path = fmt.Sprintf("%s[%d]", f.path, n)
+			} else if n == 1 {
+				v.errorf(f.path, f.loc, "must not occur more than once")
+			}
+		}
+	}
+	v.stack = append(v.stack, f)
+
+	if ct, ok := pomSchema[f.typ]; ok {
+		for _, attr := range el.Attr {
+			if attr.Name.Space == "xmlns" || attr.Name.Local == "xmlns" || attr.Name.Space == "http://www.w3.org/2001/XMLSchema-instance" {
+				continue
+			}
+			if !ct.hasAttribute(attr.Name.Local) {
+				v.errorf(f.path, f.loc, "has attribute %s which is not allowed", attr.Name.Local)
+			}
+		}
+	}
+}
+
+func (v *schemaValidator) text(data xml.CharData, offset int64) {
+	if len(v.stack) == 0 {
+		return
+	}
+	f := v.stack[len(v.stack)-1]
+	if _, complex := pomSchema[f.typ]; complex {
+		if strings.TrimSpace(string(data)) != "" {
+			v.errorf(f.path, v.location(offset), "must not contain text")
+		}
+		return
+	}
+	f.text.Write(data)
+}
+
+func (v *schemaValidator) end() {
+	f := v.stack[len(v.stack)-1]
+	v.stack = v.stack[:len(v.stack)-1]
+
+	if f.typ == xsBoolean {
+		switch value := strings.TrimSpace(f.text.String()); value {
+		case "true", "false", "1", "0":
+		default:
+			v.errorf(f.path, f.loc, "must be a boolean but was %q", value)
+		}
+	}
+}
+
+const (
+	xsString  = "xs:string"
+	xsBoolean = "xs:boolean"
+	xsAny     = "xs:anyType"
+)
+
+// xsdType is a complex type of the pom schema.
+type xsdType struct {
+	attributes []string
+	elements   []xsdElement
+}
+
+// xsdElement is a child element of a complex type. Unbounded elements only
+// appear inside the anonymous wrapper types generated for lists.
+type xsdElement struct {
+	name      string
+	typ       string
+	unbounded bool
+}
+
+func (t *xsdType) element(name string) (xsdElement, bool) {
+	for _, e := range t.elements {
+		if e.name == name {
+			return e, true
+		}
+	}
+	return xsdElement{}, false
+}
+
+func (t *xsdType) hasAttribute(name string) bool {
+	for _, a := range t.attributes {
+		if a == name {
+			return true
+		}
+	}
+	return false
+}
+
+func complexType(attributes []string, elements ...xsdElement) *xsdType {
+	return &xsdType{attributes: attributes, elements: elements}
+}
+
+func el(name, typ string) xsdElement {
+	return xsdElement{name: name, typ: typ}
+}
+
+// list declares a wrapper element holding any number of item elements, such
+// as <licenses><license/></licenses>.
+func list(name, item, typ string) xsdElement {
+	wrapper := name + ">" + item + ":" + typ
+	pomSchema[wrapper] = complexType(nil, xsdElement{name: item, typ: typ, unbounded: true})
+	return el(name, wrapper)
+}
+
+// pomSchema holds the complex types of maven-4.0.0.xsd keyed by type name.
+// It is a transcription of the XSD, not the XSD itself, as go:embed needs a
+// newer Go than the module supports. It follows the revision published at
+// https://maven.apache.org/xsd/maven-4.0.0.xsd since Maven 3.6.1, which added
+// the child.*.inherit.append.path attributes.
+var pomSchema = map[string]*xsdType{}
+
+func init() {
+	pomSchema["Model"] = complexType([]string{"child.project.url.inherit.append.path"},
+		el("modelVersion", xsString),
+		el("parent", "Parent"),
+		el("groupId", xsString),
+		el("artifactId", xsString),
+		el("version", xsString),
+		el("packaging", xsString),
+		el("name", xsString),
+		el("description", xsString),
+		el("url", xsString),
+		el("inceptionYear", xsString),
+		el("organization", "Organization"),
+		list("licenses", "license", "License"),
+		list("developers", "developer", "Developer"),
+		list("contributors", "contributor", "Contributor"),
+		list("mailingLists", "mailingList", "MailingList"),
+		el("prerequisites", "Prerequisites"),
+		list("modules", "module", xsString),
+		el("scm", "Scm"),
+		el("issueManagement", "IssueManagement"),
+		el("ciManagement", "CiManagement"),
+		el("distributionManagement", "DistributionManagement"),
+		el("properties", xsAny),
+		el("dependencyManagement", "DependencyManagement"),
+		list("dependencies", "dependency", "Dependency"),
+		list("repositories", "repository", "Repository"),
+		list("pluginRepositories", "pluginRepository", "Repository"),
+		el("build", "Build"),
+		el("reports", xsAny),
+		el("reporting", "Reporting"),
+		list("profiles", "profile", "Profile"),
+	)
+	pomSchema["Parent"] = complexType(nil,
+		el("groupId", xsString),
+		el("artifactId", xsString),
+		el("version", xsString),
+		el("relativePath", xsString),
+	)
+	pomSchema["Organization"] = complexType(nil,
+		el("name", xsString),
+		el("url", xsString),
+	)
+	pomSchema["License"] = complexType(nil,
+		el("name", xsString),
+		el("url", xsString),
+		el("distribution", xsString),
+		el("comments", xsString),
+	)
+	pomSchema["Developer"] = complexType(nil,
+		el("id", xsString),
+		el("name", xsString),
+		el("email", xsString),
+		el("url", xsString),
+		el("organization", xsString),
+		el("organizationUrl", xsString),
+		list("roles", "role", xsString),
+		el("timezone", xsString),
+		el("properties", xsAny),
+	)
+	pomSchema["Contributor"] = complexType(nil,
+		el("name", xsString),
+		el("email", xsString),
+		el("url", xsString),
+		el("organization", xsString),
+		el("organizationUrl", xsString),
+		list("roles", "role", xsString),
+		el("timezone", xsString),
+		el("properties", xsAny),
+	)
+	pomSchema["MailingList"] = complexType(nil,
+		el("name", xsString),
+		el("subscribe", xsString),
+		el("unsubscribe", xsString),
+		el("post", xsString),
+		el("archive", xsString),
+		list("otherArchives", "otherArchive", xsString),
+	)
+	pomSchema["Prerequisites"] = complexType(nil,
+		el("maven", xsString),
+	)
+	pomSchema["Scm"] = complexType([]string{
+		"child.scm.connection.inherit.append.path",
+		"child.scm.developerConnection.inherit.append.path",
+		"child.scm.url.inherit.append.path",
+	},
+		el("connection", xsString),
+		el("developerConnection", xsString),
+		el("tag", xsString),
+		el("url", xsString),
+	)
+	pomSchema["IssueManagement"] = complexType(nil,
+		el("system", xsString),
+		el("url", xsString),
+	)
+	pomSchema["CiManagement"] = complexType(nil,
+		el("system", xsString),
+		el("url", xsString),
+		list("notifiers", "notifier", "Notifier"),
+	)
+	pomSchema["Notifier"] = complexType(nil,
+		el("type", xsString),
+		el("sendOnError", xsBoolean),
+		el("sendOnFailure", xsBoolean),
+		el("sendOnSuccess", xsBoolean),
+		el("sendOnWarning", xsBoolean),
+		el("address", xsString),
+		el("configuration", xsAny),
+	)
+	pomSchema["DistributionManagement"] = complexType(nil,
+		el("repository", "DeploymentRepository"),
+		el("snapshotRepository", "DeploymentRepository"),
+		el("site", "Site"),
+		el("downloadUrl", xsString),
+		el("relocation", "Relocation"),
+		el("status", xsString),
+	)
+	pomSchema["DeploymentRepository"] = complexType(nil,
+		el("uniqueVersion", xsBoolean),
+		el("releases", "RepositoryPolicy"),
+		el("snapshots", "RepositoryPolicy"),
+		el("id", xsString),
+		el("name", xsString),
+		el("url", xsString),
+		el("layout", xsString),
+	)
+	pomSchema["Site"] = complexType([]string{"child.site.url.inherit.append.path"},
+		el("id", xsString),
+		el("name", xsString),
+		el("url", xsString),
+	)
+	pomSchema["Relocation"] = complexType(nil,
+		el("groupId", xsString),
+		el("artifactId", xsString),
+		el("version", xsString),
+		el("message", xsString),
+	)
+	pomSchema["DependencyManagement"] = complexType(nil,
+		list("dependencies", "dependency", "Dependency"),
+	)
+	pomSchema["Dependency"] = complexType(nil,
+		el("groupId", xsString),
+		el("artifactId", xsString),
+		el("version", xsString),
+		el("type", xsString),
+		el("classifier", xsString),
+		el("scope", xsString),
+		el("systemPath", xsString),
+		list("exclusions", "exclusion", "Exclusion"),
+		el("optional", xsString),
+	)
+	pomSchema["Exclusion"] = complexType(nil,
+		el("groupId", xsString),
+		el("artifactId", xsString),
+	)
+	pomSchema["Repository"] = complexType(nil,
+		el("releases", "RepositoryPolicy"),
+		el("snapshots", "RepositoryPolicy"),
+		el("id", xsString),
+		el("name", xsString),
+		el("url", xsString),
+		el("layout", xsString),
+	)
+	pomSchema["RepositoryPolicy"] = complexType(nil,
+		el("enabled", xsString),
+		el("updatePolicy", xsString),
+		el("checksumPolicy", xsString),
+	)
+	pomSchema["Build"] = complexType(nil,
+		el("sourceDirectory", xsString),
+		el("scriptSourceDirectory", xsString),
+		el("testSourceDirectory", xsString),
+		el("outputDirectory", xsString),
+		el("testOutputDirectory", xsString),
+		list("extensions", "extension", "Extension"),
+		el("defaultGoal", xsString),
+		list("resources", "resource", "Resource"),
+		list("testResources", "testResource", "Resource"),
+		el("directory", xsString),
+		el("finalName", xsString),
+		list("filters", "filter", xsString),
+		el("pluginManagement", "PluginManagement"),
+		list("plugins", "plugin", "Plugin"),
+	)
+	pomSchema["BuildBase"] = complexType(nil,
+		el("defaultGoal", xsString),
+		list("resources", "resource", "Resource"),
+		list("testResources", "testResource", "Resource"),
+		el("directory", xsString),
+		el("finalName", xsString),
+		list("filters", "filter", xsString),
+		el("pluginManagement", "PluginManagement"),
+		list("plugins", "plugin", "Plugin"),
+	)
+	pomSchema["Extension"] = complexType(nil,
+		el("groupId", xsString),
+		el("artifactId", xsString),
+		el("version", xsString),
+	)
+	pomSchema["Resource"] = complexType(nil,
+		el("targetPath", xsString),
+		el("filtering", xsString),
+		el("directory", xsString),
+		list("includes", "include", xsString),
+		list("excludes", "exclude", xsString),
+	)
+	pomSchema["PluginManagement"] = complexType(nil,
+		list("plugins", "plugin", "Plugin"),
+	)
+	pomSchema["Plugin"] = complexType(nil,
+		el("groupId", xsString),
+		el("artifactId", xsString),
+		el("version", xsString),
+		el("extensions", xsString),
+		list("executions", "execution", "PluginExecution"),
+		list("dependencies", "dependency", "Dependency"),
+		el("goals", xsAny),
+		el("inherited", xsString),
+		el("configuration", xsAny),
+	)
+	pomSchema["PluginExecution"] = complexType(nil,
+		el("id", xsString),
+		el("phase", xsString),
+		list("goals", "goal", xsString),
+		el("inherited", xsString),
+		el("configuration", xsAny),
+	)
+	pomSchema["Reporting"] = complexType(nil,
+		el("excludeDefaults", xsString),
+		el("outputDirectory", xsString),
+		list("plugins", "plugin", "ReportPlugin"),
+	)
+	pomSchema["ReportPlugin"] = complexType(nil,
+		el("groupId", xsString),
+		el("artifactId", xsString),
+		el("version", xsString),
+		list("reportSets", "reportSet", "ReportSet"),
+		el("inherited", xsString),
+		el("configuration", xsAny),
+	)
+	pomSchema["ReportSet"] = complexType(nil,
+		el("id", xsString),
+		list("reports", "report", xsString),
+		el("inherited", xsString),
+		el("configuration", xsAny),
+	)
+	pomSchema["Profile"] = complexType(nil,
+		el("id", xsString),
+		el("activation", "Activation"),
+		el("build", "BuildBase"),
+		list("modules", "module", xsString),
+		el("distributionManagement", "DistributionManagement"),
+		el("properties", xsAny),
+		el("dependencyManagement", "DependencyManagement"),
+		list("dependencies", "dependency", "Dependency"),
+		list("repositories", "repository", "Repository"),
+		list("pluginRepositories", "pluginRepository", "Repository"),
+		el("reports", xsAny),
+		el("reporting", "Reporting"),
+	)
+	pomSchema["Activation"] = complexType(nil,
+		el("activeByDefault", xsBoolean),
+		el("jdk", xsString),
+		el("os", "ActivationOS"),
+		el("property", "ActivationProperty"),
+		el("file", "ActivationFile"),
+	)
+	pomSchema["ActivationOS"] = complexType(nil,
+		el("name", xsString),
+		el("family", xsString),
+		el("arch", xsString),
+		el("version", xsString),
+	)
+	pomSchema["ActivationProperty"] = complexType(nil,
+		el("name", xsString),
+		el("value", xsString),
+	)
+	pomSchema["ActivationFile"] = complexType(nil,
+		el("missing", xsString),
+		el("exists", xsString),
+	)
+}
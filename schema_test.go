@@ -0,0 +1,70 @@
+package gopom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ValidateSchemaAcceptsValidPom(t *testing.T) {
+	errs, err := ValidateSchema(strings.NewReader(examplePom), "pom.xml")
+	assert.Nil(t, err)
+	assert.Empty(t, errs)
+}
+
+func Test_ValidateSchemaReportsViolations(t *testing.T) {
+	pom := `<project xmlns="http://maven.apache.org/POM/4.0.0" child.project.url.inherit.append.path="false" foo="bar">
+  <modelVersion>4.0.0</modelVersion>
+  <artifactId>a</artifactId>
+  <artifactId>b</artifactId>
+  <dependencies>
+    <dependency><groupId>g</groupId><artifactId>a</artifactId></dependency>
+    <dependency><groupId>g</groupId><artifactId>b</artifactId><scope><compile/></scope></dependency>
+    <exclusion/>
+  </dependencies>
+  <ciManagement>
+    <notifiers>
+      <notifier><sendOnError>yes</sendOnError><sendOnFailure> true </sendOnFailure></notifier>
+    </notifiers>
+  </ciManagement>
+  <build>
+    <plugins>
+      <plugin>
+        <artifactId>p</artifactId>
+        <configuration><anything><nested>ok</nested></anything></configuration>
+      </plugin>
+    </plugins>
+  </build>
+  <reporting>
+    <plugins><plugin><reportSets/></plugin></plugins>
+  </reporting>
+  stray text
+</project>`
+
+	errs, err := ValidateSchema(strings.NewReader(pom), "pom.xml")
+	assert.Nil(t, err)
+
+	var msgs []string
+	for _, e := range errs {
+		msgs = append(msgs, e.Error())
+	}
+	assert.Equal(t, []string{
+		"pom.xml:1:1: project has attribute foo which is not allowed",
+		"pom.xml:4:3: project.artifactId must not occur more than once",
+		"pom.xml:7:70: project.dependencies.dependency[1].scope must not contain child elements but has <compile>",
+		"pom.xml:8:5: project.dependencies.exclusion is not allowed in <dependencies>",
+		"pom.xml:12:17: project.ciManagement.notifiers.notifier[0].sendOnError must be a boolean but was \"yes\"",
+		"pom.xml:25:15: project must not contain text",
+	}, msgs)
+}
+
+func Test_ValidateSchemaChecksNamespace(t *testing.T) {
+	errs, err := ValidateSchema(strings.NewReader(`<project xmlns="http://example.com"/>`), "")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(errs))
+	assert.Equal(t, "project", errs[0].Path)
+
+	_, err = ValidateSchema(strings.NewReader(`<project>`), "")
+	assert.NotNil(t, err)
+}
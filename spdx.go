@@ -0,0 +1,269 @@
+package gopom
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+const spdxNoAssertion = "NOASSERTION"
+
+// SPDXDocument is an SPDX 2.3 document describing a project and its dependencies.
+type SPDXDocument struct {
+	SPDXVersion       string                   `json:"spdxVersion"`
+	DataLicense       string                   `json:"dataLicense"`
+	SPDXID            string                   `json:"SPDXID"`
+	Name              string                   `json:"name"`
+	DocumentNamespace string                   `json:"documentNamespace"`
+	CreationInfo      SPDXCreationInfo         `json:"creationInfo"`
+	Packages          []SPDXPackage            `json:"packages"`
+	Relationships     []SPDXRelationship       `json:"relationships"`
+	ExtractedLicenses []SPDXExtractedLicensing `json:"hasExtractedLicensingInfos,omitempty"`
+}
+
+type SPDXCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type SPDXPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	Supplier         string            `json:"supplier,omitempty"`
+	Originator       string            `json:"originator,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	Checksums        []SPDXChecksum    `json:"checksums,omitempty"`
+	LicenseConcluded string            `json:"licenseConcluded"`
+	LicenseDeclared  string            `json:"licenseDeclared"`
+	CopyrightText    string            `json:"copyrightText"`
+	Description      string            `json:"description,omitempty"`
+	ExternalRefs     []SPDXExternalRef `json:"externalRefs,omitempty"`
+}
+
+type SPDXChecksum struct {
+	Algorithm string `json:"algorithm"`
+	Value     string `json:"checksumValue"`
+}
+
+type SPDXExternalRef struct {
+	Category string `json:"referenceCategory"`
+	Type     string `json:"referenceType"`
+	Locator  string `json:"referenceLocator"`
+}
+
+type SPDXRelationship struct {
+	Element string `json:"spdxElementId"`
+	Type    string `json:"relationshipType"`
+	Related string `json:"relatedSpdxElement"`
+}
+
+// SPDXExtractedLicensing describes a license that has no SPDX identifier.
+type SPDXExtractedLicensing struct {
+	LicenseID     string   `json:"licenseId"`
+	ExtractedText string   `json:"extractedText"`
+	Name          string   `json:"name,omitempty"`
+	SeeAlsos      []string `json:"seeAlsos,omitempty"`
+}
+
+// NewSPDX builds an SPDX 2.3 document for the project and its resolved
+// dependency graph. Licenses are declared as the expression of
+// NormalizeLicenses, with unknown licenses described as extracted licensing
+// info. SPDX requires a creation time, so the Unix epoch is recorded when
+// SBOMOptions.Timestamp is zero.
+func NewSPDX(p *Project, graph *DependencyNode, opts SBOMOptions) (*SPDXDocument, error) {
+	root, components, err := sbomComponents(p, graph, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	created := opts.Timestamp
+	if created.IsZero() {
+		created = time.Unix(0, 0)
+	}
+	name := root.coordinates.GAV()
+	doc := &SPDXDocument{
+		SPDXVersion:  "SPDX-2.3",
+		DataLicense:  "CC0-1.0",
+		SPDXID:       "SPDXRef-DOCUMENT",
+		Name:         name,
+		CreationInfo: SPDXCreationInfo{Created: created.UTC().Format(time.RFC3339), Creators: []string{"Tool: gopom"}},
+	}
+
+	licenseRefs := map[string]bool{}
+	all := append([]sbomComponent{root}, components...)
+	namespace := sha256.New()
+	for _, c := range all {
+		pkg := SPDXPackage{
+			Name:             c.coordinates.Key().String(),
+			SPDXID:           spdxID(c.coordinates),
+			VersionInfo:      c.coordinates.Version,
+			Supplier:         spdxSupplier(c.organization),
+			Originator:       spdxOriginator(c.developers),
+			DownloadLocation: spdxNoAssertion,
+			LicenseConcluded: spdxNoAssertion,
+			LicenseDeclared:  spdxNoAssertion,
+			CopyrightText:    spdxNoAssertion,
+			Description:      c.description,
+			ExternalRefs:     []SPDXExternalRef{{Category: "PACKAGE-MANAGER", Type: "purl", Locator: c.coordinates.PackageURL().String()}},
+		}
+		for _, alg := range sbomAlgorithms {
+			if digest, ok := c.hashes[alg.name]; ok {
+				pkg.Checksums = append(pkg.Checksums, SPDXChecksum{Algorithm: strings.Replace(alg.name, "-", "", 1), Value: digest})
+			}
+		}
+		licenses := NormalizeLicenses(c.licenses)
+		if licenses.Expression != "" {
+			pkg.LicenseDeclared = licenses.Expression
+		}
+		for _, l := range licenses.Unknown {
+			if id := licenseRef(l); !licenseRefs[id] {
+				licenseRefs[id] = true
+				doc.ExtractedLicenses = append(doc.ExtractedLicenses, spdxExtractedLicense(l))
+			}
+		}
+		doc.Packages = append(doc.Packages, pkg)
+		io.WriteString(namespace, pkg.ExternalRefs[0].Locator+"\n")
+
+		for _, d := range c.dependsOn {
+			doc.Relationships = append(doc.Relationships, SPDXRelationship{Element: pkg.SPDXID, Type: "DEPENDS_ON", Related: spdxID(d)})
+		}
+	}
+	doc.DocumentNamespace = fmt.Sprintf("https://spdx.org/spdxdocs/%s-%s", spdxIDString(name), hex.EncodeToString(namespace.Sum(nil))[:16])
+
+	sort.Slice(doc.Relationships, func(i, j int) bool {
+		a, b := doc.Relationships[i], doc.Relationships[j]
+		if a.Element != b.Element {
+			return a.Element < b.Element
+		}
+		return a.Related < b.Related
+	})
+	doc.Relationships = append([]SPDXRelationship{{Element: doc.SPDXID, Type: "DESCRIBES", Related: spdxID(root.coordinates)}}, doc.Relationships...)
+	sort.Slice(doc.ExtractedLicenses, func(i, j int) bool { return doc.ExtractedLicenses[i].LicenseID < doc.ExtractedLicenses[j].LicenseID })
+	return doc, nil
+}
+
+// spdxID returns the SPDX element identifier of the artifact.
+func spdxID(c Coordinates) string {
+	return "SPDXRef-Package-" + spdxIDString(c.String())
+}
+
+// spdxIDString replaces the characters SPDX identifiers may not contain.
+func spdxIDString(s string) string {
+	return strings.Map(func(r rune) rune {
+		if 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '.' || r == '-' {
+			return r
+		}
+		return '-'
+	}, s)
+}
+
+func spdxSupplier(o *Organization) string {
+	if o == nil || isEmpty(o.Name) {
+		return ""
+	}
+	return "Organization: " + *o.Name
+}
+
+// spdxOriginator returns the first named developer as the originator.
+func spdxOriginator(developers []Developer) string {
+	for _, d := range developers {
+		if isEmpty(d.Name) {
+			continue
+		}
+		if !isEmpty(d.Email) {
+			return fmt.Sprintf("Person: %s (%s)", *d.Name, *d.Email)
+		}
+		return "Person: " + *d.Name
+	}
+	return ""
+}
+
+// spdxExtractedLicense describes a license that has no SPDX identifier by its name and URL.
+func spdxExtractedLicense(l License) SPDXExtractedLicensing {
+	name := strings.TrimSpace(deref(l.Name))
+	url := strings.TrimSpace(deref(l.URL))
+	extracted := SPDXExtractedLicensing{LicenseID: licenseRef(l), ExtractedText: name, Name: name}
+	if name == "" {
+		extracted.ExtractedText = url
+	}
+	if url != "" {
+		extracted.SeeAlsos = []string{url}
+	}
+	return extracted
+}
+
+// WriteJSON writes the document in the SPDX JSON format.
+func (d *SPDXDocument) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(d)
+}
+
+// WriteTagValue writes the document in the SPDX tag-value format.
+func (d *SPDXDocument) WriteTagValue(w io.Writer) error {
+	var b strings.Builder
+	tag := func(name, value string) {
+		if value == "" {
+			return
+		}
+		if strings.Contains(value, "\n") {
+			value = "<text>" + value + "</text>"
+		}
+		fmt.Fprintf(&b, "%s: %s\n", name, value)
+	}
+
+	tag("SPDXVersion", d.SPDXVersion)
+	tag("DataLicense", d.DataLicense)
+	tag("SPDXID", d.SPDXID)
+	tag("DocumentName", d.Name)
+	tag("DocumentNamespace", d.DocumentNamespace)
+	for _, c := range d.CreationInfo.Creators {
+		tag("Creator", c)
+	}
+	tag("Created", d.CreationInfo.Created)
+
+	for _, p := range d.Packages {
+		fmt.Fprintf(&b, "\n##### Package: %s\n\n", p.Name)
+		tag("PackageName", p.Name)
+		tag("SPDXID", p.SPDXID)
+		tag("PackageVersion", p.VersionInfo)
+		tag("PackageSupplier", p.Supplier)
+		tag("PackageOriginator", p.Originator)
+		tag("PackageDownloadLocation", p.DownloadLocation)
+		tag("FilesAnalyzed", fmt.Sprint(p.FilesAnalyzed))
+		for _, c := range p.Checksums {
+			tag("PackageChecksum", c.Algorithm+": "+c.Value)
+		}
+		tag("PackageLicenseConcluded", p.LicenseConcluded)
+		tag("PackageLicenseDeclared", p.LicenseDeclared)
+		tag("PackageCopyrightText", p.CopyrightText)
+		tag("PackageDescription", p.Description)
+		for _, r := range p.ExternalRefs {
+			tag("ExternalRef", r.Category+" "+r.Type+" "+r.Locator)
+		}
+	}
+
+	b.WriteString("\n")
+	for _, r := range d.Relationships {
+		tag("Relationship", r.Element+" "+r.Type+" "+r.Related)
+	}
+
+	for _, l := range d.ExtractedLicenses {
+		b.WriteString("\n")
+		tag("LicenseID", l.LicenseID)
+		fmt.Fprintf(&b, "ExtractedText: <text>%s</text>\n", l.ExtractedText)
+		tag("LicenseName", l.Name)
+		for _, url := range l.SeeAlsos {
+			tag("LicenseCrossReference", url)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
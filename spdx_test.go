@@ -0,0 +1,106 @@
+package gopom
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_NewSPDX(t *testing.T) {
+	p, graph, opts := sbomFixture(t)
+	doc, err := NewSPDX(p, graph, opts)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "org.acme:shop:3", doc.Name)
+	assert.Equal(t, "2024-01-02T03:04:05Z", doc.CreationInfo.Created)
+	assert.True(t, strings.HasPrefix(doc.DocumentNamespace, "https://spdx.org/spdxdocs/org.acme-shop-3-"), doc.DocumentNamespace)
+
+	var ids []string
+	for _, pkg := range doc.Packages {
+		ids = append(ids, pkg.SPDXID+" "+pkg.LicenseDeclared)
+	}
+	assert.Equal(t, []string{
+		"SPDXRef-Package-org.acme-shop-war-3 MIT",
+		"SPDXRef-Package-junit-junit-4.13 NOASSERTION",
+		"SPDXRef-Package-org.acme-core-1.0 Apache-2.0",
+		"SPDXRef-Package-org.acme-util-2 NOASSERTION",
+	}, ids)
+
+	core := doc.Packages[2]
+	assert.Equal(t, "Organization: Acme", core.Supplier)
+	assert.Equal(t, "Person: Jane Doe (jane@acme.org)", core.Originator)
+	assert.Equal(t, "SHA256", core.Checksums[1].Algorithm)
+	assert.Equal(t, SPDXExternalRef{Category: "PACKAGE-MANAGER", Type: "purl", Locator: "pkg:maven/org.acme/core@1.0"}, core.ExternalRefs[0])
+
+	assert.Equal(t, []SPDXRelationship{
+		{Element: "SPDXRef-DOCUMENT", Type: "DESCRIBES", Related: "SPDXRef-Package-org.acme-shop-war-3"},
+		{Element: "SPDXRef-Package-org.acme-core-1.0", Type: "DEPENDS_ON", Related: "SPDXRef-Package-org.acme-util-2"},
+		{Element: "SPDXRef-Package-org.acme-shop-war-3", Type: "DEPENDS_ON", Related: "SPDXRef-Package-junit-junit-4.13"},
+		{Element: "SPDXRef-Package-org.acme-shop-war-3", Type: "DEPENDS_ON", Related: "SPDXRef-Package-org.acme-core-1.0"},
+	}, doc.Relationships)
+
+	again, err := NewSPDX(p, graph, opts)
+	assert.Nil(t, err)
+	assert.Equal(t, doc.DocumentNamespace, again.DocumentNamespace)
+}
+
+func Test_SPDXUnknownLicense(t *testing.T) {
+	p, err := ParseFromReader(strings.NewReader(`<project>
+  <groupId>g</groupId><artifactId>a</artifactId><version>1</version>
+  <licenses>
+    <license><name>Acme Commercial License</name><url>https://acme.org/license</url></license>
+    <license><name>The Apache Software License, Version 2.0</name></license>
+  </licenses>
+</project>`))
+	assert.Nil(t, err)
+	doc, err := NewSPDX(p, &DependencyNode{Coordinates: p.Coordinates()}, SBOMOptions{})
+	assert.Nil(t, err)
+
+	assert.Equal(t, "1970-01-01T00:00:00Z", doc.CreationInfo.Created)
+	assert.Equal(t, "LicenseRef-Acme-Commercial-License OR Apache-2.0", doc.Packages[0].LicenseDeclared)
+	assert.Equal(t, []SPDXExtractedLicensing{{
+		LicenseID:     "LicenseRef-Acme-Commercial-License",
+		ExtractedText: "Acme Commercial License",
+		Name:          "Acme Commercial License",
+		SeeAlsos:      []string{"https://acme.org/license"},
+	}}, doc.ExtractedLicenses)
+}
+
+func Test_SPDXTagValue(t *testing.T) {
+	p, graph, opts := sbomFixture(t)
+	opts.Repository = ""
+	doc, err := NewSPDX(p, graph, opts)
+	assert.Nil(t, err)
+
+	var out bytes.Buffer
+	assert.Nil(t, doc.WriteTagValue(&out))
+	assert.True(t, strings.HasPrefix(out.String(), `SPDXVersion: SPDX-2.3
+DataLicense: CC0-1.0
+SPDXID: SPDXRef-DOCUMENT
+DocumentName: org.acme:shop:3
+`), out.String())
+	assert.Contains(t, out.String(), `
+##### Package: org.acme:core
+
+PackageName: org.acme:core
+SPDXID: SPDXRef-Package-org.acme-core-1.0
+PackageVersion: 1.0
+PackageSupplier: Organization: Acme
+PackageOriginator: Person: Jane Doe (jane@acme.org)
+PackageDownloadLocation: NOASSERTION
+FilesAnalyzed: false
+PackageLicenseConcluded: NOASSERTION
+PackageLicenseDeclared: Apache-2.0
+PackageCopyrightText: NOASSERTION
+PackageDescription: Acme core
+ExternalRef: PACKAGE-MANAGER purl pkg:maven/org.acme/core@1.0
+`)
+	assert.Contains(t, out.String(), "\nRelationship: SPDXRef-Package-org.acme-core-1.0 DEPENDS_ON SPDXRef-Package-org.acme-util-2\n")
+
+	var js bytes.Buffer
+	assert.Nil(t, doc.WriteJSON(&js))
+	assert.Contains(t, js.String(), `"spdxVersion": "SPDX-2.3"`)
+	assert.Contains(t, js.String(), `"relationshipType": "DEPENDS_ON"`)
+}
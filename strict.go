@@ -0,0 +1,105 @@
+package gopom
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// UnknownElement is an element in the input that the model does not know,
+// such as a misspelled <dependancy> or <groupID>.
+type UnknownElement struct {
+	Path     string
+	Name     string
+	Location InputLocation
+	// Suggestion is the closest valid element name, if any is close enough.
+	Suggestion string
+}
+
+func (u UnknownElement) String() string {
+	msg := fmt.Sprintf("%s: unknown element <%s> at %s", u.Location, u.Name, u.Path)
+	if u.Suggestion != "" {
+		msg += fmt.Sprintf(", did you mean <%s>?", u.Suggestion)
+	}
+	return msg
+}
+
+// UnknownElementsError is returned when parsing WithStrict finds unknown elements.
+type UnknownElementsError struct {
+	Elements []UnknownElement
+}
+
+func (e *UnknownElementsError) Error() string {
+	msgs := make([]string, len(e.Elements))
+	for i, u := range e.Elements {
+		msgs[i] = u.String()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// WithUnknownElements calls fn for every element that the model does not know.
+// Children of unknown elements and of free-form elements such as properties
+// and configuration are not reported.
+func WithUnknownElements(fn func(UnknownElement)) ParseOption {
+	return func(c *parseConfig) {
+		c.unknown = append(c.unknown, fn)
+	}
+}
+
+// WithStrict makes parsing fail with an *UnknownElementsError when the input
+// contains elements that the model does not know.
+func WithStrict() ParseOption {
+	return func(c *parseConfig) {
+		c.strict = true
+	}
+}
+
+// suggest returns the known element name closest to name, or "" when none is
+// similar enough to be a likely misspelling.
+func suggest(name string, node *modelNode) string {
+	candidates := make([]string, 0, len(node.children))
+	for c := range node.children {
+		candidates = append(candidates, c)
+	}
+	sort.Strings(candidates)
+
+	best, bestDist := "", len(name)/3+1
+	for _, c := range candidates {
+		d := levenshtein(strings.ToLower(name), strings.ToLower(c))
+		if d < bestDist {
+			best, bestDist = c, d
+		}
+	}
+	return best
+}
+
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,60 @@
+package gopom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var misspelledPom = `<project>
+  <modelVersion>4.0.0</modelVersion>
+  <groupID>com.test</groupID>
+  <dependencies>
+    <dependancy>
+      <groupId>junit</groupId>
+    </dependancy>
+  </dependencies>
+  <properties>
+    <anything>goes</anything>
+  </properties>
+  <build>
+    <plugins>
+      <plugin>
+        <configuration><whatever/></configuration>
+        <somethingElse/>
+      </plugin>
+    </plugins>
+  </build>
+</project>`
+
+func Test_ParseReportsUnknownElements(t *testing.T) {
+	var unknown []UnknownElement
+	project, err := ParseFromReader(strings.NewReader(misspelledPom),
+		WithSource("pom.xml"),
+		WithUnknownElements(func(u UnknownElement) { unknown = append(unknown, u) }))
+	assert.Nil(t, err)
+	assert.NotNil(t, project)
+
+	assert.Equal(t, []UnknownElement{
+		{Path: "project.groupID", Name: "groupID", Location: InputLocation{Source: "pom.xml", Line: 3, Column: 3}, Suggestion: "groupId"},
+		{Path: "project.dependencies.dependancy", Name: "dependancy", Location: InputLocation{Source: "pom.xml", Line: 5, Column: 5}, Suggestion: "dependency"},
+		{Path: "project.build.plugins.plugin[0].somethingElse", Name: "somethingElse", Location: InputLocation{Source: "pom.xml", Line: 16, Column: 9}},
+	}, unknown)
+}
+
+func Test_ParseStrictFails(t *testing.T) {
+	project, err := ParseFromReader(strings.NewReader(misspelledPom), WithStrict())
+	assert.Nil(t, project)
+
+	unknownErr, ok := err.(*UnknownElementsError)
+	assert.True(t, ok)
+	assert.Equal(t, 3, len(unknownErr.Elements))
+	assert.Equal(t, "3:3: unknown element <groupID> at project.groupID, did you mean <groupId>?", unknownErr.Elements[0].String())
+}
+
+func Test_ParseStrictAcceptsKnownElements(t *testing.T) {
+	project, err := ParseFromReader(strings.NewReader(examplePom), WithStrict())
+	assert.Nil(t, err)
+	assert.Equal(t, "com.test", *project.GroupID)
+}
@@ -0,0 +1,193 @@
+package gopom
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError describes a single problem found in a pom.
+type ValidationError struct {
+	Path     string
+	Message  string
+	Location *InputLocation
+}
+
+func (e *ValidationError) Error() string {
+	if e.Location != nil {
+		return fmt.Sprintf("%s: %s %s", e.Location, e.Path, e.Message)
+	}
+	return fmt.Sprintf("%s %s", e.Path, e.Message)
+}
+
+// ValidationErrors is every problem found while validating a pom.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// Validate checks that the project declares the elements Maven requires and
+// returns ValidationErrors listing every violation. When the project was parsed
+// WithLocations the errors carry the location of the offending element.
+func (p *Project) Validate() error {
+	v := validator{project: p, locations: p.Locations}
+
+	v.required("project.modelVersion", p.ModelVersion)
+	v.required("project.artifactId", p.ArtifactID)
+
+	// 4.1.0 poms may leave out the parent coordinates and inherit them from
+	// the parent found at the relative path.
+	is41 := deref(p.ModelVersion) == ModelVersion41
+	if p.Parent == nil || (!is41 && isEmpty(p.Parent.GroupID)) {
+		v.required("project.groupId", p.GroupID)
+	}
+	if p.Parent == nil || (!is41 && isEmpty(p.Parent.Version)) {
+		v.required("project.version", p.Version)
+	}
+	if p.Parent != nil && !is41 {
+		v.required("project.parent.groupId", p.Parent.GroupID)
+		v.required("project.parent.artifactId", p.Parent.ArtifactID)
+		v.required("project.parent.version", p.Parent.Version)
+	}
+
+	if deref(p.ModelVersion) == ModelVersion40 {
+		if p.Root != nil {
+			v.errorf("project.root", "is not supported in model version 4.0.0")
+		}
+		if p.Subprojects != nil {
+			v.errorf("project.subprojects", "is not supported in model version 4.0.0, use modules")
+		}
+		if path := p.only41(); path != "" {
+			v.errorf(path, "is not supported in model version 4.0.0")
+		}
+	}
+
+	if p.Dependencies != nil {
+		v.dependencies("project.dependencies.dependency", *p.Dependencies)
+	}
+	if p.DependencyManagement != nil && p.DependencyManagement.Dependencies != nil {
+		v.dependencies("project.dependencyManagement.dependencies.dependency", *p.DependencyManagement.Dependencies)
+	}
+	if p.Build != nil {
+		v.buildBase("project.build", &p.Build.BuildBase)
+	}
+
+	if p.Profiles != nil {
+		ids := map[string]bool{}
+		for i, profile := range *p.Profiles {
+			path := fmt.Sprintf("project.profiles.profile[%d]", i)
+			if profile.ID != nil {
+				if ids[*profile.ID] {
+					v.errorf(path+".id", "must be unique but found duplicate profile with id %s", *profile.ID)
+				}
+				ids[*profile.ID] = true
+			}
+			if profile.Dependencies != nil {
+				v.dependencies(path+".dependencies.dependency", *profile.Dependencies)
+			}
+			if profile.DependencyManagement != nil && profile.DependencyManagement.Dependencies != nil {
+				v.dependencies(path+".dependencyManagement.dependencies.dependency", *profile.DependencyManagement.Dependencies)
+			}
+			if profile.Build != nil {
+				v.buildBase(path+".build", profile.Build)
+			}
+		}
+	}
+
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+type validator struct {
+	project   *Project
+	locations Locations
+	errs      ValidationErrors
+}
+
+func (v *validator) errorf(path string, format string, args ...interface{}) {
+	err := &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)}
+	if loc, ok := v.locations.Lookup(path); ok {
+		err.Location = &loc
+	}
+	v.errs = append(v.errs, err)
+}
+
+func (v *validator) required(path string, value *string) {
+	if isEmpty(value) {
+		v.errorf(path, "is missing")
+	}
+}
+
+func (v *validator) dependencies(path string, deps []Dependency) {
+	seen := map[string]bool{}
+	for i, d := range deps {
+		p := fmt.Sprintf("%s[%d]", path, i)
+		v.required(p+".groupId", d.GroupID)
+		v.required(p+".artifactId", d.ArtifactID)
+		if d.Scope != nil && *d.Scope == "system" {
+			v.required(p+".systemPath", d.SystemPath)
+		}
+		if _, err := d.ScopeValue(v.project); err != nil {
+			if _, ok := err.(*UninterpolatedError); !ok {
+				v.errorf(p+".scope", "must be one of compile, provided, runtime, test, system or import but is %q", v.project.Interpolate(*d.Scope))
+			}
+		}
+
+		key := d.Coordinates().ManagementKey()
+		if seen[key] {
+			v.errorf(p, "must be unique but found duplicate declaration of dependency %s", key)
+		}
+		seen[key] = true
+	}
+}
+
+func (v *validator) buildBase(path string, b *BuildBase) {
+	if b.Plugins != nil {
+		v.plugins(path+".plugins.plugin", *b.Plugins)
+	}
+	if b.PluginManagement != nil && b.PluginManagement.Plugins != nil {
+		v.plugins(path+".pluginManagement.plugins.plugin", *b.PluginManagement.Plugins)
+	}
+}
+
+func (v *validator) plugins(path string, plugins []Plugin) {
+	seen := map[string]bool{}
+	for i, plugin := range plugins {
+		p := fmt.Sprintf("%s[%d]", path, i)
+		v.required(p+".artifactId", plugin.ArtifactID)
+
+		key := plugin.Coordinates().Key().String()
+		if seen[key] {
+			v.errorf(p, "must be unique but found duplicate declaration of plugin %s", key)
+		}
+		seen[key] = true
+
+		if plugin.Dependencies != nil {
+			v.dependencies(p+".dependencies.dependency", *plugin.Dependencies)
+		}
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func derefOr(s *string, def string) string {
+	if isEmpty(s) {
+		return def
+	}
+	return *s
+}
+
+func isEmpty(s *string) bool {
+	return s == nil || strings.TrimSpace(*s) == ""
+}
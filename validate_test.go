@@ -0,0 +1,89 @@
+package gopom
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ValidateValidProject(t *testing.T) {
+	// The example pom only uses placeholder values, of which the scope is
+	// the one Maven restricts.
+	errs := p.Validate().(ValidationErrors)
+	assert.Equal(t, 8, len(errs))
+	for _, err := range errs {
+		assert.True(t, strings.HasSuffix(err.Path, ".dependency[0].scope"), err.Path)
+		assert.Equal(t, `must be one of compile, provided, runtime, test, system or import but is "scope"`, err.Message)
+	}
+	assert.Equal(t, "project.dependencies.dependency[0].scope", errs[0].Path)
+}
+
+func Test_ValidateAcceptsClassifiedDependencies(t *testing.T) {
+	pom := `<project>
+  <modelVersion>4.0.0</modelVersion>
+  <parent>
+    <groupId>g</groupId>
+    <artifactId>parent</artifactId>
+    <version>1</version>
+  </parent>
+  <artifactId>a</artifactId>
+  <dependencies>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><scope>test</scope></dependency>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><classifier>tests</classifier></dependency>
+  </dependencies>
+</project>`
+	project, err := ParseFromReader(strings.NewReader(pom))
+	assert.Nil(t, err)
+	assert.Nil(t, project.Validate())
+}
+
+func Test_ValidateReportsMissingElements(t *testing.T) {
+	project, err := ParseFromReader(strings.NewReader(locationPom), WithLocations(), WithSource("pom.xml"))
+	assert.Nil(t, err)
+
+	err = project.Validate()
+	errs, ok := err.(ValidationErrors)
+	assert.True(t, ok)
+	assert.Equal(t, 4, len(errs))
+
+	assert.Equal(t, "project.groupId", errs[0].Path)
+	assert.Equal(t, "project.version", errs[1].Path)
+	assert.Equal(t, "project.dependencies.dependency[0].artifactId", errs[2].Path)
+	assert.Equal(t, "pom.xml:5:5: project.dependencies.dependency[0].artifactId is missing", errs[2].Error())
+	assert.Equal(t, "project.dependencies.dependency[1].artifactId", errs[3].Path)
+	assert.Equal(t, "pom.xml:1:1", errs[0].Location.String())
+}
+
+func Test_ValidateReportsDuplicates(t *testing.T) {
+	pom := `<project>
+  <modelVersion>4.0.0</modelVersion>
+  <groupId>g</groupId>
+  <artifactId>a</artifactId>
+  <version>1</version>
+  <properties><c.scope>tests</c.scope></properties>
+  <dependencies>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId></dependency>
+    <dependency><groupId>junit</groupId><artifactId>junit</artifactId><type>jar</type></dependency>
+    <dependency><groupId>g</groupId><artifactId>a</artifactId><scope>testing</scope></dependency>
+    <dependency><groupId>g</groupId><artifactId>b</artifactId><scope>${scope}</scope></dependency>
+    <dependency><groupId>g</groupId><artifactId>c</artifactId><scope>${c.scope}</scope></dependency>
+  </dependencies>
+  <build>
+    <plugins>
+      <plugin><artifactId>maven-jar-plugin</artifactId></plugin>
+      <plugin><groupId>org.apache.maven.plugins</groupId><artifactId>maven-jar-plugin</artifactId></plugin>
+    </plugins>
+  </build>
+</project>`
+	project, err := ParseFromReader(strings.NewReader(pom))
+	assert.Nil(t, err)
+
+	errs := project.Validate().(ValidationErrors)
+	assert.Equal(t, 4, len(errs))
+	assert.Equal(t, "project.dependencies.dependency[1] must be unique but found duplicate declaration of dependency junit:junit:jar", errs[0].Error())
+	assert.Equal(t, `project.dependencies.dependency[2].scope must be one of compile, provided, runtime, test, system or import but is "testing"`, errs[1].Error())
+	assert.Equal(t, `project.dependencies.dependency[4].scope must be one of compile, provided, runtime, test, system or import but is "tests"`, errs[2].Error())
+	assert.Equal(t, "project.build.plugins.plugin[1]", errs[3].Path)
+	assert.Nil(t, errs[3].Location)
+}
@@ -0,0 +1,262 @@
+package gopom
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// UninterpolatedError is returned by the typed accessors when a value still
+// contains a ${...} expression after interpolation, so it cannot be read. The
+// accessors interpolate values with the project they are given, or with none
+// when it is nil.
+type UninterpolatedError struct {
+	Value string
+}
+
+func (e *UninterpolatedError) Error() string {
+	return fmt.Sprintf("value %q contains an uninterpolated expression", e.Value)
+}
+
+// Scope is the scope of a dependency.
+type Scope string
+
+const (
+	ScopeCompile  Scope = "compile"
+	ScopeProvided Scope = "provided"
+	ScopeRuntime  Scope = "runtime"
+	ScopeTest     Scope = "test"
+	ScopeSystem   Scope = "system"
+	ScopeImport   Scope = "import"
+)
+
+func (s Scope) Valid() bool {
+	switch s {
+	case ScopeCompile, ScopeProvided, ScopeRuntime, ScopeTest, ScopeSystem, ScopeImport:
+		return true
+	}
+	return false
+}
+
+// Packaging is the packaging of a project. Build extensions can define their
+// own packagings, so Valid only reports the ones Maven knows out of the box.
+type Packaging string
+
+const (
+	PackagingPom         Packaging = "pom"
+	PackagingJar         Packaging = "jar"
+	PackagingWar         Packaging = "war"
+	PackagingEar         Packaging = "ear"
+	PackagingEjb         Packaging = "ejb"
+	PackagingRar         Packaging = "rar"
+	PackagingMavenPlugin Packaging = "maven-plugin"
+	PackagingBom         Packaging = "bom"
+)
+
+func (p Packaging) Valid() bool {
+	switch p {
+	case PackagingPom, PackagingJar, PackagingWar, PackagingEar, PackagingEjb, PackagingRar, PackagingMavenPlugin, PackagingBom:
+		return true
+	}
+	return false
+}
+
+// UpdatePolicy is how often Maven checks a repository for updates.
+type UpdatePolicy string
+
+const (
+	UpdatePolicyAlways UpdatePolicy = "always"
+	UpdatePolicyDaily  UpdatePolicy = "daily"
+	UpdatePolicyNever  UpdatePolicy = "never"
+)
+
+const updatePolicyIntervalPrefix = "interval:"
+
+// UpdatePolicyInterval returns the policy that checks for updates every given number of minutes.
+func UpdatePolicyInterval(minutes int) UpdatePolicy {
+	return UpdatePolicy(updatePolicyIntervalPrefix + strconv.Itoa(minutes))
+}
+
+func (u UpdatePolicy) Valid() bool {
+	switch u {
+	case UpdatePolicyAlways, UpdatePolicyDaily, UpdatePolicyNever:
+		return true
+	}
+	_, ok := u.Interval()
+	return ok
+}
+
+// Interval returns the interval of an interval:N policy.
+func (u UpdatePolicy) Interval() (time.Duration, bool) {
+	if !strings.HasPrefix(string(u), updatePolicyIntervalPrefix) {
+		return 0, false
+	}
+	minutes, err := strconv.Atoi(strings.TrimPrefix(string(u), updatePolicyIntervalPrefix))
+	if err != nil || minutes < 0 {
+		return 0, false
+	}
+	return time.Duration(minutes) * time.Minute, true
+}
+
+// ChecksumPolicy is what Maven does when an artifact checksum does not match.
+type ChecksumPolicy string
+
+const (
+	ChecksumPolicyFail   ChecksumPolicy = "fail"
+	ChecksumPolicyWarn   ChecksumPolicy = "warn"
+	ChecksumPolicyIgnore ChecksumPolicy = "ignore"
+)
+
+func (c ChecksumPolicy) Valid() bool {
+	switch c {
+	case ChecksumPolicyFail, ChecksumPolicyWarn, ChecksumPolicyIgnore:
+		return true
+	}
+	return false
+}
+
+// IsOptional reports whether the dependency is optional, false by default.
+func (d Dependency) IsOptional(project *Project) (bool, error) {
+	return parseBool(project, d.Optional, false)
+}
+
+// ScopeValue returns the scope of the dependency, compile by default.
+func (d Dependency) ScopeValue(project *Project) (Scope, error) {
+	s, err := value(project, d.Scope, string(ScopeCompile))
+	if err != nil {
+		return "", err
+	}
+	if scope := Scope(s); scope.Valid() {
+		return scope, nil
+	}
+	return "", fmt.Errorf("invalid scope %q", s)
+}
+
+// PackagingValue returns the packaging of the project, jar by default.
+// Packagings defined by build extensions are returned as is.
+func (p *Project) PackagingValue() (Packaging, error) {
+	s, err := value(p, p.Packaging, string(PackagingJar))
+	return Packaging(s), err
+}
+
+// IsExtensions reports whether the plugin loads Maven extensions, false by default.
+func (p Plugin) IsExtensions(project *Project) (bool, error) {
+	return parseBool(project, p.Extensions, false)
+}
+
+// IsInherited reports whether child poms inherit the plugin, true by default.
+func (p Plugin) IsInherited(project *Project) (bool, error) {
+	return parseBool(project, p.Inherited, true)
+}
+
+// IsInherited reports whether child poms inherit the execution, true by default.
+func (e PluginExecution) IsInherited(project *Project) (bool, error) {
+	return parseBool(project, e.Inherited, true)
+}
+
+// IsInherited reports whether child poms inherit the report plugin, true by default.
+func (p ReportingPlugin) IsInherited(project *Project) (bool, error) {
+	return parseBool(project, p.Inherited, true)
+}
+
+// IsInherited reports whether child poms inherit the report set, true by default.
+func (r ReportSet) IsInherited(project *Project) (bool, error) {
+	return parseBool(project, r.Inherited, true)
+}
+
+// IsFiltering reports whether properties in the resources are filtered, false by default.
+func (r Resource) IsFiltering(project *Project) (bool, error) {
+	return parseBool(project, r.Filtering, false)
+}
+
+// IsExcludeDefaults reports whether the default reports are excluded, false by default.
+func (r Reporting) IsExcludeDefaults(project *Project) (bool, error) {
+	return parseBool(project, r.ExcludeDefaults, false)
+}
+
+// IsEnabled reports whether the repository is used for this type of artifact, true by default.
+func (r RepositoryPolicy) IsEnabled(project *Project) (bool, error) {
+	return parseBool(project, r.Enabled, true)
+}
+
+// UpdatePolicyValue returns the update policy, daily by default.
+func (r RepositoryPolicy) UpdatePolicyValue(project *Project) (UpdatePolicy, error) {
+	s, err := value(project, r.UpdatePolicy, string(UpdatePolicyDaily))
+	if err != nil {
+		return "", err
+	}
+	if policy := UpdatePolicy(s); policy.Valid() {
+		return policy, nil
+	}
+	return "", fmt.Errorf("invalid update policy %q", s)
+}
+
+// ChecksumPolicyValue returns the checksum policy, warn by default.
+func (r RepositoryPolicy) ChecksumPolicyValue(project *Project) (ChecksumPolicy, error) {
+	s, err := value(project, r.ChecksumPolicy, string(ChecksumPolicyWarn))
+	if err != nil {
+		return "", err
+	}
+	if policy := ChecksumPolicy(s); policy.Valid() {
+		return policy, nil
+	}
+	return "", fmt.Errorf("invalid checksum policy %q", s)
+}
+
+// IsSendOnError reports whether the notifier is sent on errors, true by default.
+func (n Notifier) IsSendOnError() bool {
+	return boolOr(n.SendOnError, true)
+}
+
+// IsSendOnFailure reports whether the notifier is sent on failures, true by default.
+func (n Notifier) IsSendOnFailure() bool {
+	return boolOr(n.SendOnFailure, true)
+}
+
+// IsSendOnSuccess reports whether the notifier is sent on success, true by default.
+func (n Notifier) IsSendOnSuccess() bool {
+	return boolOr(n.SendOnSuccess, true)
+}
+
+// IsSendOnWarning reports whether the notifier is sent on warnings, true by default.
+func (n Notifier) IsSendOnWarning() bool {
+	return boolOr(n.SendOnWarning, true)
+}
+
+// value returns the trimmed value of s interpolated with p, or def when s is
+// not set. A nil p leaves the expressions of s unresolved.
+func value(p *Project, s *string, def string) (string, error) {
+	if isEmpty(s) {
+		return def, nil
+	}
+	v := strings.TrimSpace(*s)
+	if p != nil {
+		v = strings.TrimSpace(p.Interpolate(v))
+	}
+	if strings.Contains(v, "${") {
+		return "", &UninterpolatedError{Value: v}
+	}
+	return v, nil
+}
+
+func parseBool(p *Project, s *string, def bool) (bool, error) {
+	v, err := value(p, s, strconv.FormatBool(def))
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(v) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	return false, fmt.Errorf("invalid boolean value %q", v)
+}
+
+func boolOr(b *bool, def bool) bool {
+	if b == nil {
+		return def
+	}
+	return *b
+}
@@ -0,0 +1,126 @@
+package gopom
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func str(s string) *string {
+	return &s
+}
+
+func Test_BooleanAccessorDefaults(t *testing.T) {
+	optional, err := Dependency{}.IsOptional(nil)
+	assert.Nil(t, err)
+	assert.False(t, optional)
+
+	inherited, err := Plugin{}.IsInherited(nil)
+	assert.Nil(t, err)
+	assert.True(t, inherited)
+
+	extensions, err := Plugin{}.IsExtensions(nil)
+	assert.Nil(t, err)
+	assert.False(t, extensions)
+
+	filtering, err := Resource{}.IsFiltering(nil)
+	assert.Nil(t, err)
+	assert.False(t, filtering)
+
+	enabled, err := RepositoryPolicy{}.IsEnabled(nil)
+	assert.Nil(t, err)
+	assert.True(t, enabled)
+
+	excludeDefaults, err := Reporting{}.IsExcludeDefaults(nil)
+	assert.Nil(t, err)
+	assert.False(t, excludeDefaults)
+
+	assert.True(t, Notifier{}.IsSendOnError())
+	no := false
+	assert.False(t, Notifier{SendOnWarning: &no}.IsSendOnWarning())
+}
+
+func Test_BooleanAccessorParsing(t *testing.T) {
+	optional, err := Dependency{Optional: str(" TRUE ")}.IsOptional(nil)
+	assert.Nil(t, err)
+	assert.True(t, optional)
+
+	enabled, err := RepositoryPolicy{Enabled: str("false")}.IsEnabled(nil)
+	assert.Nil(t, err)
+	assert.False(t, enabled)
+
+	_, err = Resource{Filtering: str("yes")}.IsFiltering(nil)
+	assert.EqualError(t, err, `invalid boolean value "yes"`)
+
+	_, err = Plugin{Inherited: str("${inherit.plugins}")}.IsInherited(nil)
+	assert.IsType(t, &UninterpolatedError{}, err)
+	assert.EqualError(t, err, `value "${inherit.plugins}" contains an uninterpolated expression`)
+}
+
+func Test_ScopeValue(t *testing.T) {
+	scope, err := Dependency{}.ScopeValue(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, ScopeCompile, scope)
+
+	scope, err = Dependency{Scope: str("test")}.ScopeValue(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, ScopeTest, scope)
+
+	_, err = Dependency{Scope: str("testing")}.ScopeValue(nil)
+	assert.EqualError(t, err, `invalid scope "testing"`)
+}
+
+func Test_AccessorsInterpolate(t *testing.T) {
+	project := &Project{Properties: &Properties{Entries: map[string]string{"junit.scope": "test", "optional": "true"}}}
+
+	scope, err := Dependency{Scope: str("${junit.scope}")}.ScopeValue(project)
+	assert.Nil(t, err)
+	assert.Equal(t, ScopeTest, scope)
+
+	optional, err := Dependency{Optional: str("${optional}")}.IsOptional(project)
+	assert.Nil(t, err)
+	assert.True(t, optional)
+
+	_, err = Dependency{Scope: str("${junit.scope}")}.ScopeValue(nil)
+	assert.IsType(t, &UninterpolatedError{}, err)
+
+	_, err = Dependency{Scope: str("${missing}")}.ScopeValue(project)
+	assert.EqualError(t, err, `value "${missing}" contains an uninterpolated expression`)
+}
+
+func Test_PackagingValue(t *testing.T) {
+	packaging, err := (&Project{}).PackagingValue()
+	assert.Nil(t, err)
+	assert.Equal(t, PackagingJar, packaging)
+
+	packaging, err = (&Project{Packaging: str("bundle")}).PackagingValue()
+	assert.Nil(t, err)
+	assert.False(t, packaging.Valid())
+	assert.True(t, PackagingMavenPlugin.Valid())
+}
+
+func Test_RepositoryPolicyValues(t *testing.T) {
+	policy := RepositoryPolicy{UpdatePolicy: str("interval:90"), ChecksumPolicy: str("fail")}
+
+	update, err := policy.UpdatePolicyValue(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, UpdatePolicyInterval(90), update)
+	interval, ok := update.Interval()
+	assert.True(t, ok)
+	assert.Equal(t, 90*time.Minute, interval)
+
+	checksum, err := policy.ChecksumPolicyValue(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, ChecksumPolicyFail, checksum)
+
+	update, err = RepositoryPolicy{}.UpdatePolicyValue(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, UpdatePolicyDaily, update)
+
+	_, err = RepositoryPolicy{UpdatePolicy: str("interval:soon")}.UpdatePolicyValue(nil)
+	assert.EqualError(t, err, `invalid update policy "interval:soon"`)
+
+	_, err = RepositoryPolicy{ChecksumPolicy: str("strict")}.ChecksumPolicyValue(nil)
+	assert.EqualError(t, err, `invalid checksum policy "strict"`)
+}
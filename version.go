@@ -0,0 +1,384 @@
+package gopom
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CompareVersions compares two Maven versions the way Maven's
+// ComparableVersion does and returns -1, 0 or 1. Qualifiers are ordered
+// alpha < beta < milestone < rc < snapshot < release < sp, unknown qualifiers
+// sort after sp in lexical order, and trailing zeros are ignored so that
+// 1.0 equals 1.
+func CompareVersions(a, b string) int {
+	return parseVersion(a).compare(parseVersion(b))
+}
+
+// versionItem is a part of a parsed version: an integer, a qualifier or a
+// sub list started by a dash.
+type versionItem interface {
+	isNull() bool
+	// compare compares the item to other, which is nil when the other version
+	// has no item at this position.
+	compare(other versionItem) int
+}
+
+type intItem string
+
+type stringItem string
+
+type listItem []versionItem
+
+var versionQualifiers = []string{"alpha", "beta", "milestone", "rc", "snapshot", "", "sp"}
+
+const releaseQualifier = "5"
+
+var versionQualifierAliases = map[string]string{
+	"ga":      "",
+	"final":   "",
+	"release": "",
+	"cr":      "rc",
+}
+
+func newIntItem(s string) intItem {
+	s = strings.TrimLeft(s, "0")
+	if s == "" {
+		s = "0"
+	}
+	return intItem(s)
+}
+
+func (i intItem) isNull() bool {
+	return i == "0"
+}
+
+func (i intItem) compare(other versionItem) int {
+	switch o := other.(type) {
+	case nil:
+		if i.isNull() {
+			return 0
+		}
+		return 1
+	case intItem:
+		if len(i) != len(o) {
+			return compareInts(len(i), len(o))
+		}
+		return strings.Compare(string(i), string(o))
+	default:
+		return 1
+	}
+}
+
+func newStringItem(s string, followedByDigit bool) stringItem {
+	if followedByDigit && len(s) == 1 {
+		switch s {
+		case "a":
+			s = "alpha"
+		case "b":
+			s = "beta"
+		case "m":
+			s = "milestone"
+		}
+	}
+	if alias, ok := versionQualifierAliases[s]; ok {
+		s = alias
+	}
+	return stringItem(s)
+}
+
+func (s stringItem) comparable() string {
+	for i, q := range versionQualifiers {
+		if q == string(s) {
+			return fmt.Sprint(i)
+		}
+	}
+	return fmt.Sprintf("%d-%s", len(versionQualifiers), s)
+}
+
+func (s stringItem) isNull() bool {
+	return s.comparable() == releaseQualifier
+}
+
+func (s stringItem) compare(other versionItem) int {
+	switch o := other.(type) {
+	case nil:
+		return strings.Compare(s.comparable(), releaseQualifier)
+	case stringItem:
+		return strings.Compare(s.comparable(), o.comparable())
+	default:
+		return -1
+	}
+}
+
+func (l listItem) isNull() bool {
+	return len(l) == 0
+}
+
+func (l listItem) compare(other versionItem) int {
+	switch o := other.(type) {
+	case nil:
+		if len(l) == 0 {
+			return 0
+		}
+		return l[0].compare(nil)
+	case intItem:
+		return -1
+	case stringItem:
+		return 1
+	case listItem:
+		for i := 0; i < len(l) || i < len(o); i++ {
+			var left, right versionItem
+			if i < len(l) {
+				left = l[i]
+			}
+			if i < len(o) {
+				right = o[i]
+			}
+			var result int
+			if left == nil {
+				result = -right.compare(nil)
+			} else {
+				result = left.compare(right)
+			}
+			if result != 0 {
+				return result
+			}
+		}
+	}
+	return 0
+}
+
+// normalize removes trailing null items, stopping at the first item that is
+// neither null nor a sub list.
+func (l listItem) normalize() listItem {
+	for i := len(l) - 1; i >= 0; i-- {
+		if l[i].isNull() {
+			l = append(l[:i], l[i+1:]...)
+		} else if _, ok := l[i].(listItem); !ok {
+			break
+		}
+	}
+	return l
+}
+
+// versionList builds the nested lists of a version while it is parsed.
+type versionList struct {
+	items  []versionItem
+	parent *versionList
+	index  int
+}
+
+func parseVersion(version string) listItem {
+	version = strings.ToLower(version)
+	root := &versionList{}
+	list := root
+	isDigit := false
+	start := 0
+
+	parseItem := func(digit bool, s string) versionItem {
+		if digit {
+			return newIntItem(s)
+		}
+		return newStringItem(s, false)
+	}
+	sublist := func() {
+		child := &versionList{parent: list, index: len(list.items)}
+		list.items = append(list.items, nil)
+		list = child
+	}
+
+	for i := 0; i < len(version); i++ {
+		c := version[i]
+		switch {
+		case c == '.' || c == '-':
+			if i == start {
+				list.items = append(list.items, intItem("0"))
+			} else {
+				list.items = append(list.items, parseItem(isDigit, version[start:i]))
+			}
+			start = i + 1
+			if c == '-' {
+				sublist()
+			}
+		case c >= '0' && c <= '9':
+			if !isDigit && i > start {
+				list.items = append(list.items, newStringItem(version[start:i], true))
+				start = i
+				sublist()
+			}
+			isDigit = true
+		default:
+			if isDigit && i > start {
+				list.items = append(list.items, parseItem(true, version[start:i]))
+				start = i
+				sublist()
+			}
+			isDigit = false
+		}
+	}
+	if len(version) > start {
+		list.items = append(list.items, parseItem(isDigit, version[start:]))
+	}
+
+	for {
+		normalized := listItem(list.items).normalize()
+		if list.parent == nil {
+			return normalized
+		}
+		list.parent.items[list.index] = normalized
+		list = list.parent
+	}
+}
+
+func compareInts(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// VersionRange is a Maven version range such as [1.0,2.0), (,1.0],[1.2,) or
+// [1.5]. A plain version without brackets is treated as that exact version.
+type VersionRange struct {
+	spec         string
+	restrictions []versionRestriction
+}
+
+type versionRestriction struct {
+	lower, upper                   string
+	lowerInclusive, upperInclusive bool
+}
+
+// AnyVersion is the range containing every version.
+var AnyVersion = VersionRange{restrictions: []versionRestriction{{}}}
+
+// ParseVersionRange parses a Maven version range specification.
+func ParseVersionRange(spec string) (VersionRange, error) {
+	r := VersionRange{spec: spec}
+	s := strings.TrimSpace(spec)
+	if s == "" {
+		return r, fmt.Errorf("invalid version range %q", spec)
+	}
+	if s[0] != '[' && s[0] != '(' {
+		r.restrictions = []versionRestriction{{lower: s, upper: s, lowerInclusive: true, upperInclusive: true}}
+		return r, nil
+	}
+
+	for s != "" {
+		end := strings.IndexAny(s, "])")
+		if (s[0] != '[' && s[0] != '(') || end < 0 {
+			return VersionRange{}, fmt.Errorf("invalid version range %q", spec)
+		}
+		restriction, err := parseRestriction(s[:end+1])
+		if err != nil {
+			return VersionRange{}, fmt.Errorf("invalid version range %q: %v", spec, err)
+		}
+		r.restrictions = append(r.restrictions, restriction)
+
+		s = strings.TrimSpace(s[end+1:])
+		if strings.HasPrefix(s, ",") {
+			s = strings.TrimSpace(s[1:])
+			if s == "" {
+				return VersionRange{}, fmt.Errorf("invalid version range %q", spec)
+			}
+		}
+	}
+	return r, nil
+}
+
+func parseRestriction(s string) (versionRestriction, error) {
+	r := versionRestriction{
+		lowerInclusive: s[0] == '[',
+		upperInclusive: s[len(s)-1] == ']',
+	}
+	body := strings.TrimSpace(s[1 : len(s)-1])
+	bounds := strings.Split(body, ",")
+	switch len(bounds) {
+	case 1:
+		if !r.lowerInclusive || !r.upperInclusive || body == "" {
+			return r, fmt.Errorf("single version %s must be enclosed in []", s)
+		}
+		r.lower, r.upper = body, body
+	case 2:
+		r.lower, r.upper = strings.TrimSpace(bounds[0]), strings.TrimSpace(bounds[1])
+		if r.lower == "" && r.lowerInclusive || r.upper == "" && r.upperInclusive {
+			return r, fmt.Errorf("unbounded side of %s must be open", s)
+		}
+		if r.lower != "" && r.upper != "" && CompareVersions(r.lower, r.upper) > 0 {
+			return r, fmt.Errorf("lower bound of %s is greater than its upper bound", s)
+		}
+	default:
+		return r, fmt.Errorf("%s has more than two bounds", s)
+	}
+	return r, nil
+}
+
+func (r VersionRange) String() string {
+	return r.spec
+}
+
+// Contains reports whether version lies within the range.
+func (r VersionRange) Contains(version string) bool {
+	for _, res := range r.restrictions {
+		if res.contains(version) {
+			return true
+		}
+	}
+	return false
+}
+
+// Intersects reports whether some version lies within both ranges.
+func (r VersionRange) Intersects(other VersionRange) bool {
+	for _, a := range r.restrictions {
+		for _, b := range other.restrictions {
+			if a.intersects(b) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r versionRestriction) contains(version string) bool {
+	if r.lower != "" {
+		c := CompareVersions(version, r.lower)
+		if c < 0 || c == 0 && !r.lowerInclusive {
+			return false
+		}
+	}
+	if r.upper != "" {
+		c := CompareVersions(version, r.upper)
+		if c > 0 || c == 0 && !r.upperInclusive {
+			return false
+		}
+	}
+	return true
+}
+
+func (r versionRestriction) intersects(o versionRestriction) bool {
+	// The intersection runs from the greater lower bound to the smaller upper bound.
+	lower, lowerInclusive := r.lower, r.lowerInclusive
+	if o.lower != "" {
+		if lower == "" {
+			lower, lowerInclusive = o.lower, o.lowerInclusive
+		} else if c := CompareVersions(o.lower, lower); c > 0 || c == 0 && !o.lowerInclusive {
+			lower, lowerInclusive = o.lower, o.lowerInclusive
+		}
+	}
+	upper, upperInclusive := r.upper, r.upperInclusive
+	if o.upper != "" {
+		if upper == "" {
+			upper, upperInclusive = o.upper, o.upperInclusive
+		} else if c := CompareVersions(o.upper, upper); c < 0 || c == 0 && !o.upperInclusive {
+			upper, upperInclusive = o.upper, o.upperInclusive
+		}
+	}
+	if lower == "" || upper == "" {
+		return true
+	}
+	c := CompareVersions(lower, upper)
+	return c < 0 || c == 0 && lowerInclusive && upperInclusive
+}
@@ -0,0 +1,102 @@
+package gopom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func assertAscending(t *testing.T, versions []string) {
+	for i := range versions {
+		for j := range versions {
+			expected := compareInts(i, j)
+			assert.Equal(t, expected, CompareVersions(versions[i], versions[j]), "%s <=> %s", versions[i], versions[j])
+		}
+	}
+}
+
+func Test_CompareVersionsQualifiers(t *testing.T) {
+	assertAscending(t, []string{
+		"1-alpha2snapshot", "1-alpha2", "1-alpha-123", "1-beta-2", "1-beta123", "1-m2", "1-m11", "1-rc", "1-cr2",
+		"1-rc123", "1-SNAPSHOT", "1", "1-sp", "1-sp2", "1-sp123", "1-abc", "1-def", "1-pom-1", "1-1-snapshot",
+		"1-1", "1-2", "1-123",
+	})
+}
+
+func Test_CompareVersionsNumbers(t *testing.T) {
+	assertAscending(t, []string{
+		"2.0", "2-1", "2.0.a", "2.0.0.a", "2.0.2", "2.0.123", "2.1.0", "2.1-a", "2.1b", "2.1-c", "2.1-1",
+		"2.1.0.1", "2.2", "2.123", "11.a2", "11.a11", "11.b2", "11.b11", "11.m2", "11.m11", "11", "11.a", "11b",
+		"11c", "11m", "12345678901234567890",
+	})
+}
+
+func Test_CompareVersionsEqual(t *testing.T) {
+	for _, pair := range [][2]string{
+		{"1", "1.0.0"},
+		{"1-ga", "1"},
+		{"1.final", "1"},
+		{"1-RELEASE", "1"},
+		{"1a1", "1-alpha-1"},
+		{"1b2", "1-beta-2"},
+		{"1m3", "1-milestone-3"},
+		{"1cr", "1rc"},
+		{"1.0.0-0", "1"},
+		{"01.002", "1.2"},
+	} {
+		assert.Equal(t, 0, CompareVersions(pair[0], pair[1]), "%s == %s", pair[0], pair[1])
+	}
+}
+
+func Test_ParseVersionRange(t *testing.T) {
+	r, err := ParseVersionRange("[1.0,2.0)")
+	assert.Nil(t, err)
+	assert.True(t, r.Contains("1.0"))
+	assert.True(t, r.Contains("1.9.9"))
+	assert.False(t, r.Contains("2.0"))
+	assert.False(t, r.Contains("0.9"))
+	assert.True(t, r.Contains("2.0-SNAPSHOT"))
+	assert.Equal(t, "[1.0,2.0)", r.String())
+
+	r, err = ParseVersionRange("(,1.0],[1.2,)")
+	assert.Nil(t, err)
+	assert.True(t, r.Contains("0.1"))
+	assert.True(t, r.Contains("1.0"))
+	assert.False(t, r.Contains("1.1"))
+	assert.True(t, r.Contains("1.2"))
+	assert.True(t, r.Contains("99"))
+
+	r, err = ParseVersionRange("[1.5]")
+	assert.Nil(t, err)
+	assert.True(t, r.Contains("1.5.0"))
+	assert.False(t, r.Contains("1.5.1"))
+
+	r, err = ParseVersionRange("1.5")
+	assert.Nil(t, err)
+	assert.True(t, r.Contains("1.5"))
+	assert.False(t, r.Contains("1.6"))
+
+	assert.True(t, AnyVersion.Contains("1"))
+
+	for _, invalid := range []string{"", "[1.0", "(1.0)", "[,1.0]", "[2.0,1.0]", "[1,2,3]", "[1,2],"} {
+		_, err := ParseVersionRange(invalid)
+		assert.NotNil(t, err, invalid)
+	}
+}
+
+func Test_VersionRangeIntersects(t *testing.T) {
+	intersects := func(a, b string) bool {
+		ra, err := ParseVersionRange(a)
+		assert.Nil(t, err)
+		rb, err := ParseVersionRange(b)
+		assert.Nil(t, err)
+		return ra.Intersects(rb)
+	}
+	assert.True(t, intersects("[1.0,2.0)", "[1.5,3.0]"))
+	assert.True(t, intersects("[1.0,2.0]", "[2.0,3.0]"))
+	assert.False(t, intersects("[1.0,2.0)", "[2.0,3.0]"))
+	assert.True(t, intersects("(,1.0]", "1.0"))
+	assert.False(t, intersects("(,1.0)", "1.0"))
+	assert.True(t, intersects("(,1.0],[3,)", "[4,5]"))
+	assert.True(t, AnyVersion.Intersects(VersionRange{restrictions: []versionRestriction{{lower: "1", upper: "1", lowerInclusive: true, upperInclusive: true}}}))
+}